@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/pkg/analytics"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultAnalyticsRange = 24 * time.Hour
+	maxAnalyticsRange     = 31 * 24 * time.Hour
+)
+
+type AttemptAnalytics struct {
+	Interval analytics.Interval  `json:"interval"`
+	GroupBy  analytics.GroupBy   `json:"group_by,omitempty"`
+	Start    types.Time          `json:"start"`
+	End      types.Time          `json:"end"`
+	Data     []*analytics.Bucket `json:"data"`
+}
+
+// parseTimestamp parses a query parameter of unix timestamp in milliseconds
+func parseTimestamp(r *http.Request, name string, defaultValue time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func (api *API) GetAttemptAnalytics(w http.ResponseWriter, r *http.Request) {
+	interval := analytics.Interval(utils.DefaultIfZero(r.URL.Query().Get("interval"), string(analytics.IntervalHour)))
+	if !interval.IsValid() {
+		api.json(400, w, types.ErrorResponse{Message: fmt.Sprintf("invalid interval: %s", interval)})
+		return
+	}
+	groupBy := analytics.GroupBy(r.URL.Query().Get("group_by"))
+	if !groupBy.IsValid() {
+		api.json(400, w, types.ErrorResponse{Message: fmt.Sprintf("invalid group_by: %s", groupBy)})
+		return
+	}
+
+	now := time.Now()
+	end, err := parseTimestamp(r, "end", now)
+	if err != nil {
+		api.json(400, w, types.ErrorResponse{Message: err.Error()})
+		return
+	}
+	start, err := parseTimestamp(r, "start", end.Add(-defaultAnalyticsRange))
+	if err != nil {
+		api.json(400, w, types.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if !start.Before(end) {
+		api.json(400, w, types.ErrorResponse{Message: "start must be before end"})
+		return
+	}
+	if end.Sub(start) > maxAnalyticsRange {
+		api.json(400, w, types.ErrorResponse{Message: "time range must not exceed 31 days"})
+		return
+	}
+
+	q := query.AttemptStatsQuery{
+		Start: interval.Truncate(start),
+		End:   end,
+	}
+	if endpointId := r.URL.Query().Get("endpoint_id"); endpointId != "" {
+		q.EndpointId = &endpointId
+	}
+	if sourceId := r.URL.Query().Get("source_id"); sourceId != "" {
+		q.SourceId = &sourceId
+	}
+	if eventType := r.URL.Query().Get("event_type"); eventType != "" {
+		q.EventType = &eventType
+	}
+
+	list, err := api.db.AttemptStatsWS.List(r.Context(), &q)
+	api.assert(err)
+
+	api.json(200, w, AttemptAnalytics{
+		Interval: interval,
+		GroupBy:  groupBy,
+		Start:    types.NewTime(q.Start),
+		End:      types.NewTime(q.End),
+		Data:     analytics.Aggregate(list, interval, groupBy),
+	})
+}
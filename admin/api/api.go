@@ -13,6 +13,7 @@ import (
 	"github.com/webhookx-io/webhookx/pkg/errs"
 	"github.com/webhookx-io/webhookx/pkg/http/middlewares"
 	"github.com/webhookx-io/webhookx/pkg/http/response"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
 	"github.com/webhookx-io/webhookx/pkg/openapi"
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/utils"
@@ -27,6 +28,7 @@ type API struct {
 	dispatcher  *dispatcher.Dispatcher
 	declarative *declarative.Declarative
 	bus         eventbus.Bus
+	tail        *livetail.Tail
 	middlewares []mux.MiddlewareFunc
 }
 
@@ -36,6 +38,7 @@ type Options struct {
 	Dispatcher  *dispatcher.Dispatcher
 	Middlewares []mux.MiddlewareFunc
 	EventBus    eventbus.Bus
+	Tail        *livetail.Tail
 }
 
 func NewAPI(opts Options) *API {
@@ -45,6 +48,7 @@ func NewAPI(opts Options) *API {
 		dispatcher:  opts.Dispatcher,
 		declarative: declarative.NewDeclarative(opts.DB),
 		bus:         opts.EventBus,
+		tail:        opts.Tail,
 		middlewares: opts.Middlewares,
 	}
 }
@@ -59,6 +63,21 @@ func (api *API) query(r *http.Request, name string) string {
 	return r.URL.Query().Get(name)
 }
 
+// revealSecrets reports whether the caller asks to reveal secrets
+func (api *API) revealSecrets(r *http.Request) bool {
+	return api.query(r, "reveal_secrets") == "true"
+}
+
+// redact redacts secrets of the entities unless the caller asks to reveal secrets
+func (api *API) redact(r *http.Request, list ...interface{}) {
+	if api.revealSecrets(r) {
+		return
+	}
+	for _, entity := range list {
+		api.assert(entities.RedactSecrets(entity))
+	}
+}
+
 func (api *API) json(code int, w http.ResponseWriter, data interface{}) {
 	response.JSON(w, code, data)
 }
@@ -131,6 +150,7 @@ func (api *API) Handler() http.Handler {
 	}
 	r.Use(middlewares.PanicRecovery)
 	r.Use(api.contextMiddleware)
+	r.Use(api.secretsMiddleware)
 
 	r.HandleFunc("/", api.Index).Methods("GET")
 
@@ -170,6 +190,7 @@ func (api *API) Handler() http.Handler {
 	for _, prefix := range []string{"", "/workspaces/{workspace}"} {
 		r.HandleFunc(prefix+"/events", api.PageEvent).Methods("GET")
 		r.HandleFunc(prefix+"/events", api.CreateEvent).Methods("POST")
+		r.HandleFunc(prefix+"/events/stream", api.StreamEvents).Methods("GET")
 		r.HandleFunc(prefix+"/events/{id}", api.GetEvent).Methods("GET")
 		r.HandleFunc(prefix+"/events/{id}/retry", api.RetryEvent).Methods("POST")
 	}
@@ -179,6 +200,14 @@ func (api *API) Handler() http.Handler {
 		r.HandleFunc(prefix+"/attempts/{id}", api.GetAttempt).Methods("GET")
 	}
 
+	for _, prefix := range []string{"", "/workspaces/{workspace}"} {
+		r.HandleFunc(prefix+"/analytics/attempts", api.GetAttemptAnalytics).Methods("GET")
+	}
+
+	// plugin schemas are registered before /plugins/{id}
+	r.HandleFunc("/plugins/schemas", api.ListPluginSchemas).Methods("GET")
+	r.HandleFunc("/plugins/schemas/{name}", api.GetPluginSchema).Methods("GET")
+
 	for _, prefix := range []string{"", "/workspaces/{workspace}"} {
 		r.HandleFunc(prefix+"/plugins", api.PagePlugin).Methods("GET")
 		r.HandleFunc(prefix+"/plugins", api.CreatePlugin).Methods("POST")
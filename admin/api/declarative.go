@@ -56,6 +56,18 @@ func (api *API) Sync(w http.ResponseWriter, r *http.Request) {
 	}
 
 	wid := ucontext.GetWorkspaceID(r.Context())
+	for _, model := range cfg.Plugins {
+		if model.Global {
+			defaultWorkspace, err := api.db.Workspaces.GetDefault(r.Context())
+			api.assert(err)
+			if defaultWorkspace.ID != wid {
+				api.error(400, w, errors.New("global plugins can only be declared in the default workspace"))
+				return
+			}
+			break
+		}
+	}
+
 	err = api.declarative.Sync(wid, &cfg)
 	api.assert(err)
 
@@ -69,6 +81,13 @@ func (api *API) Dump(w http.ResponseWriter, r *http.Request) {
 		api.error(400, w, err)
 		return
 	}
+	for _, endpoint := range cfg.Endpoints {
+		api.redact(r, &endpoint.Endpoint, &endpoint.Plugins)
+	}
+	for _, source := range cfg.Sources {
+		api.redact(r, &source.Plugins)
+	}
+	api.redact(r, &cfg.Plugins)
 
 	var buf bytes.Buffer
 	encoder := yaml.NewEncoder(&buf)
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/db/query"
 	"github.com/webhookx-io/webhookx/pkg/types"
@@ -15,6 +16,10 @@ func (api *API) PageEndpoint(w http.ResponseWriter, r *http.Request) {
 	api.bindQuery(r, &q.Query)
 	list, total, err := api.db.EndpointsWS.Page(r.Context(), &q)
 	api.assert(err)
+	api.assert(api.attachHealth(r.Context(), list...))
+	for _, endpoint := range list {
+		api.redact(r, endpoint)
+	}
 
 	api.json(200, w, NewPagination(total, list))
 }
@@ -28,7 +33,9 @@ func (api *API) GetEndpoint(w http.ResponseWriter, r *http.Request) {
 		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
 		return
 	}
+	api.assert(api.attachHealth(r.Context(), endpoint))
 
+	api.redact(r, endpoint)
 	api.json(200, w, endpoint)
 }
 
@@ -40,10 +47,16 @@ func (api *API) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := endpoint.Validate(); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
 	endpoint.WorkspaceId = ucontext.GetWorkspaceID(r.Context())
 	err := api.db.EndpointsWS.Insert(r.Context(), &endpoint)
 	api.assert(err)
 
+	api.redact(r, &endpoint)
 	api.json(201, w, endpoint)
 }
 
@@ -56,19 +69,53 @@ func (api *API) UpdateEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	secrets, err := entities.CollectSecrets(endpoint)
+	api.assert(err)
 	defaults := utils.Must(utils.StructToMap(endpoint))
 	if err := ValidateRequest(r, defaults, endpoint); err != nil {
 		api.error(400, w, err)
 		return
 	}
+	api.assert(entities.RestoreSecrets(endpoint, secrets))
+
+	if err := endpoint.Validate(); err != nil {
+		api.error(400, w, err)
+		return
+	}
 
 	endpoint.ID = id
 	err = api.db.EndpointsWS.Update(r.Context(), endpoint)
 	api.assert(err)
+	api.assert(api.attachHealth(r.Context(), endpoint))
 
+	api.redact(r, endpoint)
 	api.json(200, w, endpoint)
 }
 
+// attachHealth attaches the delivery health to endpoints, endpoints without any attempts have no health.
+func (api *API) attachHealth(ctx context.Context, endpoints ...*entities.Endpoint) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	var q query.EndpointHealthQuery
+	q.IDs = make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		q.IDs = append(q.IDs, endpoint.ID)
+	}
+	list, err := api.db.EndpointHealthWS.List(ctx, &q)
+	if err != nil {
+		return err
+	}
+	healths := make(map[string]*entities.EndpointHealth, len(list))
+	for _, health := range list {
+		healths[health.ID] = health
+	}
+	for _, endpoint := range endpoints {
+		endpoint.Health = healths[endpoint.ID]
+	}
+	return nil
+}
+
 func (api *API) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
 	id := api.param(r, "id")
 	_, err := api.db.EndpointsWS.Delete(r.Context(), id)
@@ -46,6 +46,7 @@ func (api *API) CreateEvent(w http.ResponseWriter, r *http.Request) {
 
 	event.IngestedAt = types.Time{Time: time.Now()}
 	event.WorkspaceId = ucontext.GetWorkspaceID(r.Context())
+	event.SourceId = nil
 	attempts, err := api.dispatcher.Dispatch(context.WithoutCancel(r.Context()), []*entities.Event{&event})
 	api.assert(err)
 
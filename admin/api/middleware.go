@@ -2,6 +2,7 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/pkg/ucontext"
@@ -38,3 +39,14 @@ func (api *API) contextMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// secretsMiddleware rejects requests revealing secrets unless revealing is allowed
+func (api *API) secretsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.revealSecrets(r) && !api.cfg.Admin.RevealSecrets {
+			api.error(403, w, fmt.Errorf("revealing secrets is not allowed"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,5 +1,10 @@
 package api
 
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
 type Pagination[T any] struct {
 	Total int64 `json:"total"`
 	Data  []T   `json:"data"`
@@ -11,3 +16,21 @@ func NewPagination[T any](total int64, data []T) *Pagination[T] {
 		Data:  data,
 	}
 }
+
+type PluginSchema struct {
+	Name        string           `json:"name"`
+	Type        plugin.Type      `json:"type"`
+	Description string           `json:"description"`
+	Priority    int              `json:"priority"`
+	Schema      *openapi3.Schema `json:"schema"`
+}
+
+func NewPluginSchema(r *plugin.Registration) *PluginSchema {
+	return &PluginSchema{
+		Name:        r.Name,
+		Type:        r.Type,
+		Description: r.Description,
+		Priority:    r.Priority,
+		Schema:      r.Schema,
+	}
+}
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"context"
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/pkg/ucontext"
 	"github.com/webhookx-io/webhookx/utils"
 	"net/http"
 )
@@ -14,6 +18,9 @@ func (api *API) PagePlugin(w http.ResponseWriter, r *http.Request) {
 	api.bindQuery(r, &q.Query)
 	list, total, err := api.db.PluginsWS.Page(r.Context(), &q)
 	api.assert(err)
+	for _, plugin := range list {
+		api.redact(r, plugin)
+	}
 
 	api.json(200, w, NewPagination(total, list))
 }
@@ -28,6 +35,7 @@ func (api *API) GetPlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	api.redact(r, plugin)
 	api.json(200, w, plugin)
 }
 
@@ -44,12 +52,18 @@ func (api *API) CreatePlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := api.checkPluginConflicts(r.Context(), &model); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
 	p, err := model.Plugin()
 	api.assert(err)
 	model.Config = utils.Must(p.MarshalConfig())
 	err = api.db.PluginsWS.Insert(r.Context(), &model)
 	api.assert(err)
 
+	api.redact(r, &model)
 	api.json(201, w, model)
 }
 
@@ -62,29 +76,59 @@ func (api *API) UpdatePlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	secrets, err := entities.CollectSecrets(model)
+	api.assert(err)
 	defaults := utils.Must(utils.StructToMap(model))
 	if err := ValidateRequest(r, defaults, model); err != nil {
 		api.error(400, w, err)
 		return
 	}
+	api.assert(entities.RestoreSecrets(model, secrets))
 
 	if err := model.Validate(); err != nil {
 		api.error(400, w, err)
 		return
 	}
 
+	model.ID = id
+	if err := api.checkPluginConflicts(r.Context(), model); err != nil {
+		api.error(400, w, err)
+		return
+	}
+
 	p, err := model.Plugin()
 	api.assert(err)
 
 	model.Config = utils.Must(p.MarshalConfig())
 
-	model.ID = id
 	err = api.db.PluginsWS.Update(r.Context(), model)
 	api.assert(err)
 
+	api.redact(r, model)
 	api.json(200, w, model)
 }
 
+func (api *API) ListPluginSchemas(w http.ResponseWriter, r *http.Request) {
+	registrations := plugin.GetRegistrations()
+	list := make([]*PluginSchema, 0, len(registrations))
+	for _, registration := range registrations {
+		list = append(list, NewPluginSchema(registration))
+	}
+
+	api.json(200, w, NewPagination(int64(len(list)), list))
+}
+
+func (api *API) GetPluginSchema(w http.ResponseWriter, r *http.Request) {
+	name := api.param(r, "name")
+	registration := plugin.GetRegistration(name)
+	if registration == nil {
+		api.json(404, w, types.ErrorResponse{Message: MsgNotFound})
+		return
+	}
+
+	api.json(200, w, NewPluginSchema(registration))
+}
+
 func (api *API) DeletePlugin(w http.ResponseWriter, r *http.Request) {
 	id := api.param(r, "id")
 	_, err := api.db.PluginsWS.Delete(r.Context(), id)
@@ -92,3 +136,45 @@ func (api *API) DeletePlugin(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(204)
 }
+
+// checkPluginConflicts checks the plugin against the other plugins applied to the same endpoint, source or workspace
+func (api *API) checkPluginConflicts(ctx context.Context, model *entities.Plugin) error {
+	var targets []query.PluginQuery
+	if model.EndpointId != nil {
+		targets = append(targets, query.PluginQuery{EndpointId: model.EndpointId})
+	}
+	if model.SourceId != nil {
+		targets = append(targets, query.PluginQuery{SourceId: model.SourceId})
+	}
+	if model.Unbound() {
+		if model.Global {
+			defaultWorkspace, err := api.db.Workspaces.GetDefault(ctx)
+			if err != nil {
+				return err
+			}
+			if defaultWorkspace.ID != ucontext.GetWorkspaceID(ctx) {
+				e := errs.NewValidateError(errs.ErrRequestValidation)
+				e.Fields["global"] = "global plugins can only be created in the default workspace"
+				return e
+			}
+		}
+		targets = append(targets, query.PluginQuery{Global: utils.Pointer(model.Global), Unbound: true})
+	}
+	for _, q := range targets {
+		list, err := api.db.PluginsWS.List(ctx, &q)
+		if err != nil {
+			return err
+		}
+		plugins := make([]*entities.Plugin, 0, len(list)+1)
+		for _, p := range list {
+			if p.ID != model.ID {
+				plugins = append(plugins, p)
+			}
+		}
+		plugins = append(plugins, model)
+		if err := entities.CheckPluginConflicts(plugins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
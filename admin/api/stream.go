@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/pkg/ucontext"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const streamKeepAliveInterval = 15 * time.Second
+
+var attemptStatuses = []string{
+	entities.AttemptStatusInit,
+	entities.AttemptStatusQueued,
+	entities.AttemptStatusSuccess,
+	entities.AttemptStatusFailure,
+	entities.AttemptStatusCanceled,
+}
+
+// queryValues returns the values of a query parameter that is either repeated or comma-separated
+func queryValues(r *http.Request, name string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[name] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// StreamEvents streams events and attempt status changes of all nodes as Server-Sent Events
+func (api *API) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	filter := livetail.Filter{
+		WorkspaceId: ucontext.GetWorkspaceID(r.Context()),
+		EventTypes:  queryValues(r, "event_type"),
+		EndpointIds: queryValues(r, "endpoint_id"),
+		Statuses:    queryValues(r, "status"),
+	}
+	for _, status := range filter.Statuses {
+		if !slices.Contains(attemptStatuses, status) {
+			api.json(400, w, types.ErrorResponse{Message: fmt.Sprintf("invalid status: %s", status)})
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// the stream outlives the write timeout of the server
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		api.json(500, w, types.ErrorResponse{Message: "streaming is not supported"})
+		return
+	}
+
+	sub := api.tail.Subscribe(filter)
+	defer api.tail.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(streamKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case activity, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(activity.Payload())
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", activity.Type, data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
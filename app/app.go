@@ -18,20 +18,28 @@ import (
 	"github.com/webhookx-io/webhookx/mcache"
 	"github.com/webhookx-io/webhookx/pkg/accesslog"
 	"github.com/webhookx-io/webhookx/pkg/cache"
+	"github.com/webhookx-io/webhookx/pkg/encryption"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
 	"github.com/webhookx-io/webhookx/pkg/log"
 	"github.com/webhookx-io/webhookx/pkg/metrics"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/ratelimiter"
 	"github.com/webhookx-io/webhookx/pkg/reports"
+	"github.com/webhookx-io/webhookx/pkg/secret"
 	"github.com/webhookx-io/webhookx/pkg/stats"
 	"github.com/webhookx-io/webhookx/pkg/taskqueue"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"github.com/webhookx-io/webhookx/plugins"
+	"github.com/webhookx-io/webhookx/plugins/function"
+	"github.com/webhookx-io/webhookx/plugins/transform"
+	"github.com/webhookx-io/webhookx/plugins/wasm"
 	"github.com/webhookx-io/webhookx/proxy"
 	"github.com/webhookx-io/webhookx/proxy/middlewares"
 	"github.com/webhookx-io/webhookx/service"
 	"github.com/webhookx-io/webhookx/status"
 	"github.com/webhookx-io/webhookx/status/health"
 	"github.com/webhookx-io/webhookx/worker"
+	"github.com/webhookx-io/webhookx/worker/auth"
 	"github.com/webhookx-io/webhookx/worker/deliverer"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
@@ -72,6 +80,7 @@ type Application struct {
 	worker  *worker.Worker
 	tracer  *tracing.Tracer
 	srv     *service.Service
+	tail    *livetail.Tail
 }
 
 func New(cfg *config.Config) (*Application, error) {
@@ -110,17 +119,44 @@ func (app *Application) initialize() error {
 		L1Size: 1000,
 		L1TTL:  time.Second * 10,
 		L2:     c,
+		// secrets are decrypted in process memory only
+		Seal:   entities.EncryptSecrets,
+		Unseal: entities.DecryptSecrets,
 	}))
 
+	// encryption
+	var keyring *encryption.Keyring
+	if cfg.Encryption.IsEnabled() {
+		keyring, err = cfg.Encryption.Keyring()
+		if err != nil {
+			return err
+		}
+	}
+	encryption.Set(keyring)
+
+	// secret references
+	secret.Set(cfg.Secret.Manager())
+	secret.SetPolicy(cfg.Secret.Policy())
+
+	// plugin resource limits
+	function.SetLimits(cfg.Plugin.Function.Limits())
+	wasm.SetLimits(cfg.Plugin.Wasm.Limits())
+	transform.SetLimits(cfg.Plugin.Expression.Limits())
+	entities.SetConditionLimits(cfg.Plugin.Expression.Limits())
+
 	sqlDB, err := db.NewSqlDB(cfg.Database)
 	if err != nil {
 		return err
 	}
 
-	app.bus = eventbus.NewEventBus(
-		app.NodeID(),
-		cfg.Database.GetDSN(),
-		log, sqlDB)
+	var broker eventbus.Broker
+	switch cfg.EventBus.Backend {
+	case config.EventBusBackendRedis:
+		broker = eventbus.NewRedisBroker(client, log)
+	default:
+		broker = eventbus.NewPostgresBroker(cfg.Database.GetDSN(), sqlDB, log)
+	}
+	app.bus = eventbus.NewEventBus(app.NodeID(), broker, log)
 	registerEventHandler(app.bus)
 
 	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
@@ -147,6 +183,11 @@ func (app *Application) initialize() error {
 	if err != nil {
 		return err
 	}
+	if cfg.Metrics.IsExportEnabled(config.ExportPrometheus) && !cfg.Status.IsEnabled() {
+		app.log.Warn("prometheus metrics are not served because the status server is disabled")
+	}
+
+	app.tail = livetail.New(app.bus)
 
 	registry := dispatcher.NewRegistry(db)
 	app.bus.Subscribe("endpoint.crud", func(v interface{}) {
@@ -158,6 +199,7 @@ func (app *Application) initialize() error {
 		DB:       db,
 		Metrics:  app.metrics,
 		Registry: registry,
+		Tail:     app.tail,
 	})
 
 	if cfg.Worker.Enabled || cfg.Proxy.IsEnabled() {
@@ -169,15 +211,24 @@ func (app *Application) initialize() error {
 		app.srv = service.NewService(service.Options{
 			DB:        db,
 			TaskQueue: queue,
+			Tail:      app.tail,
 		})
 	}
 
 	// worker
 	if cfg.Worker.Enabled {
-		d := deliverer.NewHTTPDeliverer(deliverer.Options{
+		delivererOpts := deliverer.Options{
 			Logger:         log.Named("deliverer"),
 			RequestTimeout: time.Duration(cfg.Worker.Deliverer.Timeout) * time.Millisecond,
-		})
+
+			HTTP2:               cfg.Worker.Deliverer.HTTP2,
+			DisableKeepAlives:   !cfg.Worker.Deliverer.KeepAlive,
+			MaxConnsPerHost:     int(cfg.Worker.Deliverer.MaxConnsPerHost),
+			IdleConnTimeout:     time.Duration(cfg.Worker.Deliverer.KeepAliveTimeout) * time.Millisecond,
+			DNSCacheTTL:         time.Duration(cfg.Worker.Deliverer.DNSCacheTTL) * time.Millisecond,
+			MaxResponseBodySize: cfg.Worker.Deliverer.MaxResponseBodySize,
+		}
+		d := deliverer.NewHTTPDeliverer(delivererOpts)
 		if cfg.Worker.Deliverer.Proxy != "" {
 			err := d.SetupProxy(deliverer.ProxyOptions{
 				URL:              cfg.Worker.Deliverer.Proxy,
@@ -190,8 +241,13 @@ func (app *Application) initialize() error {
 				return err
 			}
 		}
+		kafka := deliverer.NewKafkaDeliverer(delivererOpts)
+		amqp := deliverer.NewAMQPDeliverer(delivererOpts)
+		nats := deliverer.NewNATSDeliverer(delivererOpts)
+		grpc := deliverer.NewGRPCDeliverer(delivererOpts)
 		if len(cfg.Worker.Deliverer.ACL.Deny) > 0 {
-			err := d.SetupACL(deliverer.AclOptions{Rules: cfg.Worker.Deliverer.ACL.Deny})
+			aclOpts := deliverer.AclOptions{Rules: cfg.Worker.Deliverer.ACL.Deny}
+			err := errors.Join(d.SetupACL(aclOpts), kafka.SetupACL(aclOpts), amqp.SetupACL(aclOpts), nats.SetupACL(aclOpts), grpc.SetupACL(aclOpts))
 			if err != nil {
 				return err
 			}
@@ -199,13 +255,27 @@ func (app *Application) initialize() error {
 		opts := worker.Options{
 			PoolSize:        int(cfg.Worker.Pool.Size),
 			PoolConcurrency: int(cfg.Worker.Pool.Concurrency),
+			DeliveryTimeout: delivererOpts.RequestTimeout,
+			PluginTimeout:   cfg.Plugin.Timeout(),
 			Deliverer:       d,
+			Authenticator:   auth.NewAuthenticator(auth.Options{Client: d.Client()}),
 			DB:              db,
 			Srv:             app.srv,
+			Dispatcher:      dispatcher,
+			Tail:            app.tail,
 			Tracer:          tracer,
 			Metrics:         app.metrics,
 			EventBus:        app.bus,
 			RedisClient:     client,
+			Deliverers: map[entities.EndpointType]deliverer.Deliverer{
+				entities.EndpointTypeKafka: kafka,
+				entities.EndpointTypeAMQP:  amqp,
+				entities.EndpointTypeNATS:  nats,
+				entities.EndpointTypeGRPC:  grpc,
+			},
+		}
+		if cfg.Worker.Health.Enabled {
+			opts.HealthFailureThreshold = int(cfg.Worker.Health.FailureThreshold)
 		}
 		app.worker = worker.NewWorker(opts)
 	}
@@ -217,6 +287,7 @@ func (app *Application) initialize() error {
 			DB:         db,
 			Dispatcher: dispatcher,
 			EventBus:   app.bus,
+			Tail:       app.tail,
 		}
 		if cfg.AccessLog.Enabled() {
 			accessLogger, err := accesslog.NewAccessLogger("admin", accesslog.Options{
@@ -302,6 +373,7 @@ func (app *Application) initialize() error {
 			AccessLog:  accessLogger,
 			Config:     cfg,
 			Indicators: indicators,
+			Metrics:    app.metrics,
 		}
 		app.status = status.NewStatus(cfg.Status, app.tracer, opts)
 	}
@@ -326,6 +398,24 @@ func registerEventHandler(bus *eventbus.EventBus) {
 		}
 		bus.Broadcast(fmt.Sprintf("%s.crud", eventData.Entity), eventData)
 	})
+	bus.Subscribe("plugin.crud", func(data interface{}) {
+		eventData := data.(*eventbus.CrudData)
+		model := entities.Plugin{}
+		if err := json.Unmarshal(eventData.Data, &model); err != nil {
+			zap.S().Errorf("failed to unmarshal event data: %s", err)
+			return
+		}
+		// secrets are propagated encrypted
+		if err := entities.DecryptSecrets(&model); err != nil {
+			zap.S().Errorf("failed to decrypt plugin secrets: %s", err)
+			return
+		}
+		plugin.NotifyConfigChange(model.Name, &plugin.ConfigChange{
+			ID:          eventData.ID,
+			WorkspaceID: eventData.WID,
+			Config:      model.Config,
+		})
+	})
 }
 
 func (app *Application) DB() *db.DB {
@@ -374,9 +464,14 @@ func (app *Application) Start() error {
 		}
 	}))
 
+	if err := plugin.InitPlugins(); err != nil {
+		return err
+	}
+
 	if err := app.bus.Start(); err != nil {
 		return err
 	}
+	app.tail.Start()
 	if app.admin != nil {
 		app.admin.Start()
 	}
@@ -422,6 +517,7 @@ func (app *Application) Stop() error {
 	}()
 
 	_ = app.bus.Stop()
+	app.tail.Stop()
 	if app.metrics != nil {
 		_ = app.metrics.Stop()
 	}
@@ -441,6 +537,9 @@ func (app *Application) Stop() error {
 	if app.tracer != nil {
 		_ = app.tracer.Stop()
 	}
+	if err := plugin.ShutdownPlugins(); err != nil {
+		app.log.Errorf("failed to shutdown plugins: %v", err)
+	}
 
 	app.started = false
 	app.stop <- struct{}{}
@@ -12,9 +12,10 @@ import (
 
 func newAdminDumpCmd() *cobra.Command {
 	var (
-		addr      string
-		timeout   int
-		workspace string
+		addr          string
+		timeout       int
+		workspace     string
+		revealSecrets bool
 	)
 
 	dump := &cobra.Command{
@@ -23,6 +24,9 @@ func newAdminDumpCmd() *cobra.Command {
 		Long:  ``,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			url := fmt.Sprintf("%s/workspaces/%s/config/dump", addr, workspace)
+			if revealSecrets {
+				url += "?reveal_secrets=true"
+			}
 			r, err := http.NewRequest("POST", url, nil)
 			if err != nil {
 				return err
@@ -71,6 +75,7 @@ func newAdminDumpCmd() *cobra.Command {
 
 	dump.Flags().StringVarP(&workspace, "workspace", "", "default", "Set a specific workspace.")
 	dump.Flags().StringVarP(&addr, "addr", "", defaultAdminURL, "HTTP address of WebhookX's Admin API.")
+	dump.Flags().BoolVarP(&revealSecrets, "reveal-secrets", "", false, "Dump secrets instead of redacting them, requires admin.reveal_secrets to be enabled.")
 	dump.Flags().IntVarP(&timeout, "timeout", "", 10, "Set the request timeout for the client to connect with WebhookX (in seconds).")
 
 	return dump
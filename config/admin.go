@@ -4,6 +4,8 @@ type AdminConfig struct {
 	Listen         string `yaml:"listen" json:"listen"`
 	DebugEndpoints bool   `yaml:"debug_endpoints" json:"debug_endpoints" envconfig:"DEBUG_ENDPOINTS"`
 	TLS            TLS    `yaml:"tls" json:"tls"`
+	// RevealSecrets allows callers to reveal secrets in responses with "?reveal_secrets=true"
+	RevealSecrets bool `yaml:"reveal_secrets" json:"reveal_secrets" envconfig:"REVEAL_SECRETS"`
 }
 
 func (cfg AdminConfig) Validate() error {
@@ -26,18 +26,22 @@ const (
 )
 
 type Config struct {
-	Log              LogConfig       `yaml:"log" json:"log" envconfig:"LOG"`
-	AccessLog        AccessLogConfig `yaml:"access_log" json:"access_log" envconfig:"ACCESS_LOG"`
-	Database         DatabaseConfig  `yaml:"database" json:"database" envconfig:"DATABASE"`
-	Redis            RedisConfig     `yaml:"redis" json:"redis" envconfig:"REDIS"`
-	Admin            AdminConfig     `yaml:"admin" json:"admin" envconfig:"ADMIN"`
-	Status           StatusConfig    `yaml:"status" json:"status" envconfig:"STATUS"`
-	Proxy            ProxyConfig     `yaml:"proxy" json:"proxy" envconfig:"PROXY"`
-	Worker           WorkerConfig    `yaml:"worker" json:"worker" envconfig:"WORKER"`
-	Metrics          MetricsConfig   `yaml:"metrics" json:"metrics" envconfig:"METRICS"`
-	Tracing          TracingConfig   `yaml:"tracing" json:"tracing" envconfig:"TRACING"`
-	Role             Role            `yaml:"role" json:"role" envconfig:"ROLE" default:"standalone"`
-	AnonymousReports bool            `yaml:"anonymous_reports" json:"anonymous_reports" envconfig:"ANONYMOUS_REPORTS" default:"true"`
+	Log              LogConfig        `yaml:"log" json:"log" envconfig:"LOG"`
+	AccessLog        AccessLogConfig  `yaml:"access_log" json:"access_log" envconfig:"ACCESS_LOG"`
+	Database         DatabaseConfig   `yaml:"database" json:"database" envconfig:"DATABASE"`
+	Redis            RedisConfig      `yaml:"redis" json:"redis" envconfig:"REDIS"`
+	EventBus         EventBusConfig   `yaml:"eventbus" json:"eventbus" envconfig:"EVENTBUS"`
+	Admin            AdminConfig      `yaml:"admin" json:"admin" envconfig:"ADMIN"`
+	Status           StatusConfig     `yaml:"status" json:"status" envconfig:"STATUS"`
+	Proxy            ProxyConfig      `yaml:"proxy" json:"proxy" envconfig:"PROXY"`
+	Worker           WorkerConfig     `yaml:"worker" json:"worker" envconfig:"WORKER"`
+	Metrics          MetricsConfig    `yaml:"metrics" json:"metrics" envconfig:"METRICS"`
+	Tracing          TracingConfig    `yaml:"tracing" json:"tracing" envconfig:"TRACING"`
+	Encryption       EncryptionConfig `yaml:"encryption" json:"encryption" envconfig:"ENCRYPTION"`
+	Secret           SecretConfig     `yaml:"secret" json:"secret" envconfig:"SECRET"`
+	Plugin           PluginConfig     `yaml:"plugin" json:"plugin" envconfig:"PLUGIN"`
+	Role             Role             `yaml:"role" json:"role" envconfig:"ROLE" default:"standalone"`
+	AnonymousReports bool             `yaml:"anonymous_reports" json:"anonymous_reports" envconfig:"ANONYMOUS_REPORTS" default:"true"`
 }
 
 func (cfg Config) String() string {
@@ -61,6 +65,9 @@ func (cfg Config) Validate() error {
 	if err := cfg.Redis.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.EventBus.Validate(); err != nil {
+		return err
+	}
 	if err := cfg.Admin.Validate(); err != nil {
 		return err
 	}
@@ -79,6 +86,15 @@ func (cfg Config) Validate() error {
 	if err := cfg.Tracing.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.Encryption.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Secret.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Plugin.Validate(); err != nil {
+		return err
+	}
 	if !slices.Contains([]Role{RoleStandalone, RoleCP, RoleDPWorker, RoleDPProxy}, cfg.Role) {
 		return fmt.Errorf("invalid role: '%s'", cfg.Role)
 	}
@@ -97,6 +113,10 @@ func Init() (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
@@ -122,6 +142,10 @@ func InitWithFile(filename string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/pkg/secret"
 	"testing"
+	"time"
 )
 
 func TestRedisConfig(t *testing.T) {
@@ -38,6 +40,34 @@ func TestRedisConfig(t *testing.T) {
 	}
 }
 
+func TestEventBusConfig(t *testing.T) {
+	tests := []struct {
+		desc                string
+		cfg                 EventBusConfig
+		expectedValidateErr error
+	}{
+		{
+			desc:                "postgres",
+			cfg:                 EventBusConfig{Backend: EventBusBackendPostgres},
+			expectedValidateErr: nil,
+		},
+		{
+			desc:                "redis",
+			cfg:                 EventBusConfig{Backend: EventBusBackendRedis},
+			expectedValidateErr: nil,
+		},
+		{
+			desc:                "invalid backend",
+			cfg:                 EventBusConfig{Backend: "kafka"},
+			expectedValidateErr: errors.New("invalid backend: 'kafka'"),
+		},
+	}
+	for _, test := range tests {
+		actualValidateErr := test.cfg.Validate()
+		assert.Equal(t, test.expectedValidateErr, actualValidateErr, "expected %v got %v", test.expectedValidateErr, actualValidateErr)
+	}
+}
+
 func TestLogConfig(t *testing.T) {
 	tests := []struct {
 		desc                string
@@ -182,6 +212,18 @@ func TestMetricsConfig(t *testing.T) {
 			},
 			expectedValidateErr: nil,
 		},
+		{
+			desc: "prometheus export",
+			cfg: MetricsConfig{
+				Attributes:   nil,
+				Exports:      []Export{"opentelemetry", "prometheus"},
+				PushInterval: 1,
+				Opentelemetry: OpentelemetryMetrics{
+					Protocol: "http/protobuf",
+				},
+			},
+			expectedValidateErr: nil,
+		},
 		{
 			desc: "invalid export",
 			cfg: MetricsConfig{
@@ -388,6 +430,27 @@ func TestWorkerConfig(t *testing.T) {
 			},
 			validateErr: errors.New("deliverer.timeout cannot be negative"),
 		},
+		{
+			desc: "invalid deliverer configuration: negative keepalive_timeout",
+			cfg: WorkerConfig{
+				Deliverer: WorkerDeliverer{KeepAliveTimeout: -1},
+			},
+			validateErr: errors.New("deliverer.keepalive_timeout cannot be negative"),
+		},
+		{
+			desc: "invalid deliverer configuration: negative dns_cache_ttl",
+			cfg: WorkerConfig{
+				Deliverer: WorkerDeliverer{DNSCacheTTL: -1},
+			},
+			validateErr: errors.New("deliverer.dns_cache_ttl cannot be negative"),
+		},
+		{
+			desc: "invalid deliverer configuration: negative max_response_body_size",
+			cfg: WorkerConfig{
+				Deliverer: WorkerDeliverer{MaxResponseBodySize: -1},
+			},
+			validateErr: errors.New("deliverer.max_response_body_size cannot be negative"),
+		},
 		{
 			desc: "invalid deliverer configuration: invalid acl configuration 1",
 			cfg: WorkerConfig{
@@ -424,6 +487,25 @@ func TestWorkerConfig(t *testing.T) {
 			},
 			validateErr: errors.New("invalid rule 'тест.example.com': requires IP, CIDR, hostname, or pre-configured name"),
 		},
+		{
+			desc: "invalid health configuration: zero failure threshold",
+			cfg: WorkerConfig{
+				Health: WorkerHealth{
+					Enabled:          true,
+					FailureThreshold: 0,
+				},
+			},
+			validateErr: errors.New("health.failure_threshold must be greater than 0"),
+		},
+		{
+			desc: "health disabled",
+			cfg: WorkerConfig{
+				Health: WorkerHealth{
+					Enabled: false,
+				},
+			},
+			validateErr: nil,
+		},
 	}
 	for _, test := range tests {
 		actual := test.cfg.Validate()
@@ -490,6 +572,7 @@ func TestConfig(t *testing.T) {
 	cfg2.Database.Password = cfg.Database.Password
 	cfg2.Redis.Password = cfg.Redis.Password
 	cfg2.Proxy.Queue.Redis.Password = cfg.Proxy.Queue.Redis.Password
+	cfg2.Secret.Vault.Token = cfg.Secret.Vault.Token
 	assert.Nil(t, err)
 	assert.Equal(t, cfg, cfg2)
 }
@@ -499,3 +582,129 @@ func TestInitWithFile(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, cfg.Validate())
 }
+
+func TestSecretReferences(t *testing.T) {
+	t.Setenv("WEBHOOKX_TEST_DATABASE_PASSWORD", "database-password")
+	cfg, err := InitWithFile("./testdata/config-secret.yml")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "database-password", cfg.Database.Password)
+	assert.EqualValues(t, "redis-password", cfg.Redis.Password)
+
+	t.Setenv("WEBHOOKX_REDIS_PASSWORD", "{secret://env/WEBHOOKX_TEST_NOT_EXIST}")
+	_, err = InitWithFile("./testdata/config-secret.yml")
+	assert.EqualError(t, err, "failed to resolve secret {secret://env/WEBHOOKX_TEST_NOT_EXIST}: secret not found: environment variable 'WEBHOOKX_TEST_NOT_EXIST' is not set")
+}
+
+func TestSecretConfig(t *testing.T) {
+	cfg, err := Init()
+	assert.Nil(t, err)
+	assert.Equal(t, &secret.Policy{Providers: []string{"vault"}}, cfg.Secret.Policy())
+
+	cfg.Secret.Plugin.Providers = []string{"env", "unknown"}
+	assert.EqualError(t, cfg.Secret.Validate(), "invalid plugin secret provider: unknown")
+}
+
+func TestEncryptionConfig(t *testing.T) {
+	tests := []struct {
+		desc        string
+		cfg         EncryptionConfig
+		validateErr error
+	}{
+		{
+			desc:        "disabled",
+			cfg:         EncryptionConfig{},
+			validateErr: nil,
+		},
+		{
+			desc: "sanity",
+			cfg: EncryptionConfig{
+				Keys: []Password{"k1:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="},
+			},
+			validateErr: nil,
+		},
+		{
+			desc: "invalid key",
+			cfg: EncryptionConfig{
+				Keys: []Password{"k1:MDEy"},
+			},
+			validateErr: errors.New("invalid encryption keys: invalid key 'k1': must be 32 bytes"),
+		},
+		{
+			desc: "missing keyring file",
+			cfg: EncryptionConfig{
+				KeyringFile: "./testdata/notfound",
+			},
+			validateErr: errors.New("invalid encryption keys: open ./testdata/notfound: no such file or directory"),
+		},
+	}
+	for _, test := range tests {
+		actual := test.cfg.Validate()
+		assert.Equal(t, test.validateErr, actual, "expected %v got %v", test.validateErr, actual)
+	}
+}
+
+func TestPluginConfig(t *testing.T) {
+	tests := []struct {
+		desc        string
+		cfg         PluginConfig
+		validateErr error
+	}{
+		{
+			desc: "sanity",
+			cfg: PluginConfig{
+				Function:   FunctionPluginConfig{Timeout: 1000},
+				Wasm:       WasmPluginConfig{Timeout: 1000, MaxMemory: 1024, MaxOutputSize: 1024},
+				Expression: ExpressionPluginConfig{Timeout: 1000, MaxOutputSize: 1024},
+			},
+			validateErr: nil,
+		},
+		{
+			desc:        "invalid function timeout",
+			cfg:         PluginConfig{Function: FunctionPluginConfig{Timeout: -1}},
+			validateErr: errors.New("function.timeout cannot be negative"),
+		},
+		{
+			desc:        "invalid function max_output_size",
+			cfg:         PluginConfig{Function: FunctionPluginConfig{MaxOutputSize: -1}},
+			validateErr: errors.New("function.max_output_size cannot be negative"),
+		},
+		{
+			desc:        "invalid wasm timeout",
+			cfg:         PluginConfig{Wasm: WasmPluginConfig{Timeout: -1}},
+			validateErr: errors.New("wasm.timeout cannot be negative"),
+		},
+		{
+			desc:        "invalid wasm max_memory",
+			cfg:         PluginConfig{Wasm: WasmPluginConfig{MaxMemory: -1}},
+			validateErr: errors.New("wasm.max_memory cannot be negative"),
+		},
+		{
+			desc:        "invalid wasm max_output_size",
+			cfg:         PluginConfig{Wasm: WasmPluginConfig{MaxOutputSize: -1}},
+			validateErr: errors.New("wasm.max_output_size cannot be negative"),
+		},
+		{
+			desc:        "invalid expression timeout",
+			cfg:         PluginConfig{Expression: ExpressionPluginConfig{Timeout: -1}},
+			validateErr: errors.New("expression.timeout cannot be negative"),
+		},
+		{
+			desc:        "invalid expression max_output_size",
+			cfg:         PluginConfig{Expression: ExpressionPluginConfig{MaxOutputSize: -1}},
+			validateErr: errors.New("expression.max_output_size cannot be negative"),
+		},
+	}
+	for _, test := range tests {
+		actual := test.cfg.Validate()
+		assert.Equal(t, test.validateErr, actual, "expected %v got %v", test.validateErr, actual)
+	}
+}
+
+func TestPluginConfigTimeout(t *testing.T) {
+	cfg := PluginConfig{
+		Function:   FunctionPluginConfig{Timeout: 1000},
+		Wasm:       WasmPluginConfig{Timeout: 3000},
+		Expression: ExpressionPluginConfig{Timeout: 2000},
+	}
+	assert.Equal(t, 3*time.Second, cfg.Timeout())
+}
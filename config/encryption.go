@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"github.com/webhookx-io/webhookx/pkg/encryption"
+)
+
+type EncryptionConfig struct {
+	// Keys is a list of keys in the form of "<id>:<base64 encoded 32 bytes key>", the first one is the primary key
+	Keys        []Password `yaml:"keys" json:"keys" envconfig:"KEYS"`
+	KeyringFile string     `yaml:"keyring_file" json:"keyring_file" envconfig:"KEYRING_FILE"`
+}
+
+func (cfg EncryptionConfig) Validate() error {
+	if !cfg.IsEnabled() {
+		return nil
+	}
+	if _, err := cfg.Keyring(); err != nil {
+		return fmt.Errorf("invalid encryption keys: %s", err)
+	}
+	return nil
+}
+
+func (cfg EncryptionConfig) IsEnabled() bool {
+	return len(cfg.Keys) > 0 || cfg.KeyringFile != ""
+}
+
+// Keyring returns the keyring, keys from the keyring file are appended to the configured keys
+func (cfg EncryptionConfig) Keyring() (*encryption.Keyring, error) {
+	keys := make([]string, 0, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		keys = append(keys, string(key))
+	}
+	if cfg.KeyringFile != "" {
+		fileKeys, err := encryption.LoadKeyringFile(cfg.KeyringFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, fileKeys...)
+	}
+	return encryption.NewKeyring(keys)
+}
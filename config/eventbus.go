@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+type EventBusBackend string
+
+const (
+	EventBusBackendPostgres EventBusBackend = "postgres"
+	EventBusBackendRedis    EventBusBackend = "redis"
+)
+
+type EventBusConfig struct {
+	Backend EventBusBackend `yaml:"backend" json:"backend" default:"postgres"`
+}
+
+func (cfg EventBusConfig) Validate() error {
+	if !slices.Contains([]EventBusBackend{EventBusBackendPostgres, EventBusBackendRedis}, cfg.Backend) {
+		return fmt.Errorf("invalid backend: '%s'", cfg.Backend)
+	}
+	return nil
+}
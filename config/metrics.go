@@ -6,10 +6,11 @@ import (
 )
 
 type MetricsConfig struct {
-	Attributes    Map                  `yaml:"attributes" json:"attributes"`
-	Exports       []Export             `yaml:"exports" json:"exports"`
-	PushInterval  uint32               `yaml:"push_interval" json:"push_interval" default:"10" envconfig:"PUSH_INTERVAL"`
-	Opentelemetry OpentelemetryMetrics `yaml:"opentelemetry" json:"opentelemetry"`
+	Attributes     Map                  `yaml:"attributes" json:"attributes"`
+	Exports        []Export             `yaml:"exports" json:"exports"`
+	PushInterval   uint32               `yaml:"push_interval" json:"push_interval" default:"10" envconfig:"PUSH_INTERVAL"`
+	MaxLabelValues uint32               `yaml:"max_label_values" json:"max_label_values" default:"1000" envconfig:"MAX_LABEL_VALUES"`
+	Opentelemetry  OpentelemetryMetrics `yaml:"opentelemetry" json:"opentelemetry"`
 }
 
 type OpentelemetryMetrics struct {
@@ -24,12 +25,17 @@ func (cfg OpentelemetryMetrics) Validate() error {
 	return nil
 }
 
+// IsExportEnabled reports whether the export is enabled
+func (cfg *MetricsConfig) IsExportEnabled(export Export) bool {
+	return slices.Contains(cfg.Exports, export)
+}
+
 func (cfg *MetricsConfig) Validate() error {
 	if err := cfg.Opentelemetry.Validate(); err != nil {
 		return err
 	}
 	for _, export := range cfg.Exports {
-		if !slices.Contains([]Export{ExportOpenTelemetry}, export) {
+		if !slices.Contains([]Export{ExportOpenTelemetry, ExportPrometheus}, export) {
 			return fmt.Errorf("invalid export: %s", export)
 		}
 	}
@@ -43,4 +49,5 @@ type Export string
 
 const (
 	ExportOpenTelemetry Export = "opentelemetry"
+	ExportPrometheus    Export = "prometheus"
 )
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"time"
+)
+
+type PluginConfig struct {
+	Function   FunctionPluginConfig   `yaml:"function" json:"function" envconfig:"FUNCTION"`
+	Wasm       WasmPluginConfig       `yaml:"wasm" json:"wasm" envconfig:"WASM"`
+	Expression ExpressionPluginConfig `yaml:"expression" json:"expression" envconfig:"EXPRESSION"`
+}
+
+func (cfg PluginConfig) Validate() error {
+	if err := cfg.Function.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Wasm.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Expression.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Timeout returns the maximum timeout of plugin executions
+func (cfg PluginConfig) Timeout() time.Duration {
+	timeout := max(cfg.Function.Timeout, cfg.Wasm.Timeout, cfg.Expression.Timeout)
+	return time.Duration(timeout) * time.Millisecond
+}
+
+// FunctionPluginConfig is the resource limits of function executions.
+// There is no memory limit, goja allocates JavaScript values on the Go heap shared by the whole process
+// and does not account memory per runtime, the timeout bounds how much a function can allocate.
+type FunctionPluginConfig struct {
+	Timeout       int64 `yaml:"timeout" json:"timeout" default:"1000"`
+	MaxOutputSize int64 `yaml:"max_output_size" json:"max_output_size" envconfig:"MAX_OUTPUT_SIZE"`
+}
+
+func (cfg FunctionPluginConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("function.timeout cannot be negative")
+	}
+	if cfg.MaxOutputSize < 0 {
+		return fmt.Errorf("function.max_output_size cannot be negative")
+	}
+	return nil
+}
+
+func (cfg FunctionPluginConfig) Limits() plugin.Limits {
+	return plugin.Limits{
+		Timeout:       time.Duration(cfg.Timeout) * time.Millisecond,
+		MaxOutputSize: cfg.MaxOutputSize,
+	}
+}
+
+type WasmPluginConfig struct {
+	Timeout       int64 `yaml:"timeout" json:"timeout" default:"1000"`
+	MaxMemory     int64 `yaml:"max_memory" json:"max_memory" default:"134217728" envconfig:"MAX_MEMORY"`
+	MaxOutputSize int64 `yaml:"max_output_size" json:"max_output_size" envconfig:"MAX_OUTPUT_SIZE"`
+}
+
+func (cfg WasmPluginConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("wasm.timeout cannot be negative")
+	}
+	if cfg.MaxMemory < 0 {
+		return fmt.Errorf("wasm.max_memory cannot be negative")
+	}
+	if cfg.MaxOutputSize < 0 {
+		return fmt.Errorf("wasm.max_output_size cannot be negative")
+	}
+	return nil
+}
+
+func (cfg WasmPluginConfig) Limits() plugin.Limits {
+	return plugin.Limits{
+		Timeout:       time.Duration(cfg.Timeout) * time.Millisecond,
+		MaxMemory:     cfg.MaxMemory,
+		MaxOutputSize: cfg.MaxOutputSize,
+	}
+}
+
+// ExpressionPluginConfig is the resource limits of expression evaluations,
+// including the transform plugin and plugin conditions.
+type ExpressionPluginConfig struct {
+	Timeout       int64 `yaml:"timeout" json:"timeout" default:"1000"`
+	MaxOutputSize int64 `yaml:"max_output_size" json:"max_output_size" envconfig:"MAX_OUTPUT_SIZE"`
+}
+
+func (cfg ExpressionPluginConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("expression.timeout cannot be negative")
+	}
+	if cfg.MaxOutputSize < 0 {
+		return fmt.Errorf("expression.max_output_size cannot be negative")
+	}
+	return nil
+}
+
+func (cfg ExpressionPluginConfig) Limits() plugin.Limits {
+	return plugin.Limits{
+		Timeout:       time.Duration(cfg.Timeout) * time.Millisecond,
+		MaxOutputSize: cfg.MaxOutputSize,
+	}
+}
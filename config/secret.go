@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/webhookx-io/webhookx/pkg/secret"
+	"slices"
+	"time"
+)
+
+type SecretConfig struct {
+	CacheTTL uint32             `yaml:"cache_ttl" json:"cache_ttl" default:"60" envconfig:"CACHE_TTL"`
+	Vault    VaultConfig        `yaml:"vault" json:"vault" envconfig:"VAULT"`
+	Plugin   PluginSecretConfig `yaml:"plugin" json:"plugin" envconfig:"PLUGIN"`
+}
+
+// PluginSecretConfig restricts the secret references of plugin configurations
+type PluginSecretConfig struct {
+	Providers    []string `yaml:"providers" json:"providers" default:"[\"vault\"]" envconfig:"PROVIDERS"`
+	PathPrefixes []string `yaml:"path_prefixes" json:"path_prefixes" envconfig:"PATH_PREFIXES"`
+}
+
+type VaultConfig struct {
+	Address   string   `yaml:"address" json:"address" envconfig:"ADDRESS"`
+	Token     Password `yaml:"token" json:"token" envconfig:"TOKEN"`
+	Namespace string   `yaml:"namespace" json:"namespace" envconfig:"NAMESPACE"`
+}
+
+func (cfg VaultConfig) IsEnabled() bool {
+	return cfg.Address != ""
+}
+
+var secretProviders = []string{"env", "file", "vault"}
+
+func (cfg SecretConfig) Validate() error {
+	for _, provider := range cfg.Plugin.Providers {
+		if !slices.Contains(secretProviders, provider) {
+			return fmt.Errorf("invalid plugin secret provider: %s", provider)
+		}
+	}
+	return nil
+}
+
+// Policy returns the policy of secret references in plugin configurations
+func (cfg SecretConfig) Policy() *secret.Policy {
+	return &secret.Policy{
+		Providers:    cfg.Plugin.Providers,
+		PathPrefixes: cfg.Plugin.PathPrefixes,
+	}
+}
+
+// Manager returns a secret manager with providers configured
+func (cfg SecretConfig) Manager() *secret.Manager {
+	m := secret.NewManager(secret.Options{
+		CacheTTL: time.Duration(cfg.CacheTTL) * time.Second,
+	})
+	if cfg.Vault.IsEnabled() {
+		m.Register("vault", &secret.VaultProvider{
+			Address:   cfg.Vault.Address,
+			Token:     string(cfg.Vault.Token),
+			Namespace: cfg.Vault.Namespace,
+		})
+	}
+	return m
+}
+
+// resolveSecrets resolves secret references in the configuration
+func (cfg *Config) resolveSecrets() error {
+	ctx := context.Background()
+	// the secret configuration itself can only reference env and file secrets
+	if err := secret.NewManager(secret.Options{}).ResolveStruct(ctx, &cfg.Secret); err != nil {
+		return fmt.Errorf("failed to resolve secret configuration: %w", err)
+	}
+	return cfg.Secret.Manager().ResolveStruct(ctx, cfg)
+}
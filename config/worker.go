@@ -9,19 +9,34 @@ import (
 )
 
 type WorkerDeliverer struct {
-	Timeout        int64     `yaml:"timeout" json:"timeout" default:"60000"`
-	ACL            ACLConfig `yaml:"acl" json:"acl"`
-	Proxy          string    `yaml:"proxy" json:"proxy"`
-	ProxyTLSCert   string    `yaml:"proxy_tls_cert" json:"proxy_tls_cert" envconfig:"PROXY_TLS_CERT"`
-	ProxyTLSKey    string    `yaml:"proxy_tls_key" json:"proxy_tls_key" envconfig:"PROXY_TLS_KEY"`
-	ProxyTLSCaCert string    `yaml:"proxy_tls_ca_cert" json:"proxy_tls_ca_cert" envconfig:"PROXY_TLS_CA_CERT"`
-	ProxyTLSVerify bool      `yaml:"proxy_tls_verify" json:"proxy_tls_verify" envconfig:"PROXY_TLS_VERIFY"`
+	Timeout             int64     `yaml:"timeout" json:"timeout" default:"60000"`
+	ACL                 ACLConfig `yaml:"acl" json:"acl"`
+	Proxy               string    `yaml:"proxy" json:"proxy"`
+	ProxyTLSCert        string    `yaml:"proxy_tls_cert" json:"proxy_tls_cert" envconfig:"PROXY_TLS_CERT"`
+	ProxyTLSKey         string    `yaml:"proxy_tls_key" json:"proxy_tls_key" envconfig:"PROXY_TLS_KEY"`
+	ProxyTLSCaCert      string    `yaml:"proxy_tls_ca_cert" json:"proxy_tls_ca_cert" envconfig:"PROXY_TLS_CA_CERT"`
+	ProxyTLSVerify      bool      `yaml:"proxy_tls_verify" json:"proxy_tls_verify" envconfig:"PROXY_TLS_VERIFY"`
+	HTTP2               bool      `yaml:"http2" json:"http2" default:"false"`
+	MaxConnsPerHost     uint32    `yaml:"max_conns_per_host" json:"max_conns_per_host" envconfig:"MAX_CONNS_PER_HOST"`
+	KeepAlive           bool      `yaml:"keepalive" json:"keepalive" default:"true"`
+	KeepAliveTimeout    int64     `yaml:"keepalive_timeout" json:"keepalive_timeout" default:"30000" envconfig:"KEEPALIVE_TIMEOUT"`
+	DNSCacheTTL         int64     `yaml:"dns_cache_ttl" json:"dns_cache_ttl" envconfig:"DNS_CACHE_TTL"`
+	MaxResponseBodySize int64     `yaml:"max_response_body_size" json:"max_response_body_size" default:"1048576" envconfig:"MAX_RESPONSE_BODY_SIZE"`
 }
 
 func (cfg *WorkerDeliverer) Validate() error {
 	if cfg.Timeout < 0 {
 		return fmt.Errorf("deliverer.timeout cannot be negative")
 	}
+	if cfg.KeepAliveTimeout < 0 {
+		return fmt.Errorf("deliverer.keepalive_timeout cannot be negative")
+	}
+	if cfg.DNSCacheTTL < 0 {
+		return fmt.Errorf("deliverer.dns_cache_ttl cannot be negative")
+	}
+	if cfg.MaxResponseBodySize < 0 {
+		return fmt.Errorf("deliverer.max_response_body_size cannot be negative")
+	}
 	if err := cfg.ACL.Validate(); err != nil {
 		return err
 	}
@@ -46,10 +61,23 @@ type Pool struct {
 	Concurrency uint32 `yaml:"concurrency" json:"concurrency"`
 }
 
+type WorkerHealth struct {
+	Enabled          bool   `yaml:"enabled" json:"enabled" default:"false"`
+	FailureThreshold uint32 `yaml:"failure_threshold" json:"failure_threshold" default:"10" envconfig:"FAILURE_THRESHOLD"`
+}
+
+func (cfg *WorkerHealth) Validate() error {
+	if cfg.Enabled && cfg.FailureThreshold == 0 {
+		return fmt.Errorf("health.failure_threshold must be greater than 0")
+	}
+	return nil
+}
+
 type WorkerConfig struct {
 	Enabled   bool            `yaml:"enabled" json:"enabled" default:"false"`
 	Deliverer WorkerDeliverer `yaml:"deliverer" json:"deliverer"`
 	Pool      Pool            `yaml:"pool" json:"pool"`
+	Health    WorkerHealth    `yaml:"health" json:"health"`
 }
 
 type ACLConfig struct {
@@ -87,5 +115,8 @@ func (cfg *WorkerConfig) Validate() error {
 	if err := cfg.Deliverer.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.Health.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
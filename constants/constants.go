@@ -27,6 +27,29 @@ const (
 	RequeueInterval = time.Second * 60
 )
 
+// Endpoint Concurrency
+const (
+	ConcurrencyKeyPrefix = "webhookx:concurrency:"
+	// ConcurrencyLimitDelay is the delay of tasks rescheduled when the endpoint is at its concurrency limit
+	ConcurrencyLimitDelay = time.Second
+)
+
+// Endpoint Health
+const (
+	// EndpointHealthWindow is the approximate number of recent attempts the endpoint success rate reflects
+	EndpointHealthWindow = 20
+
+	EventTypeEndpointFailing   = "webhookx.endpoint.failing"
+	EventTypeEndpointRecovered = "webhookx.endpoint.recovered"
+)
+
+// Analytics
+const (
+	AnalyticsRollupInterval = time.Minute
+	// AnalyticsRollupWindow is how far back attempts are re-aggregated by each rollup, so that attempts written late are counted
+	AnalyticsRollupWindow = time.Hour
+)
+
 type CacheKey string
 
 func (c CacheKey) Build(id string) string {
@@ -45,6 +68,8 @@ const (
 	EndpointCacheKey      CacheKey = "endpoints"
 	EndpointPluginsKey    CacheKey = "endpoint_plugins"
 	SourcePluginsKey      CacheKey = "source_plugins"
+	WorkspacePluginsKey   CacheKey = "workspace_plugins"
+	GlobalPluginsKey      CacheKey = "global_plugins"
 	SourceCacheKey        CacheKey = "sources"
 	WorkspaceCacheKey     CacheKey = "workspaces"
 	AttemptCacheKey       CacheKey = "attempts"
@@ -0,0 +1,105 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/eventbus"
+	"github.com/webhookx-io/webhookx/pkg/analytics"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"github.com/webhookx-io/webhookx/pkg/ucontext"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"time"
+)
+
+// attemptStatsLockKey is the key of the advisory lock that serializes rollups across nodes
+const attemptStatsLockKey int64 = 0x7765626b68737473
+
+type attemptStatsDao struct {
+	*DAO[entities.AttemptStats]
+}
+
+func NewAttemptStatsDao(db *sqlx.DB, bus *eventbus.EventBus, workspace bool) AttemptStatsDAO {
+	opts := Options{
+		Table:      "attempt_stats",
+		EntityName: "attempt_stats",
+		Workspace:  workspace,
+	}
+	return &attemptStatsDao{
+		DAO: NewDAO[entities.AttemptStats](db, bus, opts),
+	}
+}
+
+// rollupSQL aggregates delivered attempts into hourly buckets, with a latency histogram of analytics.LatencyBounds.
+// Existing buckets are updated in place, and only when their stats have changed.
+var rollupSQL = func() string {
+	n := len(analytics.LatencyBounds)
+	buckets := make([]string, 0, n+1)
+	for i, bound := range analytics.LatencyBounds {
+		if i == 0 {
+			buckets = append(buckets, fmt.Sprintf("count(*) FILTER (WHERE a.latency <= %d)", bound))
+		} else {
+			buckets = append(buckets, fmt.Sprintf("count(*) FILTER (WHERE a.latency > %d AND a.latency <= %d)", analytics.LatencyBounds[i-1], bound))
+		}
+	}
+	buckets = append(buckets, fmt.Sprintf("count(*) FILTER (WHERE a.latency > %d)", analytics.LatencyBounds[n-1]))
+
+	return fmt.Sprintf(`INSERT INTO attempt_stats (bucket, endpoint_id, source_id, event_type, status, error_code, count, latency_count, latency_sum, latency_buckets, ws_id)
+SELECT to_timestamp(floor(extract(epoch FROM a.attempted_at) / %[1]d) * %[1]d), a.endpoint_id, e.source_id, e.event_type, a.status, a.error_code,
+	count(*), count(a.latency), COALESCE(sum(a.latency), 0), ARRAY[%[2]s], a.ws_id
+FROM (SELECT *, (response->>'latency')::BIGINT AS latency FROM attempts WHERE attempted_at >= $1) a
+JOIN events e ON e.id = a.event_id
+GROUP BY 1, 2, 3, 4, 5, 6, a.ws_id
+ON CONFLICT (bucket, endpoint_id, (COALESCE(source_id, '')), event_type, status, (COALESCE(error_code, '')), (COALESCE(ws_id, '')))
+DO UPDATE SET count = EXCLUDED.count, latency_count = EXCLUDED.latency_count, latency_sum = EXCLUDED.latency_sum, latency_buckets = EXCLUDED.latency_buckets
+WHERE (attempt_stats.count, attempt_stats.latency_count, attempt_stats.latency_sum, attempt_stats.latency_buckets)
+	IS DISTINCT FROM (EXCLUDED.count, EXCLUDED.latency_count, EXCLUDED.latency_sum, EXCLUDED.latency_buckets)`, int(analytics.RollupPeriod.Seconds()), strings.Join(buckets, ", "))
+}()
+
+func (dao *attemptStatsDao) Rollup(ctx context.Context, since time.Time) (bool, error) {
+	ctx, span := tracing.Start(ctx, "dao.attempt_stats.rollup", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	db := dao.DB(ctx)
+	if _, ok := db.(*sqlx.Tx); !ok {
+		return false, fmt.Errorf("rollup must be called within a transaction")
+	}
+
+	var locked bool
+	if err := db.GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock($1)", attemptStatsLockKey); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+
+	since = since.Truncate(analytics.RollupPeriod)
+	dao.debugSQL(rollupSQL, nil)
+	if _, err := db.ExecContext(ctx, rollupSQL, since); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (dao *attemptStatsDao) List(ctx context.Context, q *query.AttemptStatsQuery) (list []*entities.AttemptStats, err error) {
+	ctx, span := tracing.Start(ctx, "dao.attempt_stats.list", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	builder := psql.Select("*").From(dao.opts.Table).
+		Where(sq.GtOrEq{"bucket": q.Start}).
+		Where(sq.Lt{"bucket": q.End})
+	if where := q.WhereMap(); len(where) > 0 {
+		builder = builder.Where(sq.Eq(where))
+	}
+	if dao.workspace {
+		builder = builder.Where(sq.Eq{"ws_id": ucontext.GetWorkspaceID(ctx)})
+	}
+	statement, args := builder.OrderBy("bucket").MustSql()
+	dao.debugSQL(statement, args)
+	err = dao.UnsafeDB(ctx).SelectContext(ctx, &list, statement, args...)
+	return
+}
@@ -9,6 +9,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/db/errs"
 	"github.com/webhookx-io/webhookx/db/query"
 	"github.com/webhookx-io/webhookx/db/transaction"
@@ -117,6 +118,9 @@ func (dao *DAO[T]) Select(ctx context.Context, field string, value string) (enti
 	if errors.Is(err, ErrNoRows) {
 		return nil, nil
 	}
+	if err == nil {
+		err = entities.DecryptSecrets(entity)
+	}
 	return
 }
 
@@ -133,9 +137,29 @@ func (dao *DAO[T]) selectByField(ctx context.Context, field string, value string
 	if errors.Is(err, ErrNoRows) {
 		return nil, nil
 	}
+	if err == nil {
+		err = entities.DecryptSecrets(entity)
+	}
 	return
 }
 
+// storedRow returns the row as stored, secrets are not decrypted
+func (dao *DAO[T]) storedRow(ctx context.Context, id string) (*T, error) {
+	builder := psql.Select("*").From(dao.opts.Table).Where(sq.Eq{"id": id})
+	if dao.workspace {
+		wid := ucontext.GetWorkspaceID(ctx)
+		builder = builder.Where(sq.Eq{"ws_id": wid})
+	}
+	statement, args := builder.MustSql()
+	dao.debugSQL(statement, args)
+	entity := new(T)
+	err := dao.UnsafeDB(ctx).GetContext(ctx, entity, statement, args...)
+	if errors.Is(err, ErrNoRows) {
+		return nil, nil
+	}
+	return entity, err
+}
+
 func (dao *DAO[T]) Delete(ctx context.Context, id string) (bool, error) {
 	ctx, span := tracing.Start(ctx, fmt.Sprintf("dao.%s.delete", dao.opts.Table), trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
@@ -156,7 +180,7 @@ func (dao *DAO[T]) Delete(ctx context.Context, id string) (bool, error) {
 		return false, err
 	}
 	if dao.opts.CachePropagate {
-		go dao.propagateEvent(id, entity)
+		dao.propagateEvent(id, entity, nil)
 	}
 	return true, nil
 }
@@ -215,6 +239,14 @@ func (dao *DAO[T]) List(ctx context.Context, q query.Queryer) (list []*T, err er
 	dao.debugSQL(statement, args)
 	list = make([]*T, 0)
 	err = dao.UnsafeDB(ctx).SelectContext(ctx, &list, statement, args...)
+	if err != nil {
+		return
+	}
+	for _, entity := range list {
+		if err = entities.DecryptSecrets(entity); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -222,6 +254,10 @@ func (dao *DAO[T]) Insert(ctx context.Context, entity *T) error {
 	ctx, span := tracing.Start(ctx, fmt.Sprintf("dao.%s.insert", dao.opts.Table), trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
+	if err := entities.EncryptSecrets(entity); err != nil {
+		return err
+	}
+
 	values := make([]interface{}, 0)
 	EachField(entity, func(f reflect.StructField, v reflect.Value, column string) {
 		if column == "created_at" || column == "updated_at" {
@@ -241,23 +277,29 @@ func (dao *DAO[T]) Insert(ctx context.Context, entity *T) error {
 	if dao.opts.CachePropagate && err == nil {
 		id := reflect.ValueOf(*entity).FieldByName("ID")
 		if id.IsValid() {
-			go dao.propagateEvent(id.String(), entity)
+			dao.propagateEvent(id.String(), entity, nil)
 		}
 	}
+	if e := entities.DecryptSecrets(entity); err == nil {
+		err = e
+	}
 	return errs.ConvertError(err)
 }
 
-func (dao *DAO[T]) BatchInsert(ctx context.Context, entities []*T) error {
+func (dao *DAO[T]) BatchInsert(ctx context.Context, list []*T) error {
 	ctx, span := tracing.Start(ctx, fmt.Sprintf("dao.%s.batch_insert", dao.opts.Table), trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	if len(entities) == 0 {
+	if len(list) == 0 {
 		return nil
 	}
 
 	builder := psql.Insert(dao.opts.Table).Columns(dao.columns...)
 
-	for _, entity := range entities {
+	for _, entity := range list {
+		if err := entities.EncryptSecrets(entity); err != nil {
+			return err
+		}
 		values := make([]interface{}, 0)
 		EachField(entity, func(f reflect.StructField, v reflect.Value, column string) {
 			if column == "created_at" || column == "updated_at" {
@@ -279,10 +321,13 @@ func (dao *DAO[T]) BatchInsert(ctx context.Context, entities []*T) error {
 	}
 	i := 0
 	for rows.Next() {
-		err = rows.StructScan(entities[i])
+		err = rows.StructScan(list[i])
 		if err != nil {
 			return err
 		}
+		if err = entities.DecryptSecrets(list[i]); err != nil {
+			return err
+		}
 		i++
 	}
 	return rows.Err()
@@ -308,6 +353,10 @@ func (dao *DAO[T]) Update(ctx context.Context, entity *T) error {
 	ctx, span := tracing.Start(ctx, fmt.Sprintf("dao.%s.update", dao.opts.Table), trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
+	if err := entities.EncryptSecrets(entity); err != nil {
+		return err
+	}
+
 	var id string
 	builder := psql.Update(dao.opts.Table)
 	EachField(entity, func(f reflect.StructField, v reflect.Value, column string) {
@@ -325,16 +374,30 @@ func (dao *DAO[T]) Update(ctx context.Context, entity *T) error {
 		wid := ucontext.GetWorkspaceID(ctx)
 		builder = builder.Where(sq.Eq{"ws_id": wid})
 	}
+	var previous *T
+	if dao.opts.CachePropagate {
+		var err error
+		if previous, err = dao.storedRow(ctx, id); err != nil {
+			return err
+		}
+	}
 	statement, args := builder.Where(sq.Eq{"id": id}).Suffix("RETURNING *").MustSql()
 	dao.debugSQL(statement, args)
 	err := dao.UnsafeDB(ctx).QueryRowxContext(ctx, statement, args...).StructScan(entity)
 	if dao.opts.CachePropagate && err == nil {
-		go dao.propagateEvent(id, entity)
+		dao.propagateEvent(id, entity, previous)
+	}
+	if e := entities.DecryptSecrets(entity); err == nil {
+		err = e
 	}
 	return errs.ConvertError(err)
 }
 
 func (dao *DAO[T]) Upsert(ctx context.Context, fields []string, entity *T) error {
+	if err := entities.EncryptSecrets(entity); err != nil {
+		return err
+	}
+
 	columns := make([]string, 0)
 	values := make([]interface{}, 0)
 	EachField(entity, func(f reflect.StructField, v reflect.Value, column string) {
@@ -364,6 +427,15 @@ func (dao *DAO[T]) Upsert(ctx context.Context, fields []string, entity *T) error
 			clause.WriteString(", ")
 		}
 	}
+	var previous *T
+	if dao.opts.CachePropagate {
+		if id := reflect.ValueOf(*entity).FieldByName("ID"); id.IsValid() {
+			var err error
+			if previous, err = dao.storedRow(ctx, id.String()); err != nil {
+				return err
+			}
+		}
+	}
 	statement, args := psql.Insert(dao.opts.Table).Columns(columns...).Values(values...).
 		Suffix("ON CONFLICT (" + strings.Join(fields, ",") + ") DO UPDATE SET " + clause.String()).
 		Suffix("RETURNING *").
@@ -373,22 +445,32 @@ func (dao *DAO[T]) Upsert(ctx context.Context, fields []string, entity *T) error
 	if dao.opts.CachePropagate && err == nil {
 		id := reflect.ValueOf(*entity).FieldByName("ID")
 		if id.IsValid() {
-			go dao.propagateEvent(id.String(), entity)
+			dao.propagateEvent(id.String(), entity, previous)
 		}
 	}
+	if e := entities.DecryptSecrets(entity); err == nil {
+		err = e
+	}
 	return errs.ConvertError(err)
 }
 
-func (dao *DAO[T]) propagateEvent(id string, entity *T) {
+// propagateEvent broadcasts the CRUD event asynchronously, the entity is serialized
+// before returning so that callers are free to modify it afterward. It must be called
+// with the row as stored, so that secrets are propagated encrypted. previous is the row
+// before an update, so that subscribers can invalidate caches of the old state.
+func (dao *DAO[T]) propagateEvent(id string, entity *T, previous *T) {
 	data := &eventbus.CrudData{
 		ID:       id,
 		CacheKey: dao.opts.CacheKey.Build(id),
 		Entity:   dao.opts.EntityName,
 		Data:     utils.Must(json.Marshal(entity)),
 	}
+	if previous != nil {
+		data.Previous = utils.Must(json.Marshal(previous))
+	}
 	wid := reflect.ValueOf(*entity).FieldByName("WorkspaceId")
 	if wid.IsValid() {
 		data.WID = wid.String()
 	}
-	_ = dao.bus.ClusteringBroadcast(eventbus.EventCRUD, data)
+	go func() { _ = dao.bus.ClusteringBroadcast(eventbus.EventCRUD, data) }()
 }
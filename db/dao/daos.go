@@ -31,6 +31,12 @@ type EndpointDAO interface {
 	BaseDAO[entities.Endpoint]
 }
 
+type EndpointHealthDAO interface {
+	BaseDAO[entities.EndpointHealth]
+	Record(ctx context.Context, endpoint *entities.Endpoint, success bool, at time.Time) (*entities.EndpointHealth, error)
+	UpdateStatus(ctx context.Context, id string, from, to entities.EndpointHealthStatus) (bool, error)
+}
+
 type EventDAO interface {
 	BaseDAO[entities.Event]
 	BatchInsertIgnoreConflict(ctx context.Context, events []*entities.Event) ([]string, error)
@@ -57,4 +63,13 @@ type PluginDAO interface {
 	BaseDAO[entities.Plugin]
 	ListEndpointPlugin(ctx context.Context, endpointId string) (list []*entities.Plugin, err error)
 	ListSourcePlugin(ctx context.Context, sourceId string) ([]*entities.Plugin, error)
+	ListWorkspacePlugin(ctx context.Context, workspaceId string) ([]*entities.Plugin, error)
+	ListGlobalPlugin(ctx context.Context) ([]*entities.Plugin, error)
+}
+
+type AttemptStatsDAO interface {
+	// Rollup recomputes the stats of attempts attempted since the time, it must be called within a transaction.
+	// It returns false when a rollup is in progress on another node.
+	Rollup(ctx context.Context, since time.Time) (bool, error)
+	List(ctx context.Context, q *query.AttemptStatsQuery) ([]*entities.AttemptStats, error)
 }
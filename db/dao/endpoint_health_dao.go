@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/eventbus"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+type endpointHealthDAO struct {
+	*DAO[entities.EndpointHealth]
+}
+
+func NewEndpointHealthDAO(db *sqlx.DB, bus *eventbus.EventBus, workspace bool) EndpointHealthDAO {
+	opts := Options{
+		Table:      "endpoint_health",
+		EntityName: "endpoint_health",
+		Workspace:  workspace,
+	}
+	return &endpointHealthDAO{
+		DAO: NewDAO[entities.EndpointHealth](db, bus, opts),
+	}
+}
+
+// recordSQL folds an attempt outcome into the endpoint's health. success_rate is an exponentially
+// weighted moving average over roughly the last constants.EndpointHealthWindow attempts.
+var recordSQL = fmt.Sprintf(`INSERT INTO endpoint_health (id, success_rate, consecutive_failures, last_success_at, last_failure_at, ws_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE SET
+	success_rate = endpoint_health.success_rate + (EXCLUDED.success_rate - endpoint_health.success_rate) / %d,
+	consecutive_failures = CASE WHEN EXCLUDED.consecutive_failures = 0 THEN 0 ELSE endpoint_health.consecutive_failures + 1 END,
+	last_success_at = GREATEST(endpoint_health.last_success_at, EXCLUDED.last_success_at),
+	last_failure_at = GREATEST(endpoint_health.last_failure_at, EXCLUDED.last_failure_at),
+	updated_at = NOW()
+RETURNING *`, constants.EndpointHealthWindow)
+
+func (dao *endpointHealthDAO) Record(ctx context.Context, endpoint *entities.Endpoint, success bool, at time.Time) (*entities.EndpointHealth, error) {
+	ctx, span := tracing.Start(ctx, "dao.endpoint_health.record", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	var (
+		rate                         float64
+		failures                     int64
+		lastSuccessAt, lastFailureAt *types.Time
+	)
+	if success {
+		rate = 1
+		lastSuccessAt = &types.Time{Time: at}
+	} else {
+		failures = 1
+		lastFailureAt = &types.Time{Time: at}
+	}
+
+	args := []interface{}{endpoint.ID, rate, failures, lastSuccessAt, lastFailureAt, endpoint.WorkspaceId}
+	dao.debugSQL(recordSQL, args)
+	health := &entities.EndpointHealth{}
+	err := dao.UnsafeDB(ctx).QueryRowxContext(ctx, recordSQL, args...).StructScan(health)
+	if err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// UpdateStatus transitions the status from `from` to `to`, it returns false if the status has
+// been changed by others, so that a transition is only observed once across nodes.
+func (dao *endpointHealthDAO) UpdateStatus(ctx context.Context, id string, from, to entities.EndpointHealthStatus) (bool, error) {
+	statement, args := psql.Update(dao.opts.Table).
+		Set("status", to).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": from}).
+		MustSql()
+	dao.debugSQL(statement, args)
+	result, err := dao.DB(ctx).ExecContext(ctx, statement, args...)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
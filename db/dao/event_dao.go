@@ -32,9 +32,9 @@ func (dao *eventDao) BatchInsertIgnoreConflict(ctx context.Context, events []*en
 		return
 	}
 
-	builder := psql.Insert(dao.opts.Table).Columns("id", "data", "event_type", "ingested_at", "ws_id", "unique_id")
+	builder := psql.Insert(dao.opts.Table).Columns("id", "data", "event_type", "ingested_at", "ws_id", "unique_id", "source_id")
 	for _, event := range events {
-		builder = builder.Values(event.ID, event.Data, event.EventType, event.IngestedAt, event.WorkspaceId, event.UniqueId)
+		builder = builder.Values(event.ID, event.Data, event.EventType, event.IngestedAt, event.WorkspaceId, event.UniqueId, event.SourceId)
 	}
 	statement, args := builder.Suffix("ON CONFLICT(unique_id) DO NOTHING RETURNING id").MustSql()
 	var rows *sqlx.Rows
@@ -43,8 +43,8 @@ func (dao *eventDao) BatchInsertIgnoreConflict(ctx context.Context, events []*en
 		if is23505(err) { // id conflict
 			for _, event := range events {
 				statement, args := psql.Insert(dao.opts.Table).
-					Columns("id", "data", "event_type", "ingested_at", "ws_id", "unique_id").
-					Values(event.ID, event.Data, event.EventType, event.IngestedAt, event.WorkspaceId, event.UniqueId).
+					Columns("id", "data", "event_type", "ingested_at", "ws_id", "unique_id", "source_id").
+					Values(event.ID, event.Data, event.EventType, event.IngestedAt, event.WorkspaceId, event.UniqueId, event.SourceId).
 					Suffix("ON CONFLICT(unique_id) DO NOTHING RETURNING id").MustSql()
 				var id string
 				if e := dao.DB(ctx).GetContext(ctx, &id, statement, args...); e != nil {
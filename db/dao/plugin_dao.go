@@ -27,16 +27,33 @@ func NewPluginDAO(db *sqlx.DB, bus *eventbus.EventBus, workspace bool) PluginDAO
 	}
 }
 
+// ListEndpointPlugin returns the plugins applied to the endpoint, including disabled ones
 func (dao *pluginDAO) ListEndpointPlugin(ctx context.Context, endpointId string) ([]*entities.Plugin, error) {
 	q := query.PluginQuery{}
 	q.EndpointId = &endpointId
-	q.Enabled = utils.Pointer(true)
 	return dao.List(ctx, &q)
 }
 
+// ListSourcePlugin returns the plugins applied to the source, including disabled ones
 func (dao *pluginDAO) ListSourcePlugin(ctx context.Context, sourceId string) ([]*entities.Plugin, error) {
 	q := query.PluginQuery{}
 	q.SourceId = &sourceId
-	q.Enabled = utils.Pointer(true)
+	return dao.List(ctx, &q)
+}
+
+// ListWorkspacePlugin returns the plugins applied to the whole workspace
+func (dao *pluginDAO) ListWorkspacePlugin(ctx context.Context, workspaceId string) ([]*entities.Plugin, error) {
+	q := query.PluginQuery{}
+	q.WorkspaceId = &workspaceId
+	q.Global = utils.Pointer(false)
+	q.Unbound = true
+	return dao.List(ctx, &q)
+}
+
+// ListGlobalPlugin returns the plugins applied to all workspaces
+func (dao *pluginDAO) ListGlobalPlugin(ctx context.Context) ([]*entities.Plugin, error) {
+	q := query.PluginQuery{}
+	q.Global = utils.Pointer(true)
+	q.Unbound = true
 	return dao.List(ctx, &q)
 }
@@ -24,6 +24,8 @@ type DB struct {
 	Workspaces       dao.WorkspaceDAO
 	Endpoints        dao.EndpointDAO
 	EndpointsWS      dao.EndpointDAO
+	EndpointHealth   dao.EndpointHealthDAO
+	EndpointHealthWS dao.EndpointHealthDAO
 	Events           dao.EventDAO
 	EventsWS         dao.EventDAO
 	Attempts         dao.AttemptDAO
@@ -34,6 +36,8 @@ type DB struct {
 	AttemptDetailsWS dao.AttemptDetailDAO
 	Plugins          dao.PluginDAO
 	PluginsWS        dao.PluginDAO
+	AttemptStats     dao.AttemptStatsDAO
+	AttemptStatsWS   dao.AttemptStatsDAO
 }
 
 func NewSqlDB(cfg config.DatabaseConfig) (*sql.DB, error) {
@@ -56,6 +60,8 @@ func NewDB(sqlDB *sql.DB, log *zap.SugaredLogger, bus *eventbus.EventBus) (*DB,
 		Workspaces:       dao.NewWorkspaceDAO(sqlxDB, bus),
 		Endpoints:        dao.NewEndpointDAO(sqlxDB, bus, false),
 		EndpointsWS:      dao.NewEndpointDAO(sqlxDB, bus, true),
+		EndpointHealth:   dao.NewEndpointHealthDAO(sqlxDB, bus, false),
+		EndpointHealthWS: dao.NewEndpointHealthDAO(sqlxDB, bus, true),
 		Events:           dao.NewEventDao(sqlxDB, bus, false),
 		EventsWS:         dao.NewEventDao(sqlxDB, bus, true),
 		Attempts:         dao.NewAttemptDao(sqlxDB, bus, false),
@@ -66,6 +72,8 @@ func NewDB(sqlDB *sql.DB, log *zap.SugaredLogger, bus *eventbus.EventBus) (*DB,
 		AttemptDetailsWS: dao.NewAttemptDetailDao(sqlxDB, bus, true),
 		Plugins:          dao.NewPluginDAO(sqlxDB, bus, false),
 		PluginsWS:        dao.NewPluginDAO(sqlxDB, bus, true),
+		AttemptStats:     dao.NewAttemptStatsDao(sqlxDB, bus, false),
+		AttemptStatsWS:   dao.NewAttemptStatsDao(sqlxDB, bus, true),
 	}
 
 	return db, nil
@@ -3,6 +3,7 @@ package entities
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"regexp"
 
 	"github.com/webhookx-io/webhookx/pkg/types"
 )
@@ -45,8 +46,20 @@ const (
 	AttemptErrorCodeEndpointDisabled AttemptErrorCode = "ENDPOINT_DISABLED"
 	AttemptErrorCodeDenied           AttemptErrorCode = "DENIED"
 	AttemptErrorCodeEndpointNotFound AttemptErrorCode = "ENDPOINT_NOT_FOUND"
+	AttemptErrorCodeAuthFailed       AttemptErrorCode = "AUTHENTICATION_FAILED"
+	AttemptErrorCodePluginLimit      AttemptErrorCode = "PLUGIN_LIMIT_EXCEEDED"
+	AttemptErrorCodePluginDropped    AttemptErrorCode = "PLUGIN_DROPPED"
 )
 
+// attemptErrorCodePattern matches error codes that fit in the error_code column, e.g. RESPONSE_REJECTED
+var attemptErrorCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,29}$`)
+
+// IsValidAttemptErrorCode reports whether the code consists of uppercase letters, digits and
+// underscores, and is at most 30 characters.
+func IsValidAttemptErrorCode(code string) bool {
+	return attemptErrorCodePattern.MatchString(code)
+}
+
 type AttemptTriggerMode = string
 
 const (
@@ -75,6 +88,10 @@ type AttemptResponse struct {
 	Latency int64   `json:"latency"`
 	Headers Headers `json:"headers"`
 	Body    *string `json:"body"`
+	// GRPCStatus is the status code returned by gRPC endpoints
+	GRPCStatus *int `json:"grpc_status,omitempty"`
+	// BodyTruncated reports whether the body is truncated to the maximum response body size
+	BodyTruncated bool `json:"body_truncated,omitempty"`
 }
 
 func (m *AttemptResponse) Scan(src interface{}) error {
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AttemptStats is the rollup of delivered attempts in a time bucket
+type AttemptStats struct {
+	Bucket         time.Time         `db:"bucket"`
+	EndpointId     string            `db:"endpoint_id"`
+	SourceId       *string           `db:"source_id"`
+	EventType      string            `db:"event_type"`
+	Status         AttemptStatus     `db:"status"`
+	ErrorCode      *AttemptErrorCode `db:"error_code"`
+	Count          int64             `db:"count"`
+	LatencyCount   int64             `db:"latency_count"`
+	LatencySum     int64             `db:"latency_sum"`
+	LatencyBuckets pq.Int64Array     `db:"latency_buckets"`
+	WorkspaceId    string            `db:"ws_id"`
+}
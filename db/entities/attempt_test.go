@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidAttemptErrorCode(t *testing.T) {
+	assert.True(t, IsValidAttemptErrorCode(AttemptErrorCodeTimeout))
+	assert.True(t, IsValidAttemptErrorCode("RESPONSE_REJECTED"))
+	assert.True(t, IsValidAttemptErrorCode("E1"))
+	assert.True(t, IsValidAttemptErrorCode(strings.Repeat("A", 30)))
+
+	assert.False(t, IsValidAttemptErrorCode(""))
+	assert.False(t, IsValidAttemptErrorCode(strings.Repeat("A", 31)))
+	assert.False(t, IsValidAttemptErrorCode("rejected"))
+	assert.False(t, IsValidAttemptErrorCode("1_REJECTED"))
+	assert.False(t, IsValidAttemptErrorCode("NOT OK"))
+}
@@ -1,20 +1,32 @@
 package entities
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/webhookx-io/webhookx/pkg/errs"
 )
 
 type Endpoint struct {
-	ID          string        `json:"id" db:"id"`
-	Name        *string       `json:"name" db:"name"`
-	Description *string       `json:"description" db:"description"`
-	Enabled     bool          `json:"enabled" db:"enabled"`
-	Request     RequestConfig `json:"request" db:"request"`
-	Retry       Retry         `json:"retry" db:"retry"`
-	Events      Strings       `json:"events" db:"events"`
-	Metadata    Metadata      `json:"metadata" db:"metadata"`
-	RateLimit   *RateLimit    `json:"rate_limit" yaml:"rate_limit" db:"rate_limit"`
+	ID             string          `json:"id" db:"id"`
+	Name           *string         `json:"name" db:"name"`
+	Description    *string         `json:"description" db:"description"`
+	Enabled        bool            `json:"enabled" db:"enabled"`
+	Type           EndpointType    `json:"type" db:"type"`
+	Request        RequestConfig   `json:"request" db:"request"`
+	Retry          Retry           `json:"retry" db:"retry"`
+	Events         Strings         `json:"events" db:"events"`
+	Metadata       Metadata        `json:"metadata" db:"metadata"`
+	RateLimit      *RateLimit      `json:"rate_limit" yaml:"rate_limit" db:"rate_limit"`
+	MaxConcurrency *int            `json:"max_concurrency" yaml:"max_concurrency" db:"max_concurrency"`
+	Health         *EndpointHealth `json:"health" yaml:"-" db:"-"`
 
 	BaseModel `yaml:"-"`
 }
@@ -23,11 +35,112 @@ func (m *Endpoint) SchemaName() string {
 	return "Endpoint"
 }
 
+func (m *Endpoint) Validate() error {
+	if m.Type != "" && m.Type != EndpointTypeHTTP {
+		if err := m.Type.validateURL(m.Request.URL); err != nil {
+			return newRequestFieldValidateError("url", err)
+		}
+		if m.Request.Auth != nil && m.Type.Broker() && m.Request.Auth.Type != AuthTypeBasic {
+			return newRequestFieldValidateError("auth", fmt.Errorf("auth type must be '%s' for endpoint type '%s'", AuthTypeBasic, m.Type))
+		}
+	}
+	if m.Request.Auth != nil {
+		if field, err := m.Request.Auth.validate(); err != nil {
+			return newRequestValidateError("auth", field, err)
+		}
+	}
+	if m.Request.TLS != nil {
+		if field, err := m.Request.TLS.validate(); err != nil {
+			return newRequestValidateError("tls", field, err)
+		}
+	}
+	return validateSecrets(m)
+}
+
+func newRequestValidateError(name string, field string, err error) error {
+	e := errs.NewValidateError(errors.New("request validation"))
+	e.Fields["request"] = map[string]interface{}{
+		name: map[string]interface{}{
+			field: err.Error(),
+		},
+	}
+	return e
+}
+
+func newRequestFieldValidateError(field string, err error) error {
+	e := errs.NewValidateError(errors.New("request validation"))
+	e.Fields["request"] = map[string]interface{}{
+		field: err.Error(),
+	}
+	return e
+}
+
+type EndpointType string
+
+const (
+	EndpointTypeHTTP  EndpointType = "http"
+	EndpointTypeKafka EndpointType = "kafka"
+	EndpointTypeAMQP  EndpointType = "amqp"
+	EndpointTypeNATS  EndpointType = "nats"
+	EndpointTypeGRPC  EndpointType = "grpc"
+)
+
+// endpointURLSchemes is the allowed request URL schemes of non-http endpoint types
+var endpointURLSchemes = map[EndpointType][]string{
+	EndpointTypeKafka: {"kafka"},
+	EndpointTypeAMQP:  {"amqp", "amqps"},
+	EndpointTypeNATS:  {"nats", "tls"},
+	EndpointTypeGRPC:  {"grpc", "grpcs"},
+}
+
+// Broker reports whether the endpoint type delivers to message brokers, which authenticate with basic auth
+func (t EndpointType) Broker() bool {
+	return t == EndpointTypeKafka || t == EndpointTypeAMQP || t == EndpointTypeNATS
+}
+
+// validateURL validates the request URL of non-http endpoint types, the URL locates the gRPC server,
+// or the broker and the topic (Kafka), the exchange and routing key (AMQP) or the subject (NATS) to publish to.
+func (t EndpointType) validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid url: %s", err)
+	}
+	schemes := endpointURLSchemes[t]
+	if !slices.Contains(schemes, u.Scheme) {
+		return fmt.Errorf("url scheme must be one of [%s] for endpoint type '%s'", strings.Join(schemes, ", "), t)
+	}
+	if u.Host == "" {
+		return errors.New("url host is required")
+	}
+	if u.User != nil {
+		// credentials in the url would be stored and returned in plaintext
+		return errors.New("url must not contain credentials, use auth instead")
+	}
+	switch t {
+	case EndpointTypeKafka:
+		if strings.Trim(u.Path, "/") == "" {
+			return errors.New("url path must be the topic")
+		}
+	case EndpointTypeNATS:
+		if strings.Trim(u.Path, "/") == "" {
+			return errors.New("url path must be the subject")
+		}
+	case EndpointTypeAMQP:
+		q := u.Query()
+		if q.Get("exchange") == "" && q.Get("routing_key") == "" {
+			return errors.New("url query must specify exchange or routing_key")
+		}
+	}
+	return nil
+}
+
 type RequestConfig struct {
-	URL     string  `json:"url"`
-	Method  string  `json:"method"`
-	Headers Headers `json:"headers"`
-	Timeout int64   `json:"timeout"`
+	URL     string      `json:"url"`
+	Method  string      `json:"method"`
+	Headers Headers     `json:"headers"`
+	Timeout int64       `json:"timeout"`
+	Auth    *AuthConfig `json:"auth"`
+	TLS     *TLSConfig  `json:"tls"`
 }
 
 func (m *RequestConfig) Scan(src interface{}) error {
@@ -38,6 +151,106 @@ func (m RequestConfig) Value() (driver.Value, error) {
 	return json.Marshal(m)
 }
 
+type AuthType string
+
+const (
+	AuthTypeBasic                   AuthType = "basic"
+	AuthTypeBearer                  AuthType = "bearer"
+	AuthTypeOAuth2ClientCredentials AuthType = "oauth2_client_credentials"
+)
+
+// AuthConfig is the outbound authentication applied to delivery requests
+type AuthConfig struct {
+	Type                    AuthType                 `json:"type"`
+	Basic                   *BasicAuth               `json:"basic"`
+	Bearer                  *BearerAuth              `json:"bearer"`
+	OAuth2ClientCredentials *OAuth2ClientCredentials `json:"oauth2_client_credentials" yaml:"oauth2_client_credentials"`
+}
+
+func (m *AuthConfig) validate() (field string, err error) {
+	switch m.Type {
+	case AuthTypeBasic:
+		if m.Basic == nil {
+			return "basic", fmt.Errorf("basic is required for auth type '%s'", m.Type)
+		}
+		if m.Basic.Username == "" {
+			return "basic", errors.New("username is required")
+		}
+	case AuthTypeBearer:
+		if m.Bearer == nil {
+			return "bearer", fmt.Errorf("bearer is required for auth type '%s'", m.Type)
+		}
+		if m.Bearer.Token == "" {
+			return "bearer", errors.New("token is required")
+		}
+	case AuthTypeOAuth2ClientCredentials:
+		cfg := m.OAuth2ClientCredentials
+		if cfg == nil {
+			return "oauth2_client_credentials", fmt.Errorf("oauth2_client_credentials is required for auth type '%s'", m.Type)
+		}
+		switch {
+		case cfg.TokenURL == "":
+			return "oauth2_client_credentials", errors.New("token_url is required")
+		case cfg.ClientId == "":
+			return "oauth2_client_credentials", errors.New("client_id is required")
+		case cfg.ClientSecret == "":
+			return "oauth2_client_credentials", errors.New("client_secret is required")
+		}
+		if u, err := url.Parse(cfg.TokenURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "oauth2_client_credentials", errors.New("token_url must be an absolute http(s) URL")
+		}
+	default:
+		return "type", fmt.Errorf("unknown auth type '%s'", m.Type)
+	}
+	return "", nil
+}
+
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type BearerAuth struct {
+	Token string `json:"token"`
+}
+
+type OAuth2ClientCredentials struct {
+	TokenURL     string   `json:"token_url" yaml:"token_url"`
+	ClientId     string   `json:"client_id" yaml:"client_id"`
+	ClientSecret string   `json:"client_secret" yaml:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	Audience     string   `json:"audience"`
+}
+
+// TLSConfig is the TLS configuration used to connect to the endpoint
+type TLSConfig struct {
+	ClientCert string `json:"client_cert" yaml:"client_cert"`
+	ClientKey  string `json:"client_key" yaml:"client_key"`
+	CACert     string `json:"ca_cert" yaml:"ca_cert"`
+	ServerName string `json:"server_name" yaml:"server_name"`
+	MinVersion string `json:"min_version" yaml:"min_version"`
+}
+
+// TLSVersions is the supported values of min_version
+var TLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+func (m *TLSConfig) validate() (field string, err error) {
+	if m.MinVersion != "" && !slices.Contains(TLSVersions, m.MinVersion) {
+		return "min_version", fmt.Errorf("min_version must be one of [%s]", strings.Join(TLSVersions, ", "))
+	}
+	if m.ClientCert != "" || m.ClientKey != "" {
+		if _, err := tls.X509KeyPair([]byte(m.ClientCert), []byte(m.ClientKey)); err != nil {
+			return "client_cert", fmt.Errorf("invalid client certificate: %s", err)
+		}
+	}
+	if m.CACert != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(m.CACert)) {
+			return "ca_cert", errors.New("invalid ca certificate")
+		}
+	}
+	return "", nil
+}
+
 type RetryStrategy string
 
 const (
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"github.com/webhookx-io/webhookx/pkg/types"
+)
+
+type EndpointHealthStatus string
+
+const (
+	EndpointHealthStatusHealthy EndpointHealthStatus = "HEALTHY"
+	EndpointHealthStatusFailing EndpointHealthStatus = "FAILING"
+)
+
+// EndpointHealth is the delivery health of an endpoint, updated by the worker on every attempt
+type EndpointHealth struct {
+	ID                  string               `json:"-" db:"id"`
+	Status              EndpointHealthStatus `json:"status" db:"status"`
+	SuccessRate         float64              `json:"success_rate" db:"success_rate"`
+	ConsecutiveFailures int64                `json:"consecutive_failures" db:"consecutive_failures"`
+	LastSuccessAt       *types.Time          `json:"last_success_at" db:"last_success_at"`
+	LastFailureAt       *types.Time          `json:"last_failure_at" db:"last_failure_at"`
+
+	BaseModel
+}
@@ -12,6 +12,10 @@ type Event struct {
 	Data       json.RawMessage `json:"data" validate:"required"`
 	IngestedAt types.Time      `json:"ingested_at" db:"ingested_at"`
 	UniqueId   *string         `json:"unique_id" db:"unique_id" validate:"omitempty,max=50"`
+	SourceId   *string         `json:"source_id" db:"source_id"`
+
+	// TraceParent is the W3C traceparent captured at ingestion, it is not persisted
+	TraceParent string `json:"-" db:"-"`
 
 	BaseModel
 }
@@ -1,12 +1,16 @@
 package entities
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/creasty/defaults"
 	"github.com/webhookx-io/webhookx/pkg/errs"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/secret"
+	"slices"
+	"sort"
 )
 
 type Plugin struct {
@@ -17,6 +21,15 @@ type Plugin struct {
 	SourceId   *string             `json:"source_id" db:"source_id" yaml:"source_id"`
 	Config     PluginConfiguration `json:"config" db:"config"`
 	Metadata   Metadata            `json:"metadata" db:"metadata"`
+	// Priority overrides the default execution priority of the plugin, plugins with higher priority are executed first
+	Priority *int `json:"priority" db:"priority"`
+	// Condition restricts the execution of the plugin, the plugin is always executed if it is nil
+	Condition *PluginCondition `json:"condition" db:"condition"`
+	// Global indicates the plugin is applied to all workspaces
+	Global bool `json:"global" db:"global"`
+	// Side restricts a workspace or global plugin to the requests received by sources (inbound)
+	// or delivered to endpoints (outbound), it is required if the plugin can be applied to both
+	Side *plugin.Type `json:"side" db:"side"`
 
 	BaseModel `yaml:"-"`
 }
@@ -32,16 +45,33 @@ func (m *Plugin) Validate() error {
 		e.Fields["name"] = fmt.Sprintf("unknown plugin name '%s'", m.Name)
 		return e
 	}
-	if r.Type == plugin.TypeInbound && m.SourceId == nil {
+	if r.Type == plugin.TypeInbound && m.EndpointId != nil {
 		e := errs.NewValidateError(errors.New("request validation"))
-		e.Fields["source_id"] = fmt.Sprintf("source_id is required for plugin '%s'", m.Name)
+		e.Fields["endpoint_id"] = fmt.Sprintf("plugin '%s' cannot be applied to endpoints", m.Name)
 		return e
 	}
-	if r.Type == plugin.TypeOutbound && m.EndpointId == nil {
+	if r.Type == plugin.TypeOutbound && m.SourceId != nil {
 		e := errs.NewValidateError(errors.New("request validation"))
-		e.Fields["endpoint_id"] = fmt.Sprintf("endpoint_id is required for plugin '%s'", m.Name)
+		e.Fields["source_id"] = fmt.Sprintf("plugin '%s' cannot be applied to sources", m.Name)
 		return e
 	}
+	if m.Global && (m.EndpointId != nil || m.SourceId != nil) {
+		e := errs.NewValidateError(errors.New("request validation"))
+		e.Fields["global"] = "global plugins cannot be applied to an endpoint or a source"
+		return e
+	}
+	if err := m.validateSide(r); err != nil {
+		return err
+	}
+	if m.Condition != nil {
+		if field, err := m.Condition.validate(); err != nil {
+			e := errs.NewValidateError(errors.New("request validation"))
+			e.Fields["condition"] = map[string]interface{}{
+				field: err.Error(),
+			}
+			return e
+		}
+	}
 
 	// validate plugin configuration
 	p, err := m.Plugin()
@@ -57,6 +87,90 @@ func (m *Plugin) Validate() error {
 		}
 		return err
 	}
+	if err = m.validateReferences(r); err != nil {
+		return err
+	}
+	return validateSecrets(m)
+}
+
+func (m *Plugin) validateSide(r *plugin.Registration) error {
+	unbound := m.EndpointId == nil && m.SourceId == nil
+	var message string
+	switch {
+	case m.Side == nil:
+		if unbound && r.Type == plugin.TypeAny {
+			message = fmt.Sprintf("side is required as plugin '%s' can be applied to inbound and outbound requests", m.Name)
+		}
+	case !unbound:
+		message = "side cannot be set for plugins applied to an endpoint or a source"
+	case *m.Side != plugin.TypeInbound && *m.Side != plugin.TypeOutbound:
+		message = "value must be one of [inbound outbound]"
+	case r.Type != plugin.TypeAny && r.Type != *m.Side:
+		message = fmt.Sprintf("plugin '%s' cannot be applied to %s requests", m.Name, *m.Side)
+	}
+	if message != "" {
+		e := errs.NewValidateError(errors.New("request validation"))
+		e.Fields["side"] = message
+		return e
+	}
+	return nil
+}
+
+// ExecutionSide returns the side of requests that the plugin is executed on,
+// it is TypeAny for plugins executed on inbound and outbound requests.
+func (m *Plugin) ExecutionSide() plugin.Type {
+	switch {
+	case m.Side != nil:
+		return *m.Side
+	case m.EndpointId != nil:
+		return plugin.TypeOutbound
+	case m.SourceId != nil:
+		return plugin.TypeInbound
+	}
+	if r := plugin.GetRegistration(m.Name); r != nil {
+		return r.Type
+	}
+	return plugin.TypeAny
+}
+
+// overlaps reports whether the plugins can be executed on the same request
+func (m *Plugin) overlaps(other *Plugin) bool {
+	a, b := m.ExecutionSide(), other.ExecutionSide()
+	return a == plugin.TypeAny || b == plugin.TypeAny || a == b
+}
+
+// validateReferences rejects secret references in fields that are not sensitive,
+// and references that are not allowed by the secret policy.
+func (m *Plugin) validateReferences(r *plugin.Registration) error {
+	if !secret.HasReference(string(m.Config)) {
+		return nil
+	}
+	config := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(m.Config, &config); err != nil {
+		return err
+	}
+	fields := make(map[string]interface{})
+	for name, raw := range config {
+		if !secret.HasReference(string(raw)) {
+			continue
+		}
+		if !slices.Contains(r.SensitiveFields, name) {
+			fields[name] = "secret references are only allowed in sensitive fields"
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue // not a string
+		}
+		if err := secret.GetPolicy().Check(value); err != nil {
+			fields[name] = err.Error()
+		}
+	}
+	if len(fields) > 0 {
+		e := errs.NewValidateError(errors.New("request validation"))
+		e.Fields["config"] = fields
+		return e
+	}
 	return nil
 }
 
@@ -69,19 +183,185 @@ func (m *Plugin) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, (*alias)(m))
 }
 
+// Unbound reports whether the plugin is applied to a whole workspace, or to all workspaces if it is global,
+// rather than an endpoint or a source.
+func (m *Plugin) Unbound() bool {
+	return m.EndpointId == nil && m.SourceId == nil
+}
+
+// ExecutionPriority returns the priority of the plugin, which defaults to the priority of its registration
+func (m *Plugin) ExecutionPriority() int {
+	if m.Priority != nil {
+		return *m.Priority
+	}
+	if r := plugin.GetRegistration(m.Name); r != nil {
+		return r.Priority
+	}
+	return plugin.DefaultPriority
+}
+
+// MatchOutbound reports whether the plugin should be executed for the outbound request
+func (m *Plugin) MatchOutbound(outbound *plugin.Outbound, context *plugin.Context) (bool, error) {
+	if m.Condition == nil {
+		return true, nil
+	}
+	var eventType string
+	if context != nil && context.Event != nil {
+		eventType = context.Event.EventType
+	}
+	return m.Condition.match(eventType, func() interface{} {
+		return plugin.OutboundDocument(outbound, context)
+	})
+}
+
+// MatchInbound reports whether the plugin should be executed for the inbound request,
+// the event type is read from the request body.
+func (m *Plugin) MatchInbound(inbound *plugin.Inbound) (bool, error) {
+	if m.Condition == nil {
+		return true, nil
+	}
+	var body struct {
+		EventType string `json:"event_type"`
+	}
+	_ = json.Unmarshal(inbound.RawBody, &body)
+	return m.Condition.match(body.EventType, func() interface{} {
+		return plugin.InboundDocument(inbound)
+	})
+}
+
 func (m *Plugin) Plugin() (plugin.Plugin, error) {
+	return m.newPlugin(m.Config)
+}
+
+// Executor returns the plugin for execution, secret references in sensitive fields of the configuration are resolved
+func (m *Plugin) Executor(ctx context.Context) (plugin.Plugin, error) {
+	p := *m
+	err := p.TransformSecrets(func(_ string, value string) (string, error) {
+		if err := secret.GetPolicy().Check(value); err != nil {
+			return "", err
+		}
+		return secret.Resolve(ctx, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.newPlugin(p.Config)
+}
+
+// InboundExecutor returns the plugin for inbound execution
+func (m *Plugin) InboundExecutor(ctx context.Context) (plugin.InboundPlugin, error) {
+	executor, err := m.Executor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := executor.(plugin.InboundPlugin)
+	if !ok {
+		return nil, fmt.Errorf("plugin '%s' cannot be applied to sources", m.Name)
+	}
+	return p, nil
+}
+
+// OutboundExecutor returns the plugin for outbound execution
+func (m *Plugin) OutboundExecutor(ctx context.Context) (plugin.OutboundPlugin, error) {
+	executor, err := m.Executor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := executor.(plugin.OutboundPlugin)
+	if !ok {
+		return nil, fmt.Errorf("plugin '%s' cannot be applied to endpoints", m.Name)
+	}
+	return p, nil
+}
+
+func (m *Plugin) newPlugin(config []byte) (plugin.Plugin, error) {
 	r := plugin.GetRegistration(m.Name)
 	if r == nil {
 		return nil, fmt.Errorf("unknown plugin name: '%s'", m.Name)
 	}
 
-	executor, err := r.New(m.Config)
+	executor, err := r.NewPlugin(config)
 	if err != nil {
 		return nil, err
 	}
 	return executor, nil
 }
 
+// SortPlugins sorts plugins in execution order, plugins with higher priority are executed first
+func SortPlugins(plugins []*Plugin) {
+	sort.SliceStable(plugins, func(i, j int) bool {
+		pi, pj := plugins[i].ExecutionPriority(), plugins[j].ExecutionPriority()
+		if pi != pj {
+			return pi > pj
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+}
+
+// ResolvePlugins returns the enabled plugins to execute in execution order. The levels are ordered from the
+// lowest to the highest precedence, e.g. global, workspace and endpoint plugins, and a plugin overrides the
+// plugins with the same name in lower levels even if it is disabled. Plugins of lower levels that cannot
+// be applied to requests of the type, or whose side is the other side, are ignored. Plugins of different levels with the same priority
+// are executed from the highest level to the lowest.
+func ResolvePlugins(typ plugin.Type, levels ...[]*Plugin) []*Plugin {
+	resolved := make(map[string]*Plugin)
+	resolvedLevels := make(map[string]int)
+	for i, level := range levels {
+		for _, p := range level {
+			if i < len(levels)-1 {
+				if side := p.ExecutionSide(); side != plugin.TypeAny && side != typ {
+					continue
+				}
+			}
+			resolved[p.Name] = p
+			resolvedLevels[p.Name] = i
+		}
+	}
+
+	plugins := make([]*Plugin, 0, len(resolved))
+	for _, p := range resolved {
+		if p.Enabled {
+			plugins = append(plugins, p)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		pi, pj := plugins[i].ExecutionPriority(), plugins[j].ExecutionPriority()
+		if pi != pj {
+			return pi > pj
+		}
+		li, lj := resolvedLevels[plugins[i].Name], resolvedLevels[plugins[j].Name]
+		if li != lj {
+			return li > lj
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// CheckPluginConflicts checks the plugins applied to the same endpoint, source or workspace, two plugins
+// executed on the same side conflict if they have the same name or the same priority as their execution
+// order is undefined. Plugins of different levels may have the same priority, ResolvePlugins orders them by level.
+func CheckPluginConflicts(plugins []*Plugin) error {
+	for i, p := range plugins {
+		for _, other := range plugins[:i] {
+			if !p.overlaps(other) {
+				continue
+			}
+			if p.Name == other.Name {
+				e := errs.NewValidateError(errs.ErrRequestValidation)
+				e.Fields["name"] = fmt.Sprintf("plugin '%s' is already applied", p.Name)
+				return e
+			}
+			if priority := p.ExecutionPriority(); priority == other.ExecutionPriority() {
+				e := errs.NewValidateError(errs.ErrRequestValidation)
+				e.Fields["priority"] = fmt.Sprintf("priority %d conflicts with plugin '%s'", priority, other.Name)
+				return e
+			}
+		}
+	}
+	return nil
+}
+
 type PluginConfiguration json.RawMessage
 
 func (m PluginConfiguration) MarshalYAML() (interface{}, error) {
@@ -0,0 +1,134 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/blues/jsonata-go"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+var (
+	conditionLimitsMux sync.RWMutex
+	conditionLimits    = plugin.Limits{Timeout: time.Second}
+)
+
+// SetConditionLimits sets the resource limits of condition expression evaluations
+func SetConditionLimits(l plugin.Limits) {
+	conditionLimitsMux.Lock()
+	defer conditionLimitsMux.Unlock()
+	conditionLimits = l
+}
+
+func getConditionLimits() plugin.Limits {
+	conditionLimitsMux.RLock()
+	defer conditionLimitsMux.RUnlock()
+	return conditionLimits
+}
+
+// PluginCondition restricts the execution of a plugin, the plugin is executed only if all conditions are met.
+type PluginCondition struct {
+	// EventTypes is the event type patterns to match, e.g. "order.*"
+	EventTypes Strings `json:"event_types" yaml:"event_types"`
+	// Expression is a JSONata expression evaluated against the request document,
+	// the condition is met if the result is truthy.
+	Expression string `json:"expression"`
+}
+
+func (m *PluginCondition) Scan(src interface{}) error {
+	return json.Unmarshal(src.([]byte), m)
+}
+
+func (m PluginCondition) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+var expressions, _ = lru.New[string, *jsonata.Expr](1024)
+
+func compileExpression(source string) (*jsonata.Expr, error) {
+	if expr, ok := expressions.Get(source); ok {
+		return expr, nil
+	}
+	expr, err := jsonata.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	expressions.Add(source, expr)
+	return expr, nil
+}
+
+func (m *PluginCondition) validate() (string, error) {
+	for _, pattern := range m.EventTypes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return "event_types", fmt.Errorf("invalid pattern '%s'", pattern)
+		}
+	}
+	if m.Expression != "" {
+		if _, err := compileExpression(m.Expression); err != nil {
+			return "expression", fmt.Errorf("invalid expression: %v", err)
+		}
+	}
+	return "", nil
+}
+
+// match reports whether the event type and the document returned by fn meet the condition
+func (m *PluginCondition) match(eventType string, fn func() interface{}) (bool, error) {
+	if len(m.EventTypes) > 0 {
+		matched := slices.ContainsFunc(m.EventTypes, func(pattern string) bool {
+			ok, _ := path.Match(pattern, eventType)
+			return ok
+		})
+		if !matched {
+			return false, nil
+		}
+	}
+
+	if m.Expression != "" {
+		expr, err := compileExpression(m.Expression)
+		if err != nil {
+			return false, err
+		}
+		input := fn()
+		var v interface{}
+		err = getConditionLimits().Run(func() error {
+			value, err := expr.Eval(input)
+			v = value
+			return err
+		})
+		if err == jsonata.ErrUndefined {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate condition: %w", err)
+		}
+		return truthy(v), nil
+	}
+
+	return true, nil
+}
+
+// truthy follows the boolean casting rules of JSONata
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	case []interface{}:
+		return slices.ContainsFunc(v, truthy)
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
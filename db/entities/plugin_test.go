@@ -0,0 +1,254 @@
+package entities
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+type noopPlugin struct {
+	plugin.BasePlugin[struct{}]
+}
+
+func (p *noopPlugin) ValidateConfig() error {
+	return nil
+}
+
+func (p *noopPlugin) ExecuteInbound(inbound *plugin.Inbound) (plugin.InboundResult, error) {
+	return plugin.InboundResult{Payload: inbound.RawBody}, nil
+}
+
+func (p *noopPlugin) ExecuteOutbound(outbound *plugin.Outbound, context *plugin.Context) error {
+	return nil
+}
+
+func newNoopPlugin(config []byte) (plugin.Plugin, error) {
+	return &noopPlugin{}, nil
+}
+
+func init() {
+	plugin.RegisterPlugin(plugin.TypeAny, "entities-high", newNoopPlugin, plugin.WithPriority(100))
+	plugin.RegisterPlugin(plugin.TypeAny, "entities-low", newNoopPlugin, plugin.WithPriority(10))
+	plugin.RegisterPlugin(plugin.TypeInbound, "entities-inbound", newNoopPlugin, plugin.WithPriority(50))
+}
+
+func TestSortPlugins(t *testing.T) {
+	plugins := []*Plugin{
+		{Name: "entities-low"},
+		{Name: "unknown"},
+		{Name: "entities-high"},
+		{Name: "entities-low-override", Priority: utils.Pointer(50)},
+	}
+	SortPlugins(plugins)
+
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"entities-high", "entities-low-override", "entities-low", "unknown"}, names)
+}
+
+func TestCheckPluginConflicts(t *testing.T) {
+	assert.NoError(t, CheckPluginConflicts([]*Plugin{
+		{Name: "entities-high"},
+		{Name: "entities-low"},
+	}))
+
+	err := CheckPluginConflicts([]*Plugin{
+		{Name: "entities-high"},
+		{Name: "entities-low", Priority: utils.Pointer(100)},
+	})
+	assert.Equal(t, map[string]interface{}{"priority": "priority 100 conflicts with plugin 'entities-high'"}, err.(*errs.ValidateError).Fields)
+
+	err = CheckPluginConflicts([]*Plugin{
+		{Name: "entities-high"},
+		{Name: "entities-high", Priority: utils.Pointer(1)},
+	})
+	assert.Equal(t, map[string]interface{}{"name": "plugin 'entities-high' is already applied"}, err.(*errs.ValidateError).Fields)
+}
+
+func TestMatchOutbound(t *testing.T) {
+	outbound := &plugin.Outbound{Payload: `{"amount": 100}`}
+	context := &plugin.Context{
+		Event:    &plugin.Event{ID: "evt", EventType: "order.paid"},
+		Endpoint: &plugin.Endpoint{ID: "ep", Metadata: map[string]string{"env": "prod"}},
+	}
+
+	tests := []struct {
+		condition *PluginCondition
+		expected  bool
+	}{
+		{nil, true},
+		{&PluginCondition{EventTypes: []string{"order.paid"}}, true},
+		{&PluginCondition{EventTypes: []string{"order.*"}}, true},
+		{&PluginCondition{EventTypes: []string{"user.*", "order.refunded"}}, false},
+		{&PluginCondition{Expression: `event.data.amount > 50`}, true},
+		{&PluginCondition{Expression: `endpoint.metadata.env = "dev"`}, false},
+		{&PluginCondition{Expression: `event.data.missing`}, false},
+		{&PluginCondition{EventTypes: []string{"order.*"}, Expression: `event.data.amount > 500`}, false},
+	}
+	for _, test := range tests {
+		p := &Plugin{Name: "entities-high", Condition: test.condition}
+		matched, err := p.MatchOutbound(outbound, context)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, matched, "%+v", test.condition)
+	}
+}
+
+func TestMatchOutboundLimits(t *testing.T) {
+	defer SetConditionLimits(getConditionLimits())
+	SetConditionLimits(plugin.Limits{Timeout: time.Millisecond})
+
+	p := &Plugin{Name: "entities-high", Condition: &PluginCondition{Expression: `$sum([1..10000000]) > 0`}}
+	_, err := p.MatchOutbound(&plugin.Outbound{Payload: `{}`}, nil)
+	e, ok := plugin.AsLimitError(err)
+	assert.True(t, ok)
+	assert.Equal(t, plugin.LimitTimeout, e.Limit)
+}
+
+func TestMatchInbound(t *testing.T) {
+	r := httptest.NewRequest("POST", "/webhooks", strings.NewReader(""))
+	r.Header.Set("X-Provider", "github")
+	inbound := &plugin.Inbound{Request: r, RawBody: []byte(`{"event_type": "order.paid"}`)}
+
+	p := &Plugin{Name: "entities-high", Condition: &PluginCondition{
+		EventTypes: []string{"order.*"},
+		Expression: `request.headers.` + "`X-Provider`" + ` = "github" and request.method = "POST"`,
+	}}
+	matched, err := p.MatchInbound(inbound)
+	assert.NoError(t, err)
+	assert.True(t, matched)
+
+	p.Condition.EventTypes = []string{"user.*"}
+	matched, err = p.MatchInbound(inbound)
+	assert.NoError(t, err)
+	assert.False(t, matched)
+}
+
+func TestPluginConditionValidate(t *testing.T) {
+	p := &Plugin{Name: "entities-high", EndpointId: utils.Pointer("ep"), Condition: &PluginCondition{Expression: "event.("}}
+	err := p.Validate()
+	fields := err.(*errs.ValidateError).Fields
+	assert.Contains(t, fields["condition"].(map[string]interface{})["expression"], "invalid expression")
+
+	p.Condition = &PluginCondition{EventTypes: []string{"order.["}}
+	err = p.Validate()
+	assert.Equal(t, map[string]interface{}{"condition": map[string]interface{}{"event_types": "invalid pattern 'order.['"}}, err.(*errs.ValidateError).Fields)
+}
+
+func TestPluginSideValidate(t *testing.T) {
+	side := func(typ plugin.Type) *plugin.Type { return &typ }
+	tests := []struct {
+		plugin *Plugin
+		err    string
+	}{
+		{
+			plugin: &Plugin{Name: "entities-high"},
+			err:    "side is required as plugin 'entities-high' can be applied to inbound and outbound requests",
+		},
+		{
+			plugin: &Plugin{Name: "entities-high", Global: true},
+			err:    "side is required as plugin 'entities-high' can be applied to inbound and outbound requests",
+		},
+		{
+			plugin: &Plugin{Name: "entities-high", Side: side(plugin.TypeInbound)},
+		},
+		{
+			plugin: &Plugin{Name: "entities-high", EndpointId: utils.Pointer("ep")},
+		},
+		{
+			plugin: &Plugin{Name: "entities-high", EndpointId: utils.Pointer("ep"), Side: side(plugin.TypeOutbound)},
+			err:    "side cannot be set for plugins applied to an endpoint or a source",
+		},
+		{
+			plugin: &Plugin{Name: "entities-high", Side: side(plugin.TypeAny)},
+			err:    "value must be one of [inbound outbound]",
+		},
+		{
+			plugin: &Plugin{Name: "entities-inbound"},
+		},
+		{
+			plugin: &Plugin{Name: "entities-inbound", Side: side(plugin.TypeOutbound)},
+			err:    "plugin 'entities-inbound' cannot be applied to outbound requests",
+		},
+	}
+	for _, test := range tests {
+		err := test.plugin.Validate()
+		if test.err == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, map[string]interface{}{"side": test.err}, err.(*errs.ValidateError).Fields)
+	}
+}
+
+func TestPluginSideConflicts(t *testing.T) {
+	inbound, outbound := plugin.TypeInbound, plugin.TypeOutbound
+	assert.NoError(t, CheckPluginConflicts([]*Plugin{
+		{Name: "entities-high", Side: &inbound},
+		{Name: "entities-high", Side: &outbound},
+	}))
+	err := CheckPluginConflicts([]*Plugin{
+		{Name: "entities-high", Side: &inbound},
+		{Name: "entities-low", Priority: utils.Pointer(100), Side: &inbound},
+	})
+	assert.Equal(t, map[string]interface{}{"priority": "priority 100 conflicts with plugin 'entities-high'"}, err.(*errs.ValidateError).Fields)
+
+	global := []*Plugin{{ID: "g1", Name: "entities-high", Enabled: true, Global: true, Side: &inbound}}
+	workspace := []*Plugin{{ID: "w1", Name: "entities-low", Enabled: true, Side: &outbound}}
+	ids := func(plugins []*Plugin) []string {
+		ids := make([]string, 0)
+		for _, p := range plugins {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"g1"}, ids(ResolvePlugins(plugin.TypeInbound, global, workspace, nil)))
+	assert.Equal(t, []string{"w1"}, ids(ResolvePlugins(plugin.TypeOutbound, global, workspace, nil)))
+}
+
+func TestResolvePlugins(t *testing.T) {
+
+	global := []*Plugin{
+		{ID: "g1", Name: "entities-high", Enabled: true, Global: true},
+		{ID: "g2", Name: "entities-inbound", Enabled: true, Global: true},
+	}
+	workspace := []*Plugin{
+		{ID: "w1", Name: "entities-high", Enabled: true},
+		{ID: "w2", Name: "entities-low", Enabled: true},
+	}
+
+	ids := func(plugins []*Plugin) []string {
+		var ids []string
+		for _, p := range plugins {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"w1", "w2"}, ids(ResolvePlugins(plugin.TypeOutbound, global, workspace, nil)))
+	assert.Equal(t, []string{"w1", "g2", "w2"}, ids(ResolvePlugins(plugin.TypeInbound, global, workspace, nil)))
+
+	endpoint := []*Plugin{
+		{ID: "e1", Name: "entities-low", Enabled: true, EndpointId: utils.Pointer("ep"), Priority: utils.Pointer(200)},
+		{ID: "e2", Name: "entities-high", Enabled: false, EndpointId: utils.Pointer("ep")},
+	}
+	assert.Equal(t, []string{"e1"}, ids(ResolvePlugins(plugin.TypeOutbound, global, workspace, endpoint)))
+
+	// plugins with the same priority are ordered by level, then by name
+	global = []*Plugin{{ID: "g1", Name: "entities-a", Enabled: true, Global: true, Priority: utils.Pointer(10)}}
+	workspace = []*Plugin{
+		{ID: "w1", Name: "entities-b", Enabled: true, Priority: utils.Pointer(10)},
+		{ID: "w2", Name: "entities-c", Enabled: true, Priority: utils.Pointer(20)},
+	}
+	endpoint = []*Plugin{{ID: "e1", Name: "entities-z", Enabled: true, EndpointId: utils.Pointer("ep"), Priority: utils.Pointer(10)}}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []string{"w2", "e1", "w1", "g1"}, ids(ResolvePlugins(plugin.TypeOutbound, global, workspace, endpoint)))
+	}
+}
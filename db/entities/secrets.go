@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/webhookx-io/webhookx/pkg/encryption"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/secret"
+)
+
+// RedactedSecret replaces secrets in responses
+const RedactedSecret = "******"
+
+// sensitiveHeaders is the request headers whose values are treated as secrets
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"cookie":              true,
+}
+
+// SecretFunc transforms a secret, path is the location of the secret within the entity
+type SecretFunc func(path string, value string) (string, error)
+
+// SecretHolder is implemented by entities that contain secrets
+type SecretHolder interface {
+	TransformSecrets(fn SecretFunc) error
+}
+
+func transformSecrets(entity interface{}, fn SecretFunc) error {
+	holder, ok := entity.(SecretHolder)
+	if !ok {
+		// a list of entities, e.g. *[]*Plugin
+		v := reflect.ValueOf(entity)
+		if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Slice {
+			for i := 0; i < v.Elem().Len(); i++ {
+				if err := transformSecrets(v.Elem().Index(i).Interface(), fn); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return holder.TransformSecrets(func(path string, value string) (string, error) {
+		if value == "" || secret.HasReference(value) {
+			// secret references are not secrets themselves
+			return value, nil
+		}
+		return fn(path, value)
+	})
+}
+
+// validateSecrets rejects secrets in the form of encrypted values, which would be taken as ciphertext
+func validateSecrets(entity SecretHolder) error {
+	return transformSecrets(entity, func(path string, value string) (string, error) {
+		if !encryption.IsEncrypted(value) {
+			return value, nil
+		}
+		fields := map[string]interface{}{}
+		e := errs.NewValidateError(errors.New("request validation"))
+		e.Fields = fields
+		names := strings.Split(path, ".")
+		for _, name := range names[:len(names)-1] {
+			nested := map[string]interface{}{}
+			fields[name] = nested
+			fields = nested
+		}
+		fields[names[len(names)-1]] = "value must not start with '" + encryption.Prefix + "'"
+		return "", e
+	})
+}
+
+// EncryptSecrets encrypts secrets of the entity
+func EncryptSecrets(entity interface{}) error {
+	return transformSecrets(entity, func(_ string, value string) (string, error) {
+		return encryption.Encrypt(value)
+	})
+}
+
+// DecryptSecrets decrypts secrets of the entity
+func DecryptSecrets(entity interface{}) error {
+	return transformSecrets(entity, func(_ string, value string) (string, error) {
+		return encryption.Decrypt(value)
+	})
+}
+
+// RedactSecrets replaces secrets of the entity with RedactedSecret
+func RedactSecrets(entity interface{}) error {
+	return transformSecrets(entity, func(_ string, _ string) (string, error) {
+		return RedactedSecret, nil
+	})
+}
+
+// RedactHeaders returns a copy of the headers whose sensitive values are replaced with RedactedSecret
+func RedactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if sensitiveHeaders[strings.ToLower(name)] {
+			value = RedactedSecret
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// CollectSecrets returns the secrets of the entity keyed by their location
+func CollectSecrets(entity interface{}) (map[string]string, error) {
+	secrets := make(map[string]string)
+	err := transformSecrets(entity, func(path string, value string) (string, error) {
+		secrets[path] = value
+		return value, nil
+	})
+	return secrets, err
+}
+
+// RestoreSecrets replaces redacted secrets of the entity with the secrets at the same
+// location collected by CollectSecrets, so that a redacted entity can be written back.
+func RestoreSecrets(entity interface{}, secrets map[string]string) error {
+	return transformSecrets(entity, func(path string, value string) (string, error) {
+		if value == RedactedSecret {
+			if secret, ok := secrets[path]; ok {
+				return secret, nil
+			}
+		}
+		return value, nil
+	})
+}
+
+func transformSecret(fn SecretFunc, path string, value *string) error {
+	v, err := fn(path, *value)
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+func (m *Endpoint) TransformSecrets(fn SecretFunc) error {
+	for name, value := range m.Request.Headers {
+		if !sensitiveHeaders[strings.ToLower(name)] {
+			continue
+		}
+		if err := transformSecret(fn, "request.headers."+name, &value); err != nil {
+			return err
+		}
+		m.Request.Headers[name] = value
+	}
+
+	if auth := m.Request.Auth; auth != nil {
+		if auth.Basic != nil {
+			if err := transformSecret(fn, "request.auth.basic.password", &auth.Basic.Password); err != nil {
+				return err
+			}
+		}
+		if auth.Bearer != nil {
+			if err := transformSecret(fn, "request.auth.bearer.token", &auth.Bearer.Token); err != nil {
+				return err
+			}
+		}
+		if auth.OAuth2ClientCredentials != nil {
+			if err := transformSecret(fn, "request.auth.oauth2_client_credentials.client_secret", &auth.OAuth2ClientCredentials.ClientSecret); err != nil {
+				return err
+			}
+		}
+	}
+
+	if m.Request.TLS != nil {
+		if err := transformSecret(fn, "request.tls.client_key", &m.Request.TLS.ClientKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Plugin) TransformSecrets(fn SecretFunc) error {
+	r := plugin.GetRegistration(m.Name)
+	if r == nil || len(r.SensitiveFields) == 0 || len(m.Config) == 0 {
+		return nil
+	}
+
+	config := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(m.Config, &config); err != nil {
+		return err
+	}
+
+	changed := false
+	for _, field := range r.SensitiveFields {
+		raw, ok := config[field]
+		if !ok {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue // not a string
+		}
+		v, err := fn("config."+field, value)
+		if err != nil {
+			return err
+		}
+		if v != value {
+			config[field], _ = json.Marshal(v)
+			changed = true
+		}
+	}
+
+	if changed {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		m.Config = data
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package entities
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/pkg/encryption"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/pkg/secret"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+type sensitiveConfig struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret" sensitive:"true"`
+}
+
+func init() {
+	plugin.RegisterPlugin(plugin.TypeAny, "entities-sensitive", newNoopPlugin, plugin.WithConfig(sensitiveConfig{}))
+}
+
+func TestEncryptSecretsList(t *testing.T) {
+	keyring, err := encryption.NewKeyring([]string{"k1:" + base64.StdEncoding.EncodeToString(make([]byte, 32))})
+	assert.NoError(t, err)
+	encryption.Set(keyring)
+	defer encryption.Set(nil)
+
+	list := []*Plugin{
+		{Name: "entities-sensitive", Config: PluginConfiguration(`{"secret":"foo"}`)},
+		{Name: "entities-sensitive", Config: PluginConfiguration(`{"secret":"bar"}`)},
+	}
+	assert.NoError(t, EncryptSecrets(&list))
+	for _, p := range list {
+		secrets, err := CollectSecrets(p)
+		assert.NoError(t, err)
+		assert.True(t, encryption.IsEncrypted(secrets["config.secret"]))
+	}
+
+	assert.NoError(t, DecryptSecrets(&list))
+	assert.JSONEq(t, `{"secret":"foo"}`, string(list[0].Config))
+	assert.JSONEq(t, `{"secret":"bar"}`, string(list[1].Config))
+}
+
+func TestPluginSecretReferences(t *testing.T) {
+	defer secret.SetPolicy(&secret.DefaultPolicy)
+
+	tests := []struct {
+		desc   string
+		policy *secret.Policy
+		config string
+		fields map[string]interface{}
+	}{
+		{
+			desc:   "sensitive field",
+			policy: &secret.DefaultPolicy,
+			config: `{"secret":"{secret://vault/secret/data/webhookx#key}"}`,
+		},
+		{
+			desc:   "non-sensitive field",
+			policy: &secret.DefaultPolicy,
+			config: `{"name":"{secret://vault/secret/data/webhookx#key}"}`,
+			fields: map[string]interface{}{
+				"name": "secret references are only allowed in sensitive fields",
+			},
+		},
+		{
+			desc:   "provider not allowed",
+			policy: &secret.DefaultPolicy,
+			config: `{"secret":"{secret://env/WEBHOOKX_DATABASE_PASSWORD}"}`,
+			fields: map[string]interface{}{
+				"secret": "secret reference {secret://env/WEBHOOKX_DATABASE_PASSWORD} is not allowed",
+			},
+		},
+		{
+			desc:   "path not allowed",
+			policy: &secret.Policy{Providers: []string{"vault"}, PathPrefixes: []string{"secret/data/webhookx/"}},
+			config: `{"secret":"{secret://vault/secret/data/admin#key}"}`,
+			fields: map[string]interface{}{
+				"secret": "secret reference {secret://vault/secret/data/admin#key} is not allowed",
+			},
+		},
+		{
+			desc:   "allowed provider",
+			policy: &secret.Policy{Providers: []string{"env"}},
+			config: `{"secret":"{secret://env/WEBHOOKX_TEST_SECRET}"}`,
+		},
+	}
+	for _, test := range tests {
+		secret.SetPolicy(test.policy)
+		p := &Plugin{Name: "entities-sensitive", EndpointId: utils.Pointer("ep"), Config: PluginConfiguration(test.config)}
+		err := p.Validate()
+		if test.fields == nil {
+			assert.NoError(t, err, test.desc)
+			continue
+		}
+		e, ok := err.(*errs.ValidateError)
+		assert.True(t, ok, test.desc)
+		assert.Equal(t, map[string]interface{}{"config": test.fields}, e.Fields, test.desc)
+	}
+}
+
+func TestPluginExecutorSecretReferences(t *testing.T) {
+	defer secret.SetPolicy(&secret.DefaultPolicy)
+	t.Setenv("WEBHOOKX_TEST_SECRET", "foo")
+
+	p := &Plugin{Name: "entities-sensitive", Config: PluginConfiguration(`{"secret":"{secret://env/WEBHOOKX_TEST_SECRET}"}`)}
+	_, err := p.Executor(context.TODO())
+	assert.EqualError(t, err, "secret reference {secret://env/WEBHOOKX_TEST_SECRET} is not allowed")
+
+	secret.SetPolicy(&secret.Policy{Providers: []string{"env"}})
+	_, err = p.Executor(context.TODO())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"secret":"{secret://env/WEBHOOKX_TEST_SECRET}"}`, string(p.Config))
+}
+
+func TestRedactHeaders(t *testing.T) {
+	assert.Nil(t, RedactHeaders(nil))
+
+	headers := map[string]string{
+		"Authorization": "Bearer token",
+		"X-Api-Key":     "key",
+		"Content-Type":  "application/json",
+	}
+	redacted := RedactHeaders(headers)
+	assert.Equal(t, map[string]string{
+		"Authorization": RedactedSecret,
+		"X-Api-Key":     RedactedSecret,
+		"Content-Type":  "application/json",
+	}, redacted)
+	assert.Equal(t, "Bearer token", headers["Authorization"])
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "attempt_stats";
+ALTER TABLE IF EXISTS ONLY "events" DROP COLUMN IF EXISTS "source_id";
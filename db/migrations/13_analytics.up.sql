@@ -0,0 +1,20 @@
+ALTER TABLE IF EXISTS ONLY "events" ADD COLUMN IF NOT EXISTS "source_id" CHAR(27);
+
+CREATE TABLE IF NOT EXISTS "attempt_stats" (
+    "bucket"          TIMESTAMPTZ(3) NOT NULL,
+    "endpoint_id"     CHAR(27)       NOT NULL,
+    "source_id"       CHAR(27),
+    "event_type"      TEXT           NOT NULL,
+    "status"          VARCHAR(20)    NOT NULL,
+    "error_code"      VARCHAR(30),
+
+    "count"           BIGINT         NOT NULL DEFAULT 0,
+    "latency_count"   BIGINT         NOT NULL DEFAULT 0,
+    "latency_sum"     BIGINT         NOT NULL DEFAULT 0,
+    "latency_buckets" BIGINT[]       NOT NULL,
+
+    "ws_id"           CHAR(27)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attempt_stats_ws_id_bucket ON attempt_stats (ws_id, bucket);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_attempt_stats_dimensions ON attempt_stats (bucket, endpoint_id, (COALESCE(source_id, '')), event_type, status, (COALESCE(error_code, '')), (COALESCE(ws_id, '')));
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "endpoint_health" (
+    "id"                   CHAR(27) PRIMARY KEY REFERENCES "endpoints" ("id") ON DELETE CASCADE,
+    "status"               VARCHAR(20)      NOT NULL DEFAULT 'HEALTHY',
+    "success_rate"         DOUBLE PRECISION NOT NULL DEFAULT 1,
+    "consecutive_failures" BIGINT           NOT NULL DEFAULT 0,
+    "last_success_at"      TIMESTAMPTZ(3),
+    "last_failure_at"      TIMESTAMPTZ(3),
+
+    "ws_id"                CHAR(27),
+    "created_at"           TIMESTAMPTZ(3)            DEFAULT (CURRENT_TIMESTAMP(3) AT TIME ZONE 'UTC'),
+    "updated_at"           TIMESTAMPTZ(3)            DEFAULT (CURRENT_TIMESTAMP(3) AT TIME ZONE 'UTC')
+);
+
+CREATE INDEX IF NOT EXISTS idx_endpoint_health_ws_id ON endpoint_health (ws_id);
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS ONLY "plugins" DROP COLUMN IF EXISTS "priority";
+ALTER TABLE IF EXISTS ONLY "plugins" DROP COLUMN IF EXISTS "condition";
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS ONLY "plugins" ADD COLUMN IF NOT EXISTS "priority" INTEGER;
+ALTER TABLE IF EXISTS ONLY "plugins" ADD COLUMN IF NOT EXISTS "condition" JSONB;
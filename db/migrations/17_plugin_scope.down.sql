@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uk_plugins_global_name;
+DROP INDEX IF EXISTS uk_plugins_workspace_name;
+ALTER TABLE IF EXISTS ONLY "plugins" DROP COLUMN IF EXISTS "global";
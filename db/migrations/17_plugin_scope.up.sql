@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS ONLY "plugins" ADD COLUMN IF NOT EXISTS "global" BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_plugins_workspace_name ON plugins(ws_id, name) WHERE endpoint_id IS NULL AND source_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_plugins_global_name ON plugins(name) WHERE "global";
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_attempts_attempted_at;
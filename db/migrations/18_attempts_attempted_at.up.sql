@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_attempts_attempted_at ON attempts (attempted_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS uk_plugins_global_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_plugins_global_name ON plugins(name) WHERE "global";
+DROP INDEX IF EXISTS uk_plugins_workspace_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_plugins_workspace_name ON plugins(ws_id, name) WHERE endpoint_id IS NULL AND source_id IS NULL;
+
+ALTER TABLE IF EXISTS ONLY "plugins" DROP COLUMN IF EXISTS "side";
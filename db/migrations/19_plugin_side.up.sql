@@ -0,0 +1,6 @@
+ALTER TABLE IF EXISTS ONLY "plugins" ADD COLUMN IF NOT EXISTS "side" VARCHAR(20);
+
+DROP INDEX IF EXISTS uk_plugins_workspace_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_plugins_workspace_name ON plugins(ws_id, name, COALESCE(side, '')) WHERE endpoint_id IS NULL AND source_id IS NULL;
+DROP INDEX IF EXISTS uk_plugins_global_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uk_plugins_global_name ON plugins(name, COALESCE(side, '')) WHERE "global";
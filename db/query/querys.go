@@ -1,5 +1,7 @@
 package query
 
+import "time"
+
 type EndpointQuery struct {
 	Query
 
@@ -18,6 +20,20 @@ func (q *EndpointQuery) WhereMap() map[string]interface{} {
 	return maps
 }
 
+type EndpointHealthQuery struct {
+	Query
+
+	IDs []string
+}
+
+func (q *EndpointHealthQuery) WhereMap() map[string]interface{} {
+	maps := make(map[string]interface{})
+	if q.IDs != nil {
+		maps["id"] = q.IDs
+	}
+	return maps
+}
+
 type EventQuery struct {
 	Query
 }
@@ -81,6 +97,9 @@ type PluginQuery struct {
 	EndpointId  *string
 	SourceId    *string
 	Enabled     *bool
+	Global      *bool
+	// Unbound selects plugins that are not applied to an endpoint or a source
+	Unbound bool
 }
 
 func (q *PluginQuery) WhereMap() map[string]interface{} {
@@ -97,5 +116,34 @@ func (q *PluginQuery) WhereMap() map[string]interface{} {
 	if q.Enabled != nil {
 		maps["enabled"] = *q.Enabled
 	}
+	if q.Global != nil {
+		maps["global"] = *q.Global
+	}
+	if q.Unbound {
+		maps["endpoint_id"] = nil
+		maps["source_id"] = nil
+	}
+	return maps
+}
+
+type AttemptStatsQuery struct {
+	Start      time.Time
+	End        time.Time
+	EndpointId *string
+	SourceId   *string
+	EventType  *string
+}
+
+func (q *AttemptStatsQuery) WhereMap() map[string]interface{} {
+	maps := make(map[string]interface{})
+	if q.EndpointId != nil {
+		maps["endpoint_id"] = *q.EndpointId
+	}
+	if q.SourceId != nil {
+		maps["source_id"] = *q.SourceId
+	}
+	if q.EventType != nil {
+		maps["event_type"] = *q.EventType
+	}
 	return maps
 }
@@ -4,12 +4,14 @@ import (
 	"context"
 	"github.com/webhookx-io/webhookx/db"
 	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
 	"github.com/webhookx-io/webhookx/pkg/metrics"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/utils"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"slices"
 	"time"
 )
 
@@ -25,6 +27,7 @@ type Options struct {
 	DB       *db.DB
 	Metrics  *metrics.Metrics
 	Registry *Registry
+	Tail     *livetail.Tail
 }
 
 func NewDispatcher(opts Options) *Dispatcher {
@@ -38,6 +41,15 @@ func NewDispatcher(opts Options) *Dispatcher {
 }
 
 func (d *Dispatcher) Dispatch(ctx context.Context, events []*entities.Event) ([]*entities.Attempt, error) {
+	return d.dispatch(ctx, events, nil)
+}
+
+// DispatchExcluding dispatches the events like Dispatch, except to the excluded endpoints
+func (d *Dispatcher) DispatchExcluding(ctx context.Context, events []*entities.Event, excluded ...string) ([]*entities.Attempt, error) {
+	return d.dispatch(ctx, events, excluded)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, events []*entities.Event, excluded []string) ([]*entities.Attempt, error) {
 	ctx, span := tracing.Start(ctx, "dispatcher.dispatch", trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
@@ -51,6 +63,11 @@ func (d *Dispatcher) Dispatch(ctx context.Context, events []*entities.Event) ([]
 		if err != nil {
 			return nil, err
 		}
+		if len(excluded) > 0 {
+			endpoints = slices.DeleteFunc(slices.Clone(endpoints), func(e *entities.Endpoint) bool {
+				return slices.Contains(excluded, e.ID)
+			})
+		}
 		if len(endpoints) != 0 {
 			attempts := fanout(event, endpoints, entities.AttemptTriggerModeInitial)
 			maps[event.ID] = attempts
@@ -62,17 +79,20 @@ func (d *Dispatcher) Dispatch(ctx context.Context, events []*entities.Event) ([]
 		if d.opts.Metrics.Enabled {
 			d.opts.Metrics.EventPersistCounter.Add(float64(len(ids)))
 		}
+		if err == nil {
+			d.publish(events, ids, nil)
+		}
 		return nil, err
 	}
 
 	attempts := make([]*entities.Attempt, 0)
-	n := 0
+	var ids []string
 	err := d.db.TX(ctx, func(ctx context.Context) error {
-		ids, err := d.db.Events.BatchInsertIgnoreConflict(ctx, events)
+		var err error
+		ids, err = d.db.Events.BatchInsertIgnoreConflict(ctx, events)
 		if err != nil {
 			return err
 		}
-		n = len(ids)
 		for _, id := range ids {
 			attempts = append(attempts, maps[id]...)
 		}
@@ -80,12 +100,34 @@ func (d *Dispatcher) Dispatch(ctx context.Context, events []*entities.Event) ([]
 	})
 	if err == nil {
 		if d.opts.Metrics.Enabled {
-			d.opts.Metrics.EventPersistCounter.Add(float64(n))
+			d.opts.Metrics.EventPersistCounter.Add(float64(len(ids)))
 		}
+		d.publish(events, ids, attempts)
 	}
 	return attempts, err
 }
 
+// publish publishes the persisted events and their attempts to the live tail
+func (d *Dispatcher) publish(events []*entities.Event, ids []string, attempts []*entities.Attempt) {
+	if d.opts.Tail == nil {
+		return
+	}
+	activities := make([]*livetail.Activity, 0, len(ids)+len(attempts))
+	persisted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		persisted[id] = true
+	}
+	for _, event := range events {
+		if persisted[event.ID] {
+			activities = append(activities, livetail.NewEventActivity(event))
+		}
+	}
+	for _, attempt := range attempts {
+		activities = append(activities, livetail.NewAttemptActivity(attempt))
+	}
+	d.opts.Tail.Publish(activities...)
+}
+
 func fanout(event *entities.Event, endpoints []*entities.Endpoint, mode entities.AttemptTriggerMode) []*entities.Attempt {
 	attempts := make([]*entities.Attempt, 0, len(endpoints))
 	now := time.Now()
@@ -114,6 +156,7 @@ func (d *Dispatcher) DispatchEndpoint(ctx context.Context, event *entities.Event
 	if err != nil {
 		return nil, err
 	}
+	d.publish(nil, nil, attempts)
 
 	return attempts, nil
 }
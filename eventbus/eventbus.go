@@ -2,31 +2,33 @@ package eventbus
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
-	"fmt"
 	evbus "github.com/asaskevich/EventBus"
-	"github.com/lib/pq"
 	"go.uber.org/zap"
 	"sync"
 	"time"
 )
 
-const channelName = "webhookx"
+// Broker delivers clustering messages between nodes
+type Broker interface {
+	// Start starts receiving messages published by all nodes
+	Start(ctx context.Context, fn func(payload []byte)) error
+	Publish(ctx context.Context, payload []byte) error
+	Stop() error
+}
 
 type EventBus struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
 	nodeID   string
 	log      *zap.SugaredLogger
-	listener *pq.Listener
+	broker   Broker
 	mux      sync.Mutex
 	handlers map[string][]func(data []byte)
 	bus      evbus.Bus
-	db       *sql.DB
 }
 
-func NewEventBus(nodeID string, dsn string, log *zap.SugaredLogger, db *sql.DB) *EventBus {
+func NewEventBus(nodeID string, broker Broker, log *zap.SugaredLogger) *EventBus {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	bus := EventBus{
@@ -34,65 +36,39 @@ func NewEventBus(nodeID string, dsn string, log *zap.SugaredLogger, db *sql.DB)
 		cancel:   cancel,
 		bus:      evbus.New(),
 		nodeID:   nodeID,
-		listener: pq.NewListener(dsn, time.Millisecond*100, time.Second*30, nil),
+		broker:   broker,
 		mux:      sync.Mutex{},
 		handlers: make(map[string][]func(data []byte)),
 		log:      log.Named("eventbus"),
-		db:       db,
 	}
 
 	return &bus
 }
 
 func (bus *EventBus) Start() error {
-	go bus.listenClusterLoop()
-	go bus.startListen()
-	return nil
-}
-
-func (bus *EventBus) startListen() {
-	err := bus.listener.Listen(channelName)
-	if err != nil {
-		bus.log.Errorf("failed to listen on channel %s: %v", channelName, err)
-		return
-	}
-	bus.log.Infof(`listening on channel "%s"`, channelName)
+	return bus.broker.Start(bus.ctx, bus.dispatch)
 }
 
 func (bus *EventBus) Stop() error {
 	bus.cancel()
-	return bus.listener.Close()
+	return bus.broker.Stop()
 }
 
-func (bus *EventBus) listenClusterLoop() {
-	timeoutDuration := 5 * time.Second
-	timeout := time.NewTimer(timeoutDuration)
-	for {
-		timeout.Reset(timeoutDuration)
-		select {
-		case <-bus.ctx.Done():
-			return
-		case n := <-bus.listener.NotificationChannel():
-			var msg Message
-			if err := json.Unmarshal([]byte(n.Extra), &msg); err != nil {
-				bus.log.Errorf("failed to unmarshal message: %s", err)
-				continue
-			}
-			if msg.Node == bus.nodeID {
-				continue
-			}
-			bus.log.Debugf("dispatch cluster message: %s", n.Extra)
-			if handlers, ok := bus.handlers[msg.Event]; ok {
-				for _, handler := range handlers {
-					handler(msg.Data)
-				}
-			}
-		case <-timeout.C:
-			err := bus.listener.Ping()
-			if err != nil {
-				bus.log.Errorf("faield to ping database: %v", err)
-			}
-		}
+func (bus *EventBus) dispatch(payload []byte) {
+	var msg Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		bus.log.Errorf("failed to unmarshal message: %s", err)
+		return
+	}
+	if msg.Node == bus.nodeID {
+		return
+	}
+	bus.log.Debugf("dispatch cluster message: %s", payload)
+	bus.mux.Lock()
+	handlers := bus.handlers[msg.Event]
+	bus.mux.Unlock()
+	for _, handler := range handlers {
+		handler(msg.Data)
 	}
 }
 
@@ -118,8 +94,7 @@ func (bus *EventBus) ClusteringBroadcast(channel string, data interface{}) error
 
 	bus.log.Debugf("broadcasting cluster message: %s", string(bytes))
 
-	statement := fmt.Sprintf("NOTIFY %s, %s", "webhookx", pq.QuoteLiteral(string(bytes)))
-	_, err = bus.db.ExecContext(context.TODO(), statement)
+	err = bus.broker.Publish(context.TODO(), bytes)
 	if err != nil {
 		bus.log.Errorf("failed to broadcast message: %v", err)
 	}
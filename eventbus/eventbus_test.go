@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// memoryBroker delivers messages to all event buses sharing it
+type memoryBroker struct {
+	mux      sync.Mutex
+	handlers []func(payload []byte)
+}
+
+func (b *memoryBroker) Start(ctx context.Context, fn func(payload []byte)) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.handlers = append(b.handlers, fn)
+	return nil
+}
+
+func (b *memoryBroker) Publish(ctx context.Context, payload []byte) error {
+	b.mux.Lock()
+	handlers := b.handlers
+	b.mux.Unlock()
+	for _, fn := range handlers {
+		fn(payload)
+	}
+	return nil
+}
+
+func (b *memoryBroker) Stop() error { return nil }
+
+func TestEventBus(t *testing.T) {
+	broker := &memoryBroker{}
+	node1 := NewEventBus("node1", broker, zap.S())
+	node2 := NewEventBus("node2", broker, zap.S())
+	assert.NoError(t, node1.Start())
+	assert.NoError(t, node2.Start())
+	defer func() {
+		assert.NoError(t, node1.Stop())
+		assert.NoError(t, node2.Stop())
+	}()
+
+	var received1, received2 [][]byte
+	node1.ClusteringSubscribe("foo", func(data []byte) { received1 = append(received1, data) })
+	node2.ClusteringSubscribe("foo", func(data []byte) { received2 = append(received2, data) })
+
+	local := make(chan interface{}, 1)
+	node1.Subscribe("foo", func(data interface{}) { local <- data })
+
+	assert.NoError(t, node1.ClusteringBroadcast("foo", map[string]string{"key": "value"}))
+
+	// messages are not dispatched to the publishing node through the broker
+	assert.Empty(t, received1)
+	assert.Equal(t, [][]byte{[]byte(`{"key":"value"}`)}, received2)
+
+	select {
+	case data := <-local:
+		assert.Equal(t, map[string]string{"key": "value"}, data)
+	case <-time.After(time.Second):
+		t.Fatal("local subscriber did not receive the message")
+	}
+}
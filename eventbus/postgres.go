@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"time"
+)
+
+const channelName = "webhookx"
+
+// maxNotifyPayloadSize is the maximum payload size of PostgreSQL NOTIFY
+const maxNotifyPayloadSize = 8000
+
+// PostgresBroker is a Broker based on PostgreSQL LISTEN/NOTIFY
+type PostgresBroker struct {
+	log      *zap.SugaredLogger
+	listener *pq.Listener
+	db       *sql.DB
+}
+
+func NewPostgresBroker(dsn string, db *sql.DB, log *zap.SugaredLogger) *PostgresBroker {
+	return &PostgresBroker{
+		log:      log.Named("eventbus"),
+		listener: pq.NewListener(dsn, time.Millisecond*100, time.Second*30, nil),
+		db:       db,
+	}
+}
+
+func (b *PostgresBroker) Start(ctx context.Context, fn func(payload []byte)) error {
+	go b.listenLoop(ctx, fn)
+	go b.startListen()
+	return nil
+}
+
+func (b *PostgresBroker) startListen() {
+	err := b.listener.Listen(channelName)
+	if err != nil {
+		b.log.Errorf("failed to listen on channel %s: %v", channelName, err)
+		return
+	}
+	b.log.Infof(`listening on channel "%s"`, channelName)
+}
+
+func (b *PostgresBroker) Stop() error {
+	return b.listener.Close()
+}
+
+func (b *PostgresBroker) listenLoop(ctx context.Context, fn func(payload []byte)) {
+	timeoutDuration := 5 * time.Second
+	timeout := time.NewTimer(timeoutDuration)
+	for {
+		timeout.Reset(timeoutDuration)
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-b.listener.NotificationChannel():
+			if n == nil { // reconnected
+				continue
+			}
+			fn([]byte(n.Extra))
+		case <-timeout.C:
+			err := b.listener.Ping()
+			if err != nil {
+				b.log.Errorf("faield to ping database: %v", err)
+			}
+		}
+	}
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, payload []byte) error {
+	if len(payload) >= maxNotifyPayloadSize {
+		return fmt.Errorf("message size %d exceeds the limit of %d bytes", len(payload), maxNotifyPayloadSize)
+	}
+	statement := fmt.Sprintf("NOTIFY %s, %s", channelName, pq.QuoteLiteral(string(payload)))
+	_, err := b.db.ExecContext(ctx, statement)
+	return err
+}
@@ -0,0 +1,60 @@
+package eventbus
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const redisChannelName = "webhookx:eventbus"
+
+// RedisBroker is a Broker based on Redis Pub/Sub
+type RedisBroker struct {
+	log    *zap.SugaredLogger
+	client *redis.Client
+	pubsub *redis.PubSub
+}
+
+func NewRedisBroker(client *redis.Client, log *zap.SugaredLogger) *RedisBroker {
+	return &RedisBroker{
+		log:    log.Named("eventbus"),
+		client: client,
+	}
+}
+
+func (b *RedisBroker) Start(ctx context.Context, fn func(payload []byte)) error {
+	b.pubsub = b.client.Subscribe(ctx, redisChannelName)
+	// waits for the confirmation so that messages published after starting are received
+	if _, err := b.pubsub.Receive(ctx); err != nil {
+		_ = b.pubsub.Close()
+		return err
+	}
+	b.log.Infof(`listening on redis channel "%s"`, redisChannelName)
+
+	ch := b.pubsub.Channel()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				fn([]byte(msg.Payload))
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *RedisBroker) Stop() error {
+	if b.pubsub == nil {
+		return nil
+	}
+	return b.pubsub.Close()
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, payload []byte) error {
+	return b.client.Publish(ctx, redisChannelName, payload).Err()
+}
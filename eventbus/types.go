@@ -5,6 +5,9 @@ import "encoding/json"
 const (
 	EventCRUD        = "crud"
 	EventEventFanout = "event.fanout"
+
+	EventLiveTail          = "livetail"
+	EventLiveTailHeartbeat = "livetail.heartbeat"
 )
 
 type Bus interface {
@@ -30,6 +33,8 @@ type CrudData struct {
 	WID      string          `json:"wid"`
 	CacheKey string          `json:"cache_key"`
 	Data     json.RawMessage `json:"data"`
+	// Previous is the entity before the update, it is empty for inserts and deletes
+	Previous json.RawMessage `json:"previous,omitempty"`
 }
 
 type EventFanoutData struct {
@@ -0,0 +1,57 @@
+package headers
+
+import (
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/utils"
+	"go.uber.org/zap"
+)
+
+func init() {
+	plugin.RegisterPlugin(plugin.TypeOutbound, "static-headers", New,
+		plugin.WithConfig(Config{}),
+		plugin.WithDescription("Adds static headers to outbound requests."),
+		plugin.WithInit(func() error {
+			zap.S().Info("static-headers plugin initialized")
+			return nil
+		}),
+		plugin.WithConfigChange(func(change *plugin.ConfigChange) {
+			zap.S().Infof("static-headers plugin %s changed", change.ID)
+		}),
+		plugin.WithShutdown(func() error {
+			zap.S().Info("static-headers plugin shutdown")
+			return nil
+		}),
+	)
+}
+
+type Config struct {
+	Headers map[string]string `json:"headers" validate:"required" description:"The headers to add to requests."`
+}
+
+type HeadersPlugin struct {
+	plugin.BasePlugin[Config]
+}
+
+func New(config []byte) (plugin.Plugin, error) {
+	p := &HeadersPlugin{}
+	p.Name = "static-headers"
+
+	if config != nil {
+		if err := p.UnmarshalConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *HeadersPlugin) ValidateConfig() error {
+	return utils.Validate(p.Config)
+}
+
+func (p *HeadersPlugin) ExecuteOutbound(outbound *plugin.Outbound, _ *plugin.Context) error {
+	for k, v := range p.Config.Headers {
+		outbound.Headers[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/webhookx-io/webhookx/cmd"
+
+	// plugins are registered by importing their packages
+	_ "github.com/webhookx-io/webhookx/examples/custom-plugin/headers"
+)
+
+func main() {
+	cmd.Execute()
+}
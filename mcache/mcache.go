@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/webhookx-io/webhookx/pkg/cache"
+	"github.com/webhookx-io/webhookx/pkg/serializer"
 	"go.uber.org/zap"
 	"sync"
 	"sync/atomic"
@@ -24,21 +25,32 @@ func Set(mcache *MCache) {
 
 // MCache is multiple levels cache
 type MCache struct {
-	mux sync.Mutex
-	l1  *expirable.LRU[string, any]
-	l2  cache.Cache
+	mux    sync.Mutex
+	l1     *expirable.LRU[string, any]
+	l2     cache.Cache
+	seal   TransformFunc
+	unseal TransformFunc
 }
 
+// TransformFunc transforms the value in place
+type TransformFunc func(value any) error
+
 type Options struct {
 	L1Size int
 	L1TTL  time.Duration
 	L2     cache.Cache
+	// Seal transforms a copy of values before they are stored in L2, e.g. encrypts secrets
+	Seal TransformFunc
+	// Unseal reverses Seal for values loaded from L2
+	Unseal TransformFunc
 }
 
 func NewMCache(opts *Options) *MCache {
 	return &MCache{
-		l1: expirable.NewLRU[string, any](opts.L1Size, nil, opts.L1TTL),
-		l2: opts.L2,
+		l1:     expirable.NewLRU[string, any](opts.L1Size, nil, opts.L1TTL),
+		l2:     opts.L2,
+		seal:   opts.Seal,
+		unseal: opts.Unseal,
 	}
 }
 
@@ -99,6 +111,11 @@ func Load[T any](ctx context.Context, key string, opts *LoadOptions, cb Callback
 		return nil, err
 	}
 	if exist {
+		if mcache.unseal != nil {
+			if err := mcache.unseal(value); err != nil {
+				return nil, err
+			}
+		}
 		mcache.l1.Add(key, value)
 		return value, nil
 	}
@@ -115,7 +132,11 @@ func Load[T any](ctx context.Context, key string, opts *LoadOptions, cb Callback
 		return value, err
 	}
 
-	err = mcache.l2.Put(ctx, key, value, DefaultL2TTL)
+	sealed, err := sealValue(mcache.seal, value)
+	if err != nil {
+		return nil, err
+	}
+	err = mcache.l2.Put(ctx, key, sealed, DefaultL2TTL)
 	if err != nil {
 		return nil, err
 	}
@@ -126,3 +147,23 @@ func Load[T any](ctx context.Context, key string, opts *LoadOptions, cb Callback
 
 	return value, nil
 }
+
+// sealValue returns a sealed deep copy of the value, the value itself is left untouched
+// as it is also cached in L1.
+func sealValue[T any](seal TransformFunc, value *T) (*T, error) {
+	if seal == nil {
+		return value, nil
+	}
+	b, err := serializer.Gob.Serialize(value)
+	if err != nil {
+		return nil, err
+	}
+	copied := new(T)
+	if err := serializer.Gob.Deserialize(b, copied); err != nil {
+		return nil, err
+	}
+	if err := seal(copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
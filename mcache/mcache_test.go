@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -131,6 +132,58 @@ var _ = Describe("mcache", Ordered, func() {
 
 })
 
+var _ = Describe("seal", Ordered, func() {
+
+	var mcache *MCache
+	var testDao *TestDao[string]
+	var mockCache *MockCache
+
+	BeforeAll(func() {
+		testDao = &TestDao[string]{
+			data: map[string]interface{}{
+				"foo": "bar",
+			},
+		}
+		mockCache = &MockCache{
+			data: make(map[string]interface{}),
+		}
+		mcache = NewMCache(&Options{
+			L1Size: 100,
+			L1TTL:  time.Second,
+			L2:     mockCache,
+			Seal: func(value any) error {
+				*value.(*string) = "sealed:" + *value.(*string)
+				return nil
+			},
+			Unseal: func(value any) error {
+				*value.(*string) = strings.TrimPrefix(*value.(*string), "sealed:")
+				return nil
+			},
+		})
+		Set(mcache)
+	})
+
+	It("seals values in L2 only", func() {
+		value, err := Load(context.TODO(), "foo", nil, testDao.Get, "foo")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), "bar", *value)
+
+		layer2value, ok := mockCache.data["foo"]
+		assert.True(GinkgoT(), ok)
+		assert.Equal(GinkgoT(), "sealed:bar", *layer2value.(*string))
+
+		layer1value, ok := mcache.l1.Get("foo")
+		assert.True(GinkgoT(), ok)
+		assert.Equal(GinkgoT(), "bar", *layer1value.(*string))
+
+		// unsealed when loaded from L2
+		mcache.InvalidateL1(context.TODO(), "foo")
+		value, err = Load(context.TODO(), "foo", nil, testDao.Get, "foo")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), "bar", *value)
+	})
+})
+
 func Test(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "MCache Suite")
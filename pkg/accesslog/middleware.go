@@ -51,3 +51,7 @@ func (w *responseWriter) Write(b []byte) (int, error) {
 	w.bytesWritten += n
 	return n, err
 }
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
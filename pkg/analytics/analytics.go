@@ -0,0 +1,190 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/types"
+)
+
+// LatencyBounds are the upper bounds (in milliseconds) of the latency histogram of attempt stats,
+// an extra bucket counts latencies above the last bound.
+// Changing the bounds invalidates the histograms of existing rollups.
+var LatencyBounds = []int64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// RollupPeriod is the time period covered by a single rollup row
+const RollupPeriod = time.Hour
+
+type Interval string
+
+const (
+	IntervalHour Interval = "hour"
+	IntervalDay  Interval = "day"
+)
+
+func (i Interval) IsValid() bool {
+	return i == IntervalHour || i == IntervalDay
+}
+
+// Truncate returns t rounded down to the start of its interval in UTC
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	if i == IntervalDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+type GroupBy string
+
+const (
+	GroupByNone      GroupBy = ""
+	GroupByEndpoint  GroupBy = "endpoint"
+	GroupBySource    GroupBy = "source"
+	GroupByEventType GroupBy = "event_type"
+)
+
+func (g GroupBy) IsValid() bool {
+	switch g {
+	case GroupByNone, GroupByEndpoint, GroupBySource, GroupByEventType:
+		return true
+	}
+	return false
+}
+
+func (g GroupBy) key(stats *entities.AttemptStats) string {
+	switch g {
+	case GroupByEndpoint:
+		return stats.EndpointId
+	case GroupBySource:
+		if stats.SourceId != nil {
+			return *stats.SourceId
+		}
+	case GroupByEventType:
+		return stats.EventType
+	}
+	return ""
+}
+
+type Latency struct {
+	Avg float64 `json:"avg"`
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// Bucket is the aggregate of attempts in a time bucket
+type Bucket struct {
+	Time        types.Time       `json:"time"`
+	Key         string           `json:"key,omitempty"`
+	Total       int64            `json:"total"`
+	Statuses    map[string]int64 `json:"statuses"`
+	ErrorCodes  map[string]int64 `json:"error_codes"`
+	SuccessRate float64          `json:"success_rate"`
+	Latency     Latency          `json:"latency"`
+
+	latencyCount int64
+	latencySum   int64
+	histogram    []int64
+}
+
+func (b *Bucket) add(stats *entities.AttemptStats) {
+	b.Total += stats.Count
+	b.Statuses[stats.Status] += stats.Count
+	if stats.ErrorCode != nil {
+		b.ErrorCodes[*stats.ErrorCode] += stats.Count
+	}
+	b.latencyCount += stats.LatencyCount
+	b.latencySum += stats.LatencySum
+	for i, n := range stats.LatencyBuckets {
+		if i < len(b.histogram) {
+			b.histogram[i] += n
+		}
+	}
+}
+
+func (b *Bucket) finalize() {
+	if b.Total > 0 {
+		b.SuccessRate = float64(b.Statuses[entities.AttemptStatusSuccess]) / float64(b.Total)
+	}
+	if b.latencyCount > 0 {
+		b.Latency.Avg = float64(b.latencySum) / float64(b.latencyCount)
+	}
+	b.Latency.P50 = Percentile(b.histogram, 0.50)
+	b.Latency.P95 = Percentile(b.histogram, 0.95)
+	b.Latency.P99 = Percentile(b.histogram, 0.99)
+}
+
+// Aggregate merges rollups into buckets of the interval, grouped by the dimension.
+// Buckets are sorted by time and key.
+func Aggregate(list []*entities.AttemptStats, interval Interval, groupBy GroupBy) []*Bucket {
+	type bucketKey struct {
+		time int64
+		key  string
+	}
+
+	buckets := make(map[bucketKey]*Bucket)
+	for _, stats := range list {
+		t := interval.Truncate(stats.Bucket)
+		k := bucketKey{time: t.UnixMilli(), key: groupBy.key(stats)}
+		bucket, ok := buckets[k]
+		if !ok {
+			bucket = &Bucket{
+				Time:       types.NewTime(t),
+				Key:        k.key,
+				Statuses:   make(map[string]int64),
+				ErrorCodes: make(map[string]int64),
+				histogram:  make([]int64, len(LatencyBounds)+1),
+			}
+			buckets[k] = bucket
+		}
+		bucket.add(stats)
+	}
+
+	result := make([]*Bucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucket.finalize()
+		result = append(result, bucket)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Time.Equal(result[j].Time) {
+			return result[i].Time.Before(result[j].Time.Time)
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Percentile estimates the p-th (0 < p <= 1) percentile of a latency histogram by linear interpolation
+// within the bucket that contains it. Percentiles in the overflow bucket are reported as the last bound.
+func Percentile(histogram []int64, p float64) float64 {
+	var total int64
+	for _, n := range histogram {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := p * float64(total)
+	var cumulative int64
+	for i, n := range histogram {
+		if n == 0 {
+			continue
+		}
+		if float64(cumulative+n) >= rank {
+			if i >= len(LatencyBounds) {
+				return float64(LatencyBounds[len(LatencyBounds)-1])
+			}
+			var lower float64
+			if i > 0 {
+				lower = float64(LatencyBounds[i-1])
+			}
+			upper := float64(LatencyBounds[i])
+			return lower + (upper-lower)*(rank-float64(cumulative))/float64(n)
+		}
+		cumulative += n
+	}
+	return float64(LatencyBounds[len(LatencyBounds)-1])
+}
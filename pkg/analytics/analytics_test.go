@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+func histogram(counts map[int]int64) []int64 {
+	h := make([]int64, len(LatencyBounds)+1)
+	for i, n := range counts {
+		h[i] = n
+	}
+	return h
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		desc      string
+		histogram []int64
+		p         float64
+		expected  float64
+	}{
+		{desc: "empty", histogram: histogram(nil), p: 0.5, expected: 0},
+		{desc: "first bucket", histogram: histogram(map[int]int64{0: 10}), p: 0.5, expected: 2.5},
+		{desc: "interpolated", histogram: histogram(map[int]int64{4: 100}), p: 0.5, expected: 75},
+		{desc: "across buckets", histogram: histogram(map[int]int64{0: 50, 4: 50}), p: 0.99, expected: 99},
+		{desc: "overflow bucket", histogram: histogram(map[int]int64{len(LatencyBounds): 1}), p: 0.5, expected: 60000},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.InDelta(t, test.expected, Percentile(test.histogram, test.p), 0.0001)
+		})
+	}
+}
+
+func TestInterval(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC), IntervalHour.Truncate(ts))
+	assert.Equal(t, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), IntervalDay.Truncate(ts))
+	assert.True(t, IntervalDay.IsValid())
+	assert.False(t, Interval("week").IsValid())
+}
+
+func TestAggregate(t *testing.T) {
+	hour1 := time.Date(2024, 5, 6, 7, 0, 0, 0, time.UTC)
+	hour2 := hour1.Add(time.Hour)
+	list := []*entities.AttemptStats{
+		{Bucket: hour1, EndpointId: "e1", SourceId: utils.Pointer("s1"), EventType: "foo", Status: entities.AttemptStatusSuccess,
+			Count: 8, LatencyCount: 8, LatencySum: 400, LatencyBuckets: histogram(map[int]int64{3: 8})},
+		{Bucket: hour1, EndpointId: "e1", SourceId: utils.Pointer("s1"), EventType: "foo", Status: entities.AttemptStatusFailure,
+			ErrorCode: utils.Pointer(entities.AttemptErrorCodeTimeout), Count: 2},
+		{Bucket: hour2, EndpointId: "e2", EventType: "bar", Status: entities.AttemptStatusSuccess,
+			Count: 5, LatencyCount: 5, LatencySum: 50, LatencyBuckets: histogram(map[int]int64{1: 5})},
+	}
+
+	t.Run("hourly", func(t *testing.T) {
+		buckets := Aggregate(list, IntervalHour, GroupByNone)
+		assert.Len(t, buckets, 2)
+		assert.Equal(t, hour1, buckets[0].Time.UTC())
+		assert.EqualValues(t, 10, buckets[0].Total)
+		assert.Equal(t, map[string]int64{"SUCCESSFUL": 8, "FAILED": 2}, buckets[0].Statuses)
+		assert.Equal(t, map[string]int64{"TIMEOUT": 2}, buckets[0].ErrorCodes)
+		assert.InDelta(t, 0.8, buckets[0].SuccessRate, 0.0001)
+		assert.InDelta(t, 50, buckets[0].Latency.Avg, 0.0001)
+		assert.InDelta(t, 37.5, buckets[0].Latency.P50, 0.0001)
+		assert.Equal(t, hour2, buckets[1].Time.UTC())
+		assert.EqualValues(t, 5, buckets[1].Total)
+		assert.InDelta(t, 1, buckets[1].SuccessRate, 0.0001)
+	})
+
+	t.Run("daily grouped by endpoint", func(t *testing.T) {
+		buckets := Aggregate(list, IntervalDay, GroupByEndpoint)
+		assert.Len(t, buckets, 2)
+		assert.Equal(t, "e1", buckets[0].Key)
+		assert.Equal(t, "e2", buckets[1].Key)
+		assert.Equal(t, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), buckets[0].Time.UTC())
+	})
+
+	t.Run("daily", func(t *testing.T) {
+		buckets := Aggregate(list, IntervalDay, GroupByNone)
+		assert.Len(t, buckets, 1)
+		assert.EqualValues(t, 15, buckets[0].Total)
+		assert.Equal(t, map[string]int64{"SUCCESSFUL": 13, "FAILED": 2}, buckets[0].Statuses)
+	})
+
+	t.Run("grouped by source", func(t *testing.T) {
+		buckets := Aggregate(list, IntervalDay, GroupBySource)
+		assert.Len(t, buckets, 2)
+		assert.Equal(t, "", buckets[0].Key)
+		assert.Equal(t, "s1", buckets[1].Key)
+	})
+}
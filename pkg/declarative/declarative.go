@@ -22,6 +22,7 @@ func (m *Declarative) Sync(wid string, cfg *Configuration) error {
 
 	upsertedEndpoints := make(map[string]bool)
 	upsertedSources := make(map[string]bool)
+	upsertedPlugins := make(map[string]bool)
 
 	err := m.db.TX(ctx, func(ctx context.Context) error {
 		// Endpoints
@@ -44,6 +45,35 @@ func (m *Declarative) Sync(wid string, cfg *Configuration) error {
 			}
 		}
 
+		// Workspace plugins
+		var q query.PluginQuery
+		q.WorkspaceId = &wid
+		q.Unbound = true
+		existing, err := m.db.Plugins.List(ctx, &q)
+		if err != nil {
+			return err
+		}
+		for _, model := range cfg.Plugins {
+			model.WorkspaceId = wid
+			for _, e := range existing {
+				if e.Name == model.Name && e.ExecutionSide() == model.ExecutionSide() {
+					model.ID = e.ID
+				}
+			}
+			err = m.db.Plugins.Upsert(ctx, []string{"id"}, model)
+			if err != nil {
+				return err
+			}
+			upsertedPlugins[model.ID] = true
+		}
+		for _, e := range existing {
+			if !upsertedPlugins[e.ID] {
+				if _, err := m.db.Plugins.Delete(ctx, e.ID); err != nil {
+					return err
+				}
+			}
+		}
+
 		// Sources
 		for _, source := range cfg.Sources {
 			source.WorkspaceId = wid
@@ -135,6 +165,15 @@ func (m *Declarative) Dump(ctx context.Context, wid string) (*Configuration, err
 		if err != nil {
 			return err
 		}
+
+		var pluginQ query.PluginQuery
+		pluginQ.WorkspaceId = &wid
+		pluginQ.Unbound = true
+		cfg.Plugins, err = m.db.Plugins.List(ctx, &pluginQ)
+		if err != nil {
+			return err
+		}
+
 		for _, source := range sources {
 			var e Source
 			e.Source = *source
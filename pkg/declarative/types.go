@@ -11,6 +11,8 @@ import (
 type Configuration struct {
 	Endpoints []*Endpoint `json:"endpoints"`
 	Sources   []*Source   `json:"sources"`
+	// Plugins is the plugins applied to the whole workspace, or to all workspaces if they are global
+	Plugins []*entities.Plugin `json:"plugins"`
 }
 
 func (cfg *Configuration) SchemaName() string {
@@ -41,6 +43,13 @@ func (cfg *Configuration) Init() {
 			p.EndpointId = utils.Pointer(m.ID)
 		}
 	}
+	for _, p := range cfg.Plugins {
+		if p.ID == "" {
+			p.ID = utils.KSUID()
+		}
+		p.EndpointId = nil
+		p.SourceId = nil
+	}
 }
 
 func (cfg *Configuration) Validate() error {
@@ -50,6 +59,9 @@ func (cfg *Configuration) Validate() error {
 	}
 
 	for _, end := range cfg.Endpoints {
+		if err := end.Endpoint.Validate(); err != nil {
+			return err
+		}
 		for _, model := range end.Plugins {
 			if err := model.Validate(); err != nil {
 				return err
@@ -60,6 +72,9 @@ func (cfg *Configuration) Validate() error {
 			}
 			model.Config = utils.Must(p.MarshalConfig())
 		}
+		if err := entities.CheckPluginConflicts(end.Plugins); err != nil {
+			return err
+		}
 	}
 
 	for _, src := range cfg.Sources {
@@ -73,6 +88,32 @@ func (cfg *Configuration) Validate() error {
 			}
 			model.Config = utils.Must(p.MarshalConfig())
 		}
+		if err := entities.CheckPluginConflicts(src.Plugins); err != nil {
+			return err
+		}
+	}
+
+	var workspacePlugins, globalPlugins []*entities.Plugin
+	for _, model := range cfg.Plugins {
+		if err := model.Validate(); err != nil {
+			return err
+		}
+		p, err := model.Plugin()
+		if err != nil {
+			return err
+		}
+		model.Config = utils.Must(p.MarshalConfig())
+		if model.Global {
+			globalPlugins = append(globalPlugins, model)
+		} else {
+			workspacePlugins = append(workspacePlugins, model)
+		}
+	}
+	if err := entities.CheckPluginConflicts(workspacePlugins); err != nil {
+		return err
+	}
+	if err := entities.CheckPluginConflicts(globalPlugins); err != nil {
+		return err
 	}
 
 	return nil
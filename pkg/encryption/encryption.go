@@ -0,0 +1,241 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Prefix is the prefix of encrypted values.
+//
+// An encrypted value has the form "$enc$v1$<key id>$<encrypted data key>$<ciphertext>".
+// Each value is encrypted with a random data key, which is encrypted with the key
+// encryption key identified by key id (envelope encryption).
+const Prefix = "$enc$v1$"
+
+const keySize = 32
+
+var (
+	ErrNoKeyring  = errors.New("encryption keyring is not configured")
+	ErrUnknownKey = errors.New("unknown encryption key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// Keyring holds key encryption keys.
+// The first key is the primary key used for encryption, the others are only used
+// for decryption, which allows keys to be rotated.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring returns a keyring from keys in the form of "<id>:<base64 encoded 32 bytes key>"
+func NewKeyring(keys []string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+	k := &Keyring{
+		keys: make(map[string]cipher.AEAD),
+	}
+	for i, key := range keys {
+		id, encoded, ok := strings.Cut(key, ":")
+		if !ok || id == "" || strings.Contains(id, "$") {
+			return nil, fmt.Errorf("invalid key #%d: must be in the form of '<id>:<base64 key>'", i+1)
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate key id '%s'", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key '%s': %s", id, err)
+		}
+		if len(secret) != keySize {
+			return nil, fmt.Errorf("invalid key '%s': must be %d bytes", id, keySize)
+		}
+		aead, err := newAEAD(secret)
+		if err != nil {
+			return nil, err
+		}
+		if k.primary == "" {
+			k.primary = id
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// LoadKeyringFile reads keys from a file, one key per line.
+// Empty lines and lines starting with '#' are ignored.
+func LoadKeyringFile(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys, scanner.Err()
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// Primary returns the id of the primary key
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Encrypt encrypts the value with the primary key.
+// Values that are already encrypted with the primary key are returned as is.
+func (k *Keyring) Encrypt(value string) (string, error) {
+	if IsEncrypted(value) {
+		id, _, _, err := parse(value)
+		if err != nil {
+			return "", err
+		}
+		if id == k.primary {
+			return value, nil
+		}
+		// re-encrypt with the primary key
+		if value, err = k.Decrypt(value); err != nil {
+			return "", err
+		}
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	encryptedKey, err := seal(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(Prefix)
+	sb.WriteString(k.primary)
+	sb.WriteString("$")
+	sb.WriteString(base64.RawStdEncoding.EncodeToString(encryptedKey))
+	sb.WriteString("$")
+	sb.WriteString(base64.RawStdEncoding.EncodeToString(ciphertext))
+	return sb.String(), nil
+}
+
+// Decrypt decrypts the value. Values that are not encrypted are returned as is.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	id, encryptedKey, ciphertext, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	kek, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w '%s'", ErrUnknownKey, id)
+	}
+	dataKey, err := open(kek, encryptedKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func parse(value string) (id string, encryptedKey []byte, ciphertext []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), "$")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrMalformed
+	}
+	if encryptedKey, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	if ciphertext, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[0], encryptedKey, ciphertext, nil
+}
+
+// IsEncrypted reports whether the value is encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+var keyring atomic.Pointer[Keyring]
+
+// Set sets the global keyring, nil disables encryption
+func Set(k *Keyring) {
+	keyring.Store(k)
+}
+
+// Encrypt encrypts the value with the global keyring.
+// The value is returned as is when encryption is disabled.
+func Encrypt(value string) (string, error) {
+	k := keyring.Load()
+	if k == nil {
+		return value, nil
+	}
+	return k.Encrypt(value)
+}
+
+// Decrypt decrypts the value with the global keyring
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	k := keyring.Load()
+	if k == nil {
+		return "", ErrNoKeyring
+	}
+	return k.Decrypt(value)
+}
@@ -0,0 +1,123 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newKey(id string) string {
+	key := make([]byte, keySize)
+	_, _ = rand.Read(key)
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+func TestKeyring(t *testing.T) {
+	k1 := newKey("k1")
+	k2 := newKey("k2")
+
+	t.Run("encrypt and decrypt", func(t *testing.T) {
+		keyring, err := NewKeyring([]string{k1})
+		assert.NoError(t, err)
+
+		encrypted, err := keyring.Encrypt("secret")
+		assert.NoError(t, err)
+		assert.True(t, IsEncrypted(encrypted))
+		assert.NotContains(t, encrypted, "secret")
+
+		again, err := keyring.Encrypt("secret")
+		assert.NoError(t, err)
+		assert.NotEqual(t, encrypted, again)
+
+		decrypted, err := keyring.Decrypt(encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", decrypted)
+
+		// idempotent
+		same, err := keyring.Encrypt(encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, encrypted, same)
+
+		// plaintext
+		decrypted, err = keyring.Decrypt("plaintext")
+		assert.NoError(t, err)
+		assert.Equal(t, "plaintext", decrypted)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		old, err := NewKeyring([]string{k1})
+		assert.NoError(t, err)
+		encrypted, err := old.Encrypt("secret")
+		assert.NoError(t, err)
+
+		rotated, err := NewKeyring([]string{k2, k1})
+		assert.NoError(t, err)
+		assert.Equal(t, "k2", rotated.Primary())
+		decrypted, err := rotated.Decrypt(encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", decrypted)
+
+		reencrypted, err := rotated.Encrypt(encrypted)
+		assert.NoError(t, err)
+		assert.Contains(t, reencrypted, Prefix+"k2$")
+
+		_, err = old.Decrypt(reencrypted)
+		assert.True(t, errors.Is(err, ErrUnknownKey))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		keyring, err := NewKeyring([]string{k1})
+		assert.NoError(t, err)
+		_, err = keyring.Decrypt(Prefix + "k1$foo")
+		assert.Equal(t, ErrMalformed, err)
+
+		encrypted, err := keyring.Encrypt("secret")
+		assert.NoError(t, err)
+		_, err = keyring.Decrypt(encrypted[:len(encrypted)-2])
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := NewKeyring(nil)
+		assert.EqualError(t, err, "at least one key is required")
+		_, err = NewKeyring([]string{"invalid"})
+		assert.EqualError(t, err, "invalid key #1: must be in the form of '<id>:<base64 key>'")
+		_, err = NewKeyring([]string{"k1:" + base64.StdEncoding.EncodeToString([]byte("short"))})
+		assert.EqualError(t, err, "invalid key 'k1': must be 32 bytes")
+		_, err = NewKeyring([]string{k1, k1})
+		assert.EqualError(t, err, "duplicate key id 'k1'")
+	})
+}
+
+func TestGlobal(t *testing.T) {
+	defer Set(nil)
+
+	value, err := Encrypt("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", value)
+
+	keyring, err := NewKeyring([]string{newKey("k1")})
+	assert.NoError(t, err)
+	Set(keyring)
+	encrypted, err := Encrypt("secret")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+
+	Set(nil)
+	_, err = Decrypt(encrypted)
+	assert.Equal(t, ErrNoKeyring, err)
+}
+
+func TestLoadKeyringFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "keyring")
+	k1 := newKey("k1")
+	assert.NoError(t, os.WriteFile(filename, []byte("# primary\n"+k1+"\n\n"), 0600))
+	keys, err := LoadKeyringFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{k1}, keys)
+}
@@ -0,0 +1,323 @@
+package livetail
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/eventbus"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	ActivityTypeEvent   = "event"
+	ActivityTypeAttempt = "attempt"
+)
+
+const (
+	flushInterval     = time.Millisecond * 100
+	heartbeatInterval = time.Second * 5
+	// heartbeatTimeout is how long activities are still published after the last subscriber heartbeat
+	heartbeatTimeout = heartbeatInterval * 3
+	maxBufferSize    = 1000
+	// maxMessageSize keeps clustering messages under the 8000 bytes limit of PostgreSQL NOTIFY payload
+	maxMessageSize     = 7000
+	subscriptionBuffer = 256
+)
+
+// Activity is an event ingested or an attempt status changed
+type Activity struct {
+	Type        string           `json:"type"`
+	WorkspaceId string           `json:"ws_id"`
+	Event       *EventActivity   `json:"event,omitempty"`
+	Attempt     *AttemptActivity `json:"attempt,omitempty"`
+}
+
+// Payload returns the payload that is sent to subscribers
+func (a *Activity) Payload() interface{} {
+	if a.Type == ActivityTypeEvent {
+		return a.Event
+	}
+	return a.Attempt
+}
+
+type EventActivity struct {
+	ID         string     `json:"id"`
+	EventType  string     `json:"event_type"`
+	SourceId   *string    `json:"source_id"`
+	IngestedAt types.Time `json:"ingested_at"`
+}
+
+type AttemptActivity struct {
+	ID            string                     `json:"id"`
+	EventId       string                     `json:"event_id"`
+	EventType     string                     `json:"event_type"`
+	EndpointId    string                     `json:"endpoint_id"`
+	Status        entities.AttemptStatus     `json:"status"`
+	AttemptNumber int                        `json:"attempt_number"`
+	ErrorCode     *entities.AttemptErrorCode `json:"error_code"`
+	AttemptedAt   *types.Time                `json:"attempted_at"`
+}
+
+func NewEventActivity(event *entities.Event) *Activity {
+	return &Activity{
+		Type:        ActivityTypeEvent,
+		WorkspaceId: event.WorkspaceId,
+		Event: &EventActivity{
+			ID:         event.ID,
+			EventType:  event.EventType,
+			SourceId:   event.SourceId,
+			IngestedAt: event.IngestedAt,
+		},
+	}
+}
+
+func NewAttemptActivity(attempt *entities.Attempt) *Activity {
+	activity := &Activity{
+		Type:        ActivityTypeAttempt,
+		WorkspaceId: attempt.WorkspaceId,
+		Attempt: &AttemptActivity{
+			ID:            attempt.ID,
+			EventId:       attempt.EventId,
+			EndpointId:    attempt.EndpointId,
+			Status:        attempt.Status,
+			AttemptNumber: attempt.AttemptNumber,
+			ErrorCode:     attempt.ErrorCode,
+			AttemptedAt:   attempt.AttemptedAt,
+		},
+	}
+	if attempt.Event != nil {
+		activity.Attempt.EventType = attempt.Event.EventType
+	}
+	return activity
+}
+
+// Filter filters activities of a subscription, an empty list matches any value.
+// EndpointIds and Statuses only match attempts.
+type Filter struct {
+	WorkspaceId string
+	EventTypes  []string
+	EndpointIds []string
+	Statuses    []string
+}
+
+func (f *Filter) Match(a *Activity) bool {
+	if a.WorkspaceId != f.WorkspaceId {
+		return false
+	}
+	switch a.Type {
+	case ActivityTypeEvent:
+		if len(f.EndpointIds) > 0 || len(f.Statuses) > 0 {
+			return false
+		}
+		return match(f.EventTypes, a.Event.EventType)
+	case ActivityTypeAttempt:
+		return match(f.EventTypes, a.Attempt.EventType) &&
+			match(f.EndpointIds, a.Attempt.EndpointId) &&
+			match(f.Statuses, string(a.Attempt.Status))
+	}
+	return false
+}
+
+func match(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+type Subscription struct {
+	C      <-chan *Activity
+	c      chan *Activity
+	filter Filter
+}
+
+// Tail streams activities of all nodes to subscribers through clustering broadcasts.
+//
+// Activities are buffered and broadcast in batches, and they are only published while a
+// subscriber exists in the cluster, which is announced by heartbeats of subscribed nodes.
+// A nil Tail publishes nothing.
+type Tail struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	log    *zap.SugaredLogger
+	bus    eventbus.Bus
+
+	mux    sync.Mutex
+	buffer []*Activity
+
+	subMux        sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+
+	lastHeartbeat atomic.Int64
+}
+
+func New(bus eventbus.Bus) *Tail {
+	ctx, cancel := context.WithCancel(context.Background())
+	tail := &Tail{
+		ctx:           ctx,
+		cancel:        cancel,
+		log:           zap.S().Named("livetail"),
+		bus:           bus,
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+	tail.registerEventHandler()
+	return tail
+}
+
+func (t *Tail) registerEventHandler() {
+	t.bus.ClusteringSubscribe(eventbus.EventLiveTail, func(data []byte) {
+		var list []*Activity
+		if err := json.Unmarshal(data, &list); err != nil {
+			t.log.Errorf("failed to unmarshal activities: %v", err)
+			return
+		}
+		t.bus.Broadcast(eventbus.EventLiveTail, list)
+	})
+	t.bus.Subscribe(eventbus.EventLiveTail, func(data interface{}) {
+		t.dispatch(data.([]*Activity))
+	})
+	t.bus.ClusteringSubscribe(eventbus.EventLiveTailHeartbeat, func(data []byte) {
+		t.lastHeartbeat.Store(time.Now().UnixNano())
+	})
+	t.bus.Subscribe(eventbus.EventLiveTailHeartbeat, func(data interface{}) {
+		t.lastHeartbeat.Store(time.Now().UnixNano())
+	})
+}
+
+func (t *Tail) Start() {
+	go t.loop()
+}
+
+// Stop stops the tail and closes all subscriptions
+func (t *Tail) Stop() {
+	t.cancel()
+	t.subMux.Lock()
+	defer t.subMux.Unlock()
+	for sub := range t.subscriptions {
+		close(sub.c)
+		delete(t.subscriptions, sub)
+	}
+}
+
+func (t *Tail) loop() {
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-flush.C:
+			t.flush()
+		case <-heartbeat.C:
+			if t.subscribed() {
+				t.heartbeat()
+			}
+		}
+	}
+}
+
+func (t *Tail) active() bool {
+	return time.Since(time.Unix(0, t.lastHeartbeat.Load())) < heartbeatTimeout
+}
+
+// Publish publishes activities to subscribers of all nodes.
+// Activities are dropped when no one subscribes or the buffer is full.
+func (t *Tail) Publish(activities ...*Activity) {
+	if t == nil || len(activities) == 0 || !t.active() {
+		return
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	n := min(len(activities), maxBufferSize-len(t.buffer))
+	t.buffer = append(t.buffer, activities[:max(n, 0)]...)
+}
+
+func (t *Tail) flush() {
+	t.mux.Lock()
+	list := t.buffer
+	t.buffer = nil
+	t.mux.Unlock()
+
+	batch := make([]*Activity, 0, len(list))
+	size := 0
+	for _, activity := range list {
+		b, err := json.Marshal(activity)
+		if err != nil {
+			continue
+		}
+		if size+len(b) > maxMessageSize && len(batch) > 0 {
+			t.broadcast(batch)
+			batch = make([]*Activity, 0, len(list))
+			size = 0
+		}
+		batch = append(batch, activity)
+		size += len(b) + 1
+	}
+	if len(batch) > 0 {
+		t.broadcast(batch)
+	}
+}
+
+func (t *Tail) broadcast(list []*Activity) {
+	if err := t.bus.ClusteringBroadcast(eventbus.EventLiveTail, list); err != nil {
+		t.log.Warnf("failed to broadcast activities: %v", err)
+	}
+}
+
+func (t *Tail) heartbeat() {
+	if err := t.bus.ClusteringBroadcast(eventbus.EventLiveTailHeartbeat, struct{}{}); err != nil {
+		t.log.Warnf("failed to broadcast heartbeat: %v", err)
+	}
+}
+
+func (t *Tail) subscribed() bool {
+	t.subMux.RLock()
+	defer t.subMux.RUnlock()
+	return len(t.subscriptions) > 0
+}
+
+func (t *Tail) dispatch(list []*Activity) {
+	t.subMux.RLock()
+	defer t.subMux.RUnlock()
+	for sub := range t.subscriptions {
+		for _, activity := range list {
+			if !sub.filter.Match(activity) {
+				continue
+			}
+			select {
+			case sub.c <- activity:
+			default: // drops activities for slow subscribers
+			}
+		}
+	}
+}
+
+// Subscribe subscribes activities matching the filter, the subscription must be unsubscribed when it's done.
+func (t *Tail) Subscribe(filter Filter) *Subscription {
+	c := make(chan *Activity, subscriptionBuffer)
+	sub := &Subscription{C: c, c: c, filter: filter}
+
+	t.subMux.Lock()
+	t.subscriptions[sub] = struct{}{}
+	first := len(t.subscriptions) == 1
+	t.subMux.Unlock()
+
+	if first && !t.active() {
+		t.heartbeat()
+	}
+	return sub
+}
+
+func (t *Tail) Unsubscribe(sub *Subscription) {
+	t.subMux.Lock()
+	defer t.subMux.Unlock()
+	if _, ok := t.subscriptions[sub]; ok {
+		close(sub.c)
+		delete(t.subscriptions, sub)
+	}
+}
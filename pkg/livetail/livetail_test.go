@@ -0,0 +1,226 @@
+package livetail
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/eventbus"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+type fakeBus struct {
+	mux       sync.Mutex
+	callbacks map[string][]eventbus.Callback
+	messages  map[string][][]byte
+}
+
+func newFakeBus() *fakeBus {
+	return &fakeBus{
+		callbacks: make(map[string][]eventbus.Callback),
+		messages:  make(map[string][][]byte),
+	}
+}
+
+func (b *fakeBus) ClusteringBroadcast(channel string, data interface{}) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	b.mux.Lock()
+	b.messages[channel] = append(b.messages[channel], bytes)
+	b.mux.Unlock()
+	b.Broadcast(channel, data)
+	return nil
+}
+
+func (b *fakeBus) ClusteringSubscribe(channel string, fn func(data []byte)) {}
+
+func (b *fakeBus) Broadcast(channel string, data interface{}) {
+	b.mux.Lock()
+	callbacks := b.callbacks[channel]
+	b.mux.Unlock()
+	for _, cb := range callbacks {
+		cb(data)
+	}
+}
+
+func (b *fakeBus) Subscribe(channel string, cb eventbus.Callback) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.callbacks[channel] = append(b.callbacks[channel], cb)
+}
+
+func (b *fakeBus) Messages(channel string) [][]byte {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.messages[channel]
+}
+
+func newAttempt(wid string, endpointId string, eventType string, status entities.AttemptStatus) *Activity {
+	attempt := &entities.Attempt{
+		ID:         utils.KSUID(),
+		EventId:    utils.KSUID(),
+		EndpointId: endpointId,
+		Status:     status,
+		Event:      &entities.Event{EventType: eventType},
+	}
+	attempt.WorkspaceId = wid
+	return NewAttemptActivity(attempt)
+}
+
+func newEvent(wid string, eventType string) *Activity {
+	event := &entities.Event{ID: utils.KSUID(), EventType: eventType}
+	event.WorkspaceId = wid
+	return NewEventActivity(event)
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		desc     string
+		filter   Filter
+		activity *Activity
+		expected bool
+	}{
+		{
+			desc:     "event matches empty filter",
+			filter:   Filter{WorkspaceId: "ws"},
+			activity: newEvent("ws", "foo.bar"),
+			expected: true,
+		},
+		{
+			desc:     "other workspace",
+			filter:   Filter{WorkspaceId: "ws"},
+			activity: newEvent("other", "foo.bar"),
+			expected: false,
+		},
+		{
+			desc:     "event type",
+			filter:   Filter{WorkspaceId: "ws", EventTypes: []string{"foo.baz"}},
+			activity: newEvent("ws", "foo.bar"),
+			expected: false,
+		},
+		{
+			desc:     "event does not match endpoint filter",
+			filter:   Filter{WorkspaceId: "ws", EndpointIds: []string{"e1"}},
+			activity: newEvent("ws", "foo.bar"),
+			expected: false,
+		},
+		{
+			desc:     "attempt matches all filters",
+			filter:   Filter{WorkspaceId: "ws", EventTypes: []string{"foo.bar"}, EndpointIds: []string{"e1", "e2"}, Statuses: []string{"FAILED"}},
+			activity: newAttempt("ws", "e2", "foo.bar", entities.AttemptStatusFailure),
+			expected: true,
+		},
+		{
+			desc:     "attempt status",
+			filter:   Filter{WorkspaceId: "ws", Statuses: []string{"FAILED"}},
+			activity: newAttempt("ws", "e1", "foo.bar", entities.AttemptStatusSuccess),
+			expected: false,
+		},
+		{
+			desc:     "attempt endpoint",
+			filter:   Filter{WorkspaceId: "ws", EndpointIds: []string{"e2"}},
+			activity: newAttempt("ws", "e1", "foo.bar", entities.AttemptStatusSuccess),
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.filter.Match(test.activity))
+		})
+	}
+}
+
+func TestTail(t *testing.T) {
+	t.Run("publishes nothing without subscribers", func(t *testing.T) {
+		bus := newFakeBus()
+		tail := New(bus)
+		tail.Publish(newEvent("ws", "foo.bar"))
+		tail.flush()
+		assert.Empty(t, bus.Messages(eventbus.EventLiveTail))
+	})
+
+	t.Run("nil tail", func(t *testing.T) {
+		var tail *Tail
+		tail.Publish(newEvent("ws", "foo.bar"))
+	})
+
+	t.Run("sanity", func(t *testing.T) {
+		bus := newFakeBus()
+		tail := New(bus)
+
+		sub := tail.Subscribe(Filter{WorkspaceId: "ws", EventTypes: []string{"foo.bar"}})
+		assert.Len(t, bus.Messages(eventbus.EventLiveTailHeartbeat), 1)
+
+		tail.Publish(newEvent("ws", "foo.bar"), newEvent("ws", "foo.baz"), newAttempt("ws", "e1", "foo.bar", entities.AttemptStatusQueued))
+		tail.flush()
+		assert.Len(t, bus.Messages(eventbus.EventLiveTail), 1)
+
+		activity := <-sub.C
+		assert.Equal(t, ActivityTypeEvent, activity.Type)
+		assert.Equal(t, "foo.bar", activity.Event.EventType)
+		activity = <-sub.C
+		assert.Equal(t, ActivityTypeAttempt, activity.Type)
+		assert.Equal(t, entities.AttemptStatusQueued, activity.Attempt.Status)
+		assert.Len(t, sub.C, 0)
+
+		tail.Unsubscribe(sub)
+		_, ok := <-sub.C
+		assert.False(t, ok)
+	})
+
+	t.Run("splits large batches", func(t *testing.T) {
+		bus := newFakeBus()
+		tail := New(bus)
+		tail.Subscribe(Filter{WorkspaceId: "ws"})
+
+		eventType := strings.Repeat("x", 1000)
+		for i := 0; i < 20; i++ {
+			tail.Publish(newEvent("ws", eventType))
+		}
+		tail.flush()
+		messages := bus.Messages(eventbus.EventLiveTail)
+		assert.True(t, len(messages) > 1)
+		n := 0
+		for _, message := range messages {
+			assert.True(t, len(message) <= maxMessageSize)
+			var list []*Activity
+			assert.NoError(t, json.Unmarshal(message, &list))
+			n += len(list)
+		}
+		assert.Equal(t, 20, n)
+	})
+
+	t.Run("drops activities when buffer is full", func(t *testing.T) {
+		bus := newFakeBus()
+		tail := New(bus)
+		tail.Subscribe(Filter{WorkspaceId: "ws"})
+		for i := 0; i < maxBufferSize+10; i++ {
+			tail.Publish(newEvent("ws", "foo.bar"))
+		}
+		assert.Len(t, tail.buffer, maxBufferSize)
+	})
+
+	t.Run("stops publishing after heartbeat timeout", func(t *testing.T) {
+		bus := newFakeBus()
+		tail := New(bus)
+		tail.lastHeartbeat.Store(time.Now().Add(-heartbeatTimeout).UnixNano())
+		tail.Publish(newEvent("ws", "foo.bar"))
+		assert.Len(t, tail.buffer, 0)
+	})
+
+	t.Run("stop closes subscriptions", func(t *testing.T) {
+		bus := newFakeBus()
+		tail := New(bus)
+		sub := tail.Subscribe(Filter{WorkspaceId: "ws"})
+		tail.Stop()
+		_, ok := <-sub.C
+		assert.False(t, ok)
+		tail.Unsubscribe(sub)
+	})
+}
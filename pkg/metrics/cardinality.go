@@ -0,0 +1,69 @@
+package metrics
+
+import "sync"
+
+// OverflowLabelValue is the value that label values exceeding the cardinality limit are folded into
+const OverflowLabelValue = "other"
+
+// CardinalityLimiter caps the number of unique values of each label.
+// Once a label has reached the limit, new values are folded into OverflowLabelValue,
+// values that have been seen before are kept.
+type CardinalityLimiter struct {
+	mux    sync.RWMutex
+	limit  int
+	values map[string]map[string]struct{}
+}
+
+// NewCardinalityLimiter returns a limiter, a limit of zero disables the limiter.
+func NewCardinalityLimiter(limit int) *CardinalityLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &CardinalityLimiter{
+		limit:  limit,
+		values: make(map[string]map[string]struct{}),
+	}
+}
+
+// Limit returns the value to be recorded for the label
+func (l *CardinalityLimiter) Limit(label string, value string) string {
+	if l == nil {
+		return value
+	}
+
+	l.mux.RLock()
+	_, ok := l.values[label][value]
+	l.mux.RUnlock()
+	if ok {
+		return value
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	values, ok := l.values[label]
+	if !ok {
+		values = make(map[string]struct{})
+		l.values[label] = values
+	}
+	if _, ok := values[value]; ok {
+		return value
+	}
+	if len(values) >= l.limit {
+		return OverflowLabelValue
+	}
+	values[value] = struct{}{}
+	return value
+}
+
+// LimitLabelValues applies the limiter to label-value pairs and returns a new slice.
+func (l *CardinalityLimiter) LimitLabelValues(labelValues []string) []string {
+	limited := make([]string, len(labelValues))
+	copy(limited, labelValues)
+	if l == nil {
+		return limited
+	}
+	for i := 0; i+1 < len(limited); i += 2 {
+		limited[i+1] = l.Limit(limited[i], limited[i+1])
+	}
+	return limited
+}
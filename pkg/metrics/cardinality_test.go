@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCardinalityLimiter(t *testing.T) {
+	t.Run("sanity", func(t *testing.T) {
+		limiter := NewCardinalityLimiter(2)
+		assert.Equal(t, "a", limiter.Limit("endpoint", "a"))
+		assert.Equal(t, "b", limiter.Limit("endpoint", "b"))
+		assert.Equal(t, OverflowLabelValue, limiter.Limit("endpoint", "c"))
+		// seen values are kept
+		assert.Equal(t, "a", limiter.Limit("endpoint", "a"))
+		// limits are per label
+		assert.Equal(t, "c", limiter.Limit("source", "c"))
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		limiter := NewCardinalityLimiter(0)
+		assert.Nil(t, limiter)
+		for _, v := range []string{"a", "b", "c"} {
+			assert.Equal(t, v, limiter.Limit("endpoint", v))
+		}
+		assert.Equal(t, []string{"endpoint", "a"}, limiter.LimitLabelValues([]string{"endpoint", "a"}))
+	})
+
+	t.Run("label values", func(t *testing.T) {
+		limiter := NewCardinalityLimiter(1)
+		input := []string{"endpoint", "a", "event_type", "foo"}
+		assert.Equal(t, input, limiter.LimitLabelValues(input))
+		assert.Equal(t,
+			[]string{"endpoint", OverflowLabelValue, "event_type", "foo"},
+			limiter.LimitLabelValues([]string{"endpoint", "b", "event_type", "foo"}))
+		assert.Equal(t, []string{"endpoint", "a", "event_type", "foo"}, input)
+	})
+}
+
+func TestLabelValues(t *testing.T) {
+	base := LabelValues{"a", "1"}
+	lvs1 := base.With("b", "2")
+	lvs2 := base.With("c")
+	assert.Equal(t, LabelValues{"a", "1", "b", "2"}, lvs1)
+	assert.Equal(t, LabelValues{"a", "1", "c", "unknown"}, lvs2)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/webhookx-io/webhookx/config"
 	"github.com/webhookx-io/webhookx/pkg/schedule"
 	"go.uber.org/zap"
+	"net/http"
 	"runtime"
 	"time"
 )
@@ -17,6 +18,8 @@ type Metrics struct {
 	Enabled  bool
 	Interval time.Duration
 
+	handler http.Handler
+
 	// runtime metrics
 
 	RuntimeGoroutine    metrics.Gauge
@@ -44,6 +47,9 @@ type Metrics struct {
 	EventTotalCounter   metrics.Counter
 	EventPersistCounter metrics.Counter
 	EventPendingGauge   metrics.Gauge
+
+	// plugin metrics
+	PluginLimitExceededCounter metrics.Counter
 }
 
 func (m *Metrics) Stop() error {
@@ -64,7 +70,7 @@ func New(cfg config.MetricsConfig) (*Metrics, error) {
 
 	if len(cfg.Exports) > 0 {
 		m.Interval = time.Second * time.Duration(cfg.PushInterval)
-		err := SetupOpentelemetry(cfg.Attributes, cfg.Opentelemetry, m)
+		err := SetupOpentelemetry(cfg, m)
 		if err != nil {
 			return nil, err
 		}
@@ -75,6 +81,12 @@ func New(cfg config.MetricsConfig) (*Metrics, error) {
 	return m, nil
 }
 
+// Handler returns the handler serving metrics in the Prometheus exposition format,
+// it returns nil when the prometheus export is disabled.
+func (m *Metrics) Handler() http.Handler {
+	return m.handler
+}
+
 func (m *Metrics) collectRuntimeStats() {
 	m.RuntimeGoroutine.Set(float64(runtime.NumGoroutine()))
 
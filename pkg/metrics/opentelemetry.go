@@ -33,7 +33,7 @@ func newGRPCExporter(endpoint string) (metric.Exporter, error) {
 	return otlpmetricgrpc.New(context.Background(), opts...)
 }
 
-func SetupOpentelemetry(attributes map[string]string, cfg config.OpentelemetryMetrics, metrics *Metrics) error {
+func newPeriodicReader(cfg config.OpentelemetryMetrics, interval time.Duration) (metric.Reader, error) {
 	var err error
 	var exporter metric.Exporter
 	switch cfg.Protocol {
@@ -43,12 +43,32 @@ func SetupOpentelemetry(attributes map[string]string, cfg config.OpentelemetryMe
 		exporter, err = newGRPCExporter(cfg.Endpoint)
 	}
 	if err != nil {
-		return fmt.Errorf("failed to setup exporter: %v", err)
+		return nil, fmt.Errorf("failed to setup exporter: %v", err)
+	}
+	return metric.NewPeriodicReader(exporter, metric.WithInterval(interval)), nil
+}
+
+func SetupOpentelemetry(cfg config.MetricsConfig, metrics *Metrics) error {
+	var opts []metric.Option
+	if cfg.IsExportEnabled(config.ExportOpenTelemetry) {
+		reader, err := newPeriodicReader(cfg.Opentelemetry, metrics.Interval)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, metric.WithReader(reader))
+	}
+	if cfg.IsExportEnabled(config.ExportPrometheus) {
+		reader, handler, err := newPrometheusReader()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, metric.WithReader(reader))
+		metrics.handler = handler
 	}
 
 	// custom attributes
-	attrs := make([]attribute.KeyValue, len(attributes))
-	for name, value := range attributes {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.Attributes))
+	for name, value := range cfg.Attributes {
 		attrs = append(attrs, attribute.String(name, value))
 	}
 
@@ -63,42 +83,39 @@ func SetupOpentelemetry(attributes map[string]string, cfg config.OpentelemetryMe
 		return fmt.Errorf("failed to build resource: %w", err)
 	}
 
-	opts := []metric.PeriodicReaderOption{
-		metric.WithInterval(metrics.Interval),
-	}
-
-	meterProvider := metric.NewMeterProvider(
-		metric.WithResource(res),
-		metric.WithReader(metric.NewPeriodicReader(exporter, opts...)),
-	)
+	meterProvider := metric.NewMeterProvider(append(opts, metric.WithResource(res))...)
 	otel.SetMeterProvider(meterProvider)
 
 	meter := otel.Meter("github.com/webhookx-io/webhookx")
+	limiter := NewCardinalityLimiter(int(cfg.MaxLabelValues))
 
 	// proxy metrics
-	metrics.ProxyRequestCounter = NewCounter(meter, prefix+"request.total", "")
-	metrics.ProxyRequestDurationHistogram = NewHistogram(meter, prefix+"request.duration", "", "s")
+	metrics.ProxyRequestCounter = NewCounter(meter, prefix+"request.total", "", limiter)
+	metrics.ProxyRequestDurationHistogram = NewHistogram(meter, prefix+"request.duration", "", "s", limiter)
 
 	// runtime metrics
-	metrics.RuntimeGoroutine = NewGauge(meter, prefix+"runtime.num_goroutine", "")
-	metrics.RuntimeAlloc = NewGauge(meter, prefix+"runtime.alloc_bytes", "")
-	metrics.RuntimeSys = NewGauge(meter, prefix+"runtime.sys_bytes", "")
-	metrics.RuntimeMallocs = NewGauge(meter, prefix+"runtime.mallocs", "")
-	metrics.RuntimeFrees = NewGauge(meter, prefix+"runtime.frees", "")
-	metrics.RuntimeHeapObjects = NewGauge(meter, prefix+"runtime.heap_objects", "")
-	metrics.RuntimePauseTotalNs = NewGauge(meter, prefix+"runtime.pause_total_ns", "")
-	metrics.RuntimeGC = NewGauge(meter, prefix+"runtime.num_gc", "")
+	metrics.RuntimeGoroutine = NewGauge(meter, prefix+"runtime.num_goroutine", "", limiter)
+	metrics.RuntimeAlloc = NewGauge(meter, prefix+"runtime.alloc_bytes", "", limiter)
+	metrics.RuntimeSys = NewGauge(meter, prefix+"runtime.sys_bytes", "", limiter)
+	metrics.RuntimeMallocs = NewGauge(meter, prefix+"runtime.mallocs", "", limiter)
+	metrics.RuntimeFrees = NewGauge(meter, prefix+"runtime.frees", "", limiter)
+	metrics.RuntimeHeapObjects = NewGauge(meter, prefix+"runtime.heap_objects", "", limiter)
+	metrics.RuntimePauseTotalNs = NewGauge(meter, prefix+"runtime.pause_total_ns", "", limiter)
+	metrics.RuntimeGC = NewGauge(meter, prefix+"runtime.num_gc", "", limiter)
 
 	// worker metrics
-	metrics.AttemptTotalCounter = NewCounter(meter, prefix+"attempt.total", "")
-	metrics.AttemptFailedCounter = NewCounter(meter, prefix+"attempt.failed", "")
-	metrics.AttemptPendingGauge = NewGauge(meter, prefix+"attempt.pending", "")
-	metrics.AttemptResponseDurationHistogram = NewHistogram(meter, prefix+"attempt.response.duration", "", "s")
+	metrics.AttemptTotalCounter = NewCounter(meter, prefix+"attempt.total", "", limiter)
+	metrics.AttemptFailedCounter = NewCounter(meter, prefix+"attempt.failed", "", limiter)
+	metrics.AttemptPendingGauge = NewGauge(meter, prefix+"attempt.pending", "", limiter)
+	metrics.AttemptResponseDurationHistogram = NewHistogram(meter, prefix+"attempt.response.duration", "", "s", limiter)
 
 	// event metrics
-	metrics.EventTotalCounter = NewCounter(meter, prefix+"event.total", "")
-	metrics.EventPersistCounter = NewCounter(meter, prefix+"event.persisted", "")
-	metrics.EventPendingGauge = NewGauge(meter, prefix+"event.pending", "")
+	metrics.EventTotalCounter = NewCounter(meter, prefix+"event.total", "", limiter)
+	metrics.EventPersistCounter = NewCounter(meter, prefix+"event.persisted", "", limiter)
+	metrics.EventPendingGauge = NewGauge(meter, prefix+"event.pending", "", limiter)
+
+	// plugin metrics
+	metrics.PluginLimitExceededCounter = NewCounter(meter, prefix+"plugin.limit_exceeded", "", limiter)
 
 	return nil
 }
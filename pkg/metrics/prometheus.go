@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"net/http"
+)
+
+// newPrometheusReader returns a pull-based reader and the HTTP handler that serves its metrics.
+// A dedicated registry is used so that only WebhookX metrics are exposed.
+func newPrometheusReader() (metric.Reader, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to setup prometheus exporter: %v", err)
+	}
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return exporter, handler, nil
+}
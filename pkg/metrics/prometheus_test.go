@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/config"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheus(t *testing.T) {
+	m, err := New(config.MetricsConfig{
+		Exports:      []config.Export{config.ExportPrometheus},
+		PushInterval: 1,
+	})
+	assert.NoError(t, err)
+	defer m.Stop()
+
+	assert.NotNil(t, m.Handler())
+
+	m.ProxyRequestCounter.With("source", "test").Add(1)
+	m.AttemptResponseDurationHistogram.Observe(0.1)
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		assert.Equal(t, 200, recorder.Code)
+		body, _ := io.ReadAll(recorder.Body)
+		return string(body)
+	}
+
+	body := scrape()
+	assert.Regexp(t, `webhookx_request_total\{.*source="test"\} 1`, body)
+	assert.Contains(t, body, "webhookx_attempt_response_duration_seconds_bucket")
+	assert.Contains(t, body, `target_info{service_name="webhookx"`)
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(scrape(), "webhookx_runtime_num_goroutine")
+	}, time.Second*5, time.Millisecond*100)
+}
+
+func TestPrometheusCardinality(t *testing.T) {
+	m, err := New(config.MetricsConfig{
+		Exports:        []config.Export{config.ExportPrometheus},
+		PushInterval:   1,
+		MaxLabelValues: 1,
+	})
+	assert.NoError(t, err)
+	defer m.Stop()
+
+	m.EventTotalCounter.With("event_type", "foo").Add(1)
+	m.EventTotalCounter.With("event_type", "bar").Add(1)
+	m.EventTotalCounter.With("event_type", "baz").Add(1)
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	assert.Regexp(t, `webhookx_event_total\{event_type="foo".*\} 1`, body)
+	assert.Regexp(t, `webhookx_event_total\{event_type="other".*\} 2`, body)
+	assert.NotContains(t, body, `event_type="bar"`)
+}
+
+func TestPrometheusDisabled(t *testing.T) {
+	m, err := New(config.MetricsConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, m.Handler())
+}
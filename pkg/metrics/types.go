@@ -13,7 +13,9 @@ func (lvs LabelValues) With(labelValues ...string) LabelValues {
 	if len(labelValues)%2 != 0 {
 		labelValues = append(labelValues, "unknown")
 	}
-	return append(lvs, labelValues...)
+	values := make(LabelValues, 0, len(lvs)+len(labelValues))
+	values = append(values, lvs...)
+	return append(values, labelValues...)
 }
 
 func (lvs LabelValues) ToLabels() []attribute.KeyValue {
@@ -25,14 +27,16 @@ func (lvs LabelValues) ToLabels() []attribute.KeyValue {
 }
 
 type Counter struct {
-	lvs LabelValues
-	c   metric.Float64Counter
+	lvs     LabelValues
+	limiter *CardinalityLimiter
+	c       metric.Float64Counter
 }
 
 func (c *Counter) With(labelValues ...string) metrics.Counter {
 	return &Counter{
-		lvs: c.lvs.With(labelValues...),
-		c:   c.c,
+		lvs:     c.lvs.With(c.limiter.LimitLabelValues(labelValues)...),
+		limiter: c.limiter,
+		c:       c.c,
 	}
 }
 
@@ -40,37 +44,41 @@ func (c *Counter) Add(delta float64) {
 	c.c.Add(context.Background(), delta, metric.WithAttributes(c.lvs.ToLabels()...))
 }
 
-func NewCounter(meter metric.Meter, name string, desc string) *Counter {
+func NewCounter(meter metric.Meter, name string, desc string, limiter *CardinalityLimiter) *Counter {
 	c, _ := meter.Float64Counter(
 		name,
 		metric.WithDescription(desc),
 		metric.WithUnit("1"),
 	)
 	return &Counter{
-		c: c,
+		c:       c,
+		limiter: limiter,
 	}
 }
 
 type Gauge struct {
-	lvs LabelValues
-	g   metric.Float64Gauge
+	lvs     LabelValues
+	limiter *CardinalityLimiter
+	g       metric.Float64Gauge
 }
 
-func NewGauge(meter metric.Meter, name string, desc string) *Gauge {
+func NewGauge(meter metric.Meter, name string, desc string, limiter *CardinalityLimiter) *Gauge {
 	g, _ := meter.Float64Gauge(
 		name,
 		metric.WithDescription(desc),
 		metric.WithUnit("1"),
 	)
 	return &Gauge{
-		g: g,
+		g:       g,
+		limiter: limiter,
 	}
 }
 
 func (g *Gauge) With(labelValues ...string) metrics.Gauge {
 	return &Gauge{
-		lvs: g.lvs.With(labelValues...),
-		g:   g.g,
+		lvs:     g.lvs.With(g.limiter.LimitLabelValues(labelValues)...),
+		limiter: g.limiter,
+		g:       g.g,
 	}
 }
 
@@ -83,11 +91,12 @@ func (g *Gauge) Set(delta float64) {
 }
 
 type Histogram struct {
-	lvs LabelValues
-	h   metric.Float64Histogram
+	lvs     LabelValues
+	limiter *CardinalityLimiter
+	h       metric.Float64Histogram
 }
 
-func NewHistogram(meter metric.Meter, name string, desc string, unit string) *Histogram {
+func NewHistogram(meter metric.Meter, name string, desc string, unit string, limiter *CardinalityLimiter) *Histogram {
 	h, _ := meter.Float64Histogram(
 		name,
 		metric.WithDescription(desc),
@@ -95,14 +104,16 @@ func NewHistogram(meter metric.Meter, name string, desc string, unit string) *Hi
 		metric.WithExplicitBucketBoundaries(.005, .01, .025, .05, .075, .1, .25, .5, .75, 1, 2.5, 5, 7.5, 10),
 	)
 	return &Histogram{
-		h: h,
+		h:       h,
+		limiter: limiter,
 	}
 }
 
 func (h *Histogram) With(labelValues ...string) metrics.Histogram {
 	return &Histogram{
-		lvs: h.lvs.With(labelValues...),
-		h:   h.h,
+		lvs:     h.lvs.With(h.limiter.LimitLabelValues(labelValues)...),
+		limiter: h.limiter,
+		h:       h.h,
 	}
 }
 
@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+// OutboundDocument returns the document describing an outbound request, it is the input of
+// expressions such as plugin conditions.
+func OutboundDocument(outbound *Outbound, context *Context) map[string]interface{} {
+	event := map[string]interface{}{"data": parsePayload([]byte(outbound.Payload))}
+	endpoint := map[string]interface{}{"url": outbound.URL}
+	attempt := map[string]interface{}{}
+	if context != nil {
+		if context.Event != nil {
+			event["id"] = context.Event.ID
+			event["event_type"] = context.Event.EventType
+		}
+		if context.Endpoint != nil {
+			metadata := make(map[string]interface{}, len(context.Endpoint.Metadata))
+			for k, v := range context.Endpoint.Metadata {
+				metadata[k] = v
+			}
+			endpoint["id"] = context.Endpoint.ID
+			endpoint["name"] = context.Endpoint.Name
+			endpoint["metadata"] = metadata
+		}
+		if context.Attempt != nil {
+			attempt["id"] = context.Attempt.ID
+			attempt["number"] = float64(context.Attempt.Number)
+		}
+	}
+
+	return map[string]interface{}{
+		"event":    event,
+		"endpoint": endpoint,
+		"attempt":  attempt,
+	}
+}
+
+// InboundDocument returns the document describing an inbound request
+func InboundDocument(inbound *Inbound) map[string]interface{} {
+	headers := make(map[string]interface{})
+	for k, v := range utils.HeaderMap(inbound.Request.Header) {
+		headers[k] = v
+	}
+	return map[string]interface{}{
+		"request": map[string]interface{}{
+			"method":  inbound.Request.Method,
+			"path":    inbound.Request.URL.Path,
+			"headers": headers,
+			"body":    parsePayload(inbound.RawBody),
+		},
+	}
+}
+
+// parsePayload returns the parsed JSON payload, or the raw string if it is not JSON
+func parsePayload(payload []byte) interface{} {
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return string(payload)
+	}
+	return data
+}
@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InitFunc is called once when the application starts, before any execution
+type InitFunc func() error
+
+// ConfigChangeFunc is called when a plugin of the registration is created, updated or deleted
+type ConfigChangeFunc func(change *ConfigChange)
+
+// ShutdownFunc is called once when the application stops, it should release the resources held by the plugin
+type ShutdownFunc func() error
+
+// ConfigChange describes a changed plugin
+type ConfigChange struct {
+	// ID is the id of the changed plugin
+	ID string
+	// WorkspaceID is the workspace that the changed plugin belongs to
+	WorkspaceID string
+	// Config is the configuration of the changed plugin, it is the last configuration if the plugin is deleted
+	Config []byte
+}
+
+// WithInit sets the hook that is called when the application starts
+func WithInit(fn InitFunc) RegistrationOption {
+	return func(r *Registration) {
+		r.onInit = fn
+	}
+}
+
+// WithConfigChange sets the hook that is called when a plugin's configuration changes,
+// it is useful for plugins that cache resources derived from configurations.
+func WithConfigChange(fn ConfigChangeFunc) RegistrationOption {
+	return func(r *Registration) {
+		r.onConfigChange = fn
+	}
+}
+
+// WithShutdown sets the hook that is called when the application stops
+func WithShutdown(fn ShutdownFunc) RegistrationOption {
+	return func(r *Registration) {
+		r.onShutdown = fn
+	}
+}
+
+// InitPlugins calls the init hooks of all plugins in order of name, it stops at the first error
+func InitPlugins() error {
+	for _, r := range GetRegistrations() {
+		if r.onInit == nil {
+			continue
+		}
+		if err := r.onInit(); err != nil {
+			return fmt.Errorf("failed to initialize plugin '%s': %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// NotifyConfigChange calls the config change hook of the named plugin
+func NotifyConfigChange(name string, change *ConfigChange) {
+	r := GetRegistration(name)
+	if r == nil || r.onConfigChange == nil {
+		return
+	}
+	r.onConfigChange(change)
+}
+
+// ShutdownPlugins calls the shutdown hooks of all plugins, errors are joined
+func ShutdownPlugins() error {
+	var errs []error
+	for _, r := range GetRegistrations() {
+		if r.onShutdown == nil {
+			continue
+		}
+		if err := r.onShutdown(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown plugin '%s': %w", r.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	LimitTimeout    = "timeout"
+	LimitMemory     = "memory"
+	LimitOutputSize = "output_size"
+)
+
+// Limits is the resource limits of plugin executions, zero value means no limit
+type Limits struct {
+	Timeout time.Duration
+	// MaxMemory is the maximum memory in bytes
+	MaxMemory int64
+	// MaxOutputSize is the maximum size in bytes of the request or response produced by an execution
+	MaxOutputSize int64
+}
+
+// LimitError is returned when an execution exceeds a resource limit
+type LimitError struct {
+	Limit string
+	Value int64
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case LimitTimeout:
+		return fmt.Sprintf("execution exceeded the time limit of %s", time.Duration(e.Value))
+	default:
+		return fmt.Sprintf("execution exceeded the %s limit of %d bytes", e.Limit, e.Value)
+	}
+}
+
+// AsLimitError returns the LimitError in err's tree
+func AsLimitError(err error) (*LimitError, bool) {
+	var e *LimitError
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// CheckOutputSize returns a LimitError if size exceeds the MaxOutputSize
+func (l Limits) CheckOutputSize(size int) error {
+	if l.MaxOutputSize > 0 && int64(size) > l.MaxOutputSize {
+		return &LimitError{Limit: LimitOutputSize, Value: l.MaxOutputSize}
+	}
+	return nil
+}
+
+// MaxAbandonedRuns is the maximum number of executions that exceeded the timeout and are still running,
+// Run fails fast rather than starting new executions once it is reached.
+const MaxAbandonedRuns = 64
+
+// abandonedRuns is the number of executions that exceeded the timeout and are still running
+var abandonedRuns atomic.Int64
+
+const (
+	runRunning int32 = iota
+	runDone
+	runAbandoned
+)
+
+// Run runs fn and returns a LimitError if fn does not return within the Timeout.
+// fn is not interrupted when the timeout is exceeded, it is left running in the background
+// until it returns, it should only be used for engines that cannot be interrupted.
+// As abandoned executions keep consuming CPU, at most MaxAbandonedRuns of them are allowed at a time.
+func (l Limits) Run(fn func() error) error {
+	if l.Timeout <= 0 {
+		return fn()
+	}
+
+	if n := abandonedRuns.Load(); n >= MaxAbandonedRuns {
+		return fmt.Errorf("%w: %d executions exceeding the time limit are still running",
+			&LimitError{Limit: LimitTimeout, Value: int64(l.Timeout)}, n)
+	}
+
+	var state atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		err := fn()
+		if !state.CompareAndSwap(runRunning, runDone) {
+			abandonedRuns.Add(-1)
+		}
+		done <- err
+	}()
+
+	timer := time.NewTimer(l.Timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		if !state.CompareAndSwap(runRunning, runAbandoned) {
+			// fn returned in the meantime
+			return <-done
+		}
+		abandonedRuns.Add(1)
+		return &LimitError{Limit: LimitTimeout, Value: int64(l.Timeout)}
+	}
+}
@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLimitError(t *testing.T) {
+	err := fmt.Errorf("failed to execute plugin: %w", &LimitError{Limit: LimitTimeout, Value: int64(time.Second)})
+	e, ok := AsLimitError(err)
+	assert.True(t, ok)
+	assert.Equal(t, LimitTimeout, e.Limit)
+	assert.Equal(t, "failed to execute plugin: execution exceeded the time limit of 1s", err.Error())
+
+	_, ok = AsLimitError(fmt.Errorf("error"))
+	assert.False(t, ok)
+
+	limits := Limits{MaxOutputSize: 10}
+	assert.NoError(t, limits.CheckOutputSize(10))
+	assert.EqualError(t, limits.CheckOutputSize(11), "execution exceeded the output_size limit of 10 bytes")
+	assert.NoError(t, Limits{}.CheckOutputSize(100))
+}
+
+func TestLimitsRun(t *testing.T) {
+	assert.NoError(t, Limits{}.Run(func() error { return nil }))
+	assert.EqualError(t, Limits{}.Run(func() error { return fmt.Errorf("error") }), "error")
+
+	limits := Limits{Timeout: time.Millisecond * 10}
+	assert.NoError(t, limits.Run(func() error { return nil }))
+	err := limits.Run(func() error {
+		time.Sleep(time.Millisecond * 100)
+		return nil
+	})
+	e, ok := AsLimitError(err)
+	assert.True(t, ok)
+	assert.Equal(t, LimitTimeout, e.Limit)
+}
+
+func TestLimitsRunAbandoned(t *testing.T) {
+	limits := Limits{Timeout: time.Millisecond}
+	release := make(chan struct{})
+	for i := 0; i < MaxAbandonedRuns; i++ {
+		err := limits.Run(func() error {
+			<-release
+			return nil
+		})
+		_, ok := AsLimitError(err)
+		assert.True(t, ok)
+	}
+	assert.EqualValues(t, MaxAbandonedRuns, abandonedRuns.Load())
+
+	called := false
+	err := limits.Run(func() error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	e, ok := AsLimitError(err)
+	assert.True(t, ok)
+	assert.Equal(t, LimitTimeout, e.Limit)
+	assert.EqualError(t, err, fmt.Sprintf("execution exceeded the time limit of 1ms: %d executions exceeding the time limit are still running", MaxAbandonedRuns))
+
+	close(release)
+	assert.Eventually(t, func() bool { return abandonedRuns.Load() == 0 }, time.Second, time.Millisecond*10)
+	assert.NoError(t, Limits{Timeout: time.Second}.Run(func() error { return nil }))
+}
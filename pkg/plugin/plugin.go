@@ -3,15 +3,36 @@ package plugin
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
+	"strings"
 )
 
+// Plugin is the configuration part shared by all plugins, a plugin implements
+// InboundPlugin, OutboundPlugin or both according to its registration type.
 type Plugin interface {
-	ExecuteOutbound(outbound *Outbound, context *Context) error
-	ExecuteInbound(inbound *Inbound) (InboundResult, error)
 	ValidateConfig() error
 	MarshalConfig() ([]byte, error)
 }
 
+// InboundPlugin is a plugin that is executed on requests received by sources
+type InboundPlugin interface {
+	Plugin
+	ExecuteInbound(inbound *Inbound) (InboundResult, error)
+}
+
+// OutboundPlugin is a plugin that is executed on requests delivered to endpoints
+type OutboundPlugin interface {
+	Plugin
+	ExecuteOutbound(outbound *Outbound, context *Context) error
+}
+
+// ResponsePlugin is an outbound plugin that inspects the response after the delivery,
+// ExecuteResponse is called only if ExecuteOutbound was called for the delivery.
+type ResponsePlugin interface {
+	OutboundPlugin
+	ExecuteResponse(response *Response, context *Context) error
+}
+
 type BasePlugin[T any] struct {
 	Name   string
 	Config T
@@ -25,12 +46,24 @@ func (p *BasePlugin[T]) MarshalConfig() ([]byte, error) {
 	return json.Marshal(p.Config)
 }
 
-func (p *BasePlugin[T]) ExecuteOutbound(outbound *Outbound, context *Context) error {
-	panic("not implemented")
-}
-
-func (p *BasePlugin[T]) ExecuteInbound(inbound *Inbound) (InboundResult, error) {
-	panic("not implemented")
+// sensitiveFields returns the names of configuration fields tagged with `sensitive:"true"`
+func sensitiveFields(typ reflect.Type) []string {
+	var fields []string
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Tag.Get("sensitive") != "true" {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
 }
 
 type Outbound struct {
@@ -38,6 +71,22 @@ type Outbound struct {
 	Method  string            `json:"method"`
 	Headers map[string]string `json:"headers"`
 	Payload string            `json:"payload"`
+	// Dropped indicates the delivery is dropped by the plugin
+	Dropped bool `json:"-"`
+}
+
+// Response is the response of the delivery, plugins can override the result of the attempt
+// by changing Success and ErrorCode.
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+	// Success indicates whether the attempt succeeded
+	Success bool `json:"success"`
+	// ErrorCode is the error code of the attempt, it is empty if there is no error code.
+	// It must consist of uppercase letters, digits and underscores, and be at most 30 characters,
+	// otherwise it is dropped. It is ignored if Success is true.
+	ErrorCode string `json:"error_code"`
 }
 
 type Inbound struct {
@@ -48,6 +97,28 @@ type Inbound struct {
 
 type Context struct {
 	//Workspace *entities.Workspace
+	Event    *Event
+	Endpoint *Endpoint
+	Attempt  *Attempt
+}
+
+// Attempt is the metadata of the delivery attempt
+type Attempt struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+}
+
+// Event is the metadata of the event being delivered
+type Event struct {
+	ID        string `json:"id"`
+	EventType string `json:"event_type"`
+}
+
+// Endpoint is the metadata of the endpoint the event is delivered to
+type Endpoint struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Metadata map[string]string `json:"metadata"`
 }
 
 type InboundResult struct {
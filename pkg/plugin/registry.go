@@ -1,7 +1,12 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -10,29 +15,135 @@ type Type string
 const (
 	TypeInbound  Type = "inbound"
 	TypeOutbound Type = "outbound"
+	// TypeAny is the type of plugins that can be applied to sources and endpoints
+	TypeAny Type = "any"
 )
 
 type NewPluginFunc func(config []byte) (Plugin, error)
 
+// DefaultPriority is the priority of plugins registered without WithPriority
+const DefaultPriority = 0
+
 type Registration struct {
-	Type Type
-	New  NewPluginFunc
+	Name        string
+	Type        Type
+	Description string
+	New         NewPluginFunc
+	// Priority is the default execution priority, plugins with higher priority are executed first
+	Priority int
+	// SensitiveFields is the names of configuration fields that contain secrets,
+	// they are encrypted at rest and redacted in responses.
+	SensitiveFields []string
+	// Schema is the JSON schema of the configuration, it is nil if the configuration type is not declared
+	Schema *openapi3.Schema
+
+	config reflect.Type
+
+	// lifecycle hooks
+	onInit         InitFunc
+	onConfigChange ConfigChangeFunc
+	onShutdown     ShutdownFunc
 }
 
 var mux sync.RWMutex
 var registry = make(map[string]*Registration)
 
-func RegisterPlugin(typ Type, name string, fn NewPluginFunc) {
+type RegistrationOption func(r *Registration)
+
+// WithPriority sets the default execution priority of the plugin
+func WithPriority(priority int) RegistrationOption {
+	return func(r *Registration) {
+		r.Priority = priority
+	}
+}
+
+// WithConfig declares the configuration type of the plugin, the JSON schema is generated from it
+// and fields tagged with `sensitive:"true"` are declared as sensitive fields.
+func WithConfig(config any) RegistrationOption {
+	return func(r *Registration) {
+		r.config = reflect.TypeOf(config)
+	}
+}
+
+// WithSensitiveFields declares the configuration fields that contain secrets
+func WithSensitiveFields(fields ...string) RegistrationOption {
+	return func(r *Registration) {
+		r.SensitiveFields = append(r.SensitiveFields, fields...)
+	}
+}
+
+// WithDescription sets the description of the plugin
+func WithDescription(description string) RegistrationOption {
+	return func(r *Registration) {
+		r.Description = description
+	}
+}
+
+func RegisterPlugin(typ Type, name string, fn NewPluginFunc, opts ...RegistrationOption) {
 	mux.Lock()
 	defer mux.Unlock()
 	if _, ok := registry[name]; ok {
 		panic(fmt.Sprintf("plugin '%s' already registered", name))
 	}
 
-	registry[name] = &Registration{
-		Type: typ,
-		New:  fn,
+	r := &Registration{
+		Name:     name,
+		Type:     typ,
+		New:      fn,
+		Priority: DefaultPriority,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.config != nil {
+		schema, err := GenerateSchema(reflect.New(r.config).Interface())
+		if err != nil {
+			panic(fmt.Sprintf("failed to generate schema of plugin '%s': %v", name, err))
+		}
+		r.Schema = schema
+		for _, field := range sensitiveFields(r.config) {
+			if !slices.Contains(r.SensitiveFields, field) {
+				r.SensitiveFields = append(r.SensitiveFields, field)
+			}
+		}
+	}
+	registry[name] = r
+}
+
+// NewPlugin returns a plugin with the configuration, the plugin must implement the interfaces
+// required by the type of the registration.
+func (r *Registration) NewPlugin(config []byte) (Plugin, error) {
+	p, err := r.New(config)
+	if err != nil {
+		return nil, err
 	}
+	if err := checkType(r.Type, p); err != nil {
+		return nil, fmt.Errorf("plugin '%s' %v", r.Name, err)
+	}
+	return p, nil
+}
+
+// checkType checks whether the plugin implements the interfaces required by the type
+func checkType(typ Type, p Plugin) error {
+	_, inbound := p.(InboundPlugin)
+	_, outbound := p.(OutboundPlugin)
+	switch typ {
+	case TypeInbound:
+		if !inbound {
+			return errors.New("does not implement InboundPlugin")
+		}
+	case TypeOutbound:
+		if !outbound {
+			return errors.New("does not implement OutboundPlugin")
+		}
+	case TypeAny:
+		if !inbound || !outbound {
+			return errors.New("does not implement both InboundPlugin and OutboundPlugin")
+		}
+	default:
+		return fmt.Errorf("has unknown type '%s'", typ)
+	}
+	return nil
 }
 
 func GetRegistration(name string) *Registration {
@@ -40,3 +151,17 @@ func GetRegistration(name string) *Registration {
 	defer mux.RUnlock()
 	return registry[name]
 }
+
+// GetRegistrations returns the registrations of all plugins sorted by name
+func GetRegistrations() []*Registration {
+	mux.RLock()
+	defer mux.RUnlock()
+	list := make([]*Registration, 0, len(registry))
+	for _, r := range registry {
+		list = append(list, r)
+	}
+	slices.SortFunc(list, func(a, b *Registration) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryTestInbound struct {
+	BasePlugin[struct{}]
+}
+
+func (p *registryTestInbound) ValidateConfig() error {
+	return nil
+}
+
+func (p *registryTestInbound) ExecuteInbound(inbound *Inbound) (InboundResult, error) {
+	return InboundResult{}, nil
+}
+
+func newRegistryTestInbound(config []byte) (Plugin, error) {
+	return &registryTestInbound{}, nil
+}
+
+func TestNewPluginType(t *testing.T) {
+	RegisterPlugin(TypeInbound, "registry-test-inbound", newRegistryTestInbound)
+	RegisterPlugin(TypeOutbound, "registry-test-outbound", newRegistryTestInbound)
+	RegisterPlugin(TypeAny, "registry-test-any", newRegistryTestInbound)
+
+	p, err := GetRegistration("registry-test-inbound").NewPlugin(nil)
+	assert.NoError(t, err)
+	assert.Implements(t, (*InboundPlugin)(nil), p)
+
+	_, err = GetRegistration("registry-test-outbound").NewPlugin(nil)
+	assert.EqualError(t, err, "plugin 'registry-test-outbound' does not implement OutboundPlugin")
+
+	_, err = GetRegistration("registry-test-any").NewPlugin(nil)
+	assert.EqualError(t, err, "plugin 'registry-test-any' does not implement both InboundPlugin and OutboundPlugin")
+}
+
+type registryTestConfig struct {
+	Token string `json:"token" sensitive:"true"`
+	Name  string `json:"name"`
+}
+
+func TestRegisterPluginConfig(t *testing.T) {
+	RegisterPlugin(TypeInbound, "registry-test-config", newRegistryTestInbound,
+		WithConfig(registryTestConfig{}),
+		WithSensitiveFields("password"))
+
+	r := GetRegistration("registry-test-config")
+	assert.Equal(t, []string{"password", "token"}, r.SensitiveFields)
+	assert.NotNil(t, r.Schema)
+	assert.True(t, r.Schema.Properties["token"].Value.WriteOnly)
+
+	RegisterPlugin(TypeInbound, "registry-test-no-config", newRegistryTestInbound)
+	r = GetRegistration("registry-test-no-config")
+	assert.Nil(t, r.SensitiveFields)
+	assert.Nil(t, r.Schema)
+}
+
+func TestLifecycle(t *testing.T) {
+	var calls []string
+	var changes []*ConfigChange
+	RegisterPlugin(TypeInbound, "registry-test-lifecycle-a", newRegistryTestInbound,
+		WithInit(func() error {
+			calls = append(calls, "init a")
+			return nil
+		}),
+		WithConfigChange(func(change *ConfigChange) {
+			changes = append(changes, change)
+		}),
+		WithShutdown(func() error {
+			calls = append(calls, "shutdown a")
+			return errors.New("boom")
+		}),
+	)
+	RegisterPlugin(TypeInbound, "registry-test-lifecycle-b", newRegistryTestInbound,
+		WithInit(func() error {
+			calls = append(calls, "init b")
+			return nil
+		}),
+		WithShutdown(func() error {
+			calls = append(calls, "shutdown b")
+			return nil
+		}),
+	)
+
+	assert.NoError(t, InitPlugins())
+
+	NotifyConfigChange("registry-test-lifecycle-a", &ConfigChange{ID: "1", Config: []byte(`{}`)})
+	NotifyConfigChange("registry-test-lifecycle-b", &ConfigChange{ID: "2"})
+	NotifyConfigChange("unknown", &ConfigChange{ID: "3"})
+	assert.Equal(t, []*ConfigChange{{ID: "1", Config: []byte(`{}`)}}, changes)
+
+	err := ShutdownPlugins()
+	assert.EqualError(t, err, "failed to shutdown plugin 'registry-test-lifecycle-a': boom")
+	assert.Equal(t, []string{"init a", "init b", "shutdown a", "shutdown b"}, calls)
+}
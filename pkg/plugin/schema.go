@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+// GenerateSchema generates the JSON schema of the configuration struct. The properties are named by the
+// `json` tags, constrained by the `validate` tags (required, oneof, min, max, url), and annotated by
+// the `default` and `description` tags. Fields tagged with `sensitive:"true"` are write-only.
+func GenerateSchema(config interface{}) (*openapi3.Schema, error) {
+	ref, err := openapi3gen.NewSchemaRefForValue(config, nil, openapi3gen.SchemaCustomizer(customizeSchema))
+	if err != nil {
+		return nil, err
+	}
+	resetElements(ref.Value)
+	return ref.Value, nil
+}
+
+// resetElements resets the annotations and constraints of array items and map values,
+// which are customized with the tags of their fields.
+func resetElements(schema *openapi3.Schema) {
+	for _, ref := range schema.Properties {
+		resetElements(ref.Value)
+	}
+	var elem *openapi3.Schema
+	if schema.Items != nil {
+		elem = schema.Items.Value
+	} else if schema.AdditionalProperties.Schema != nil {
+		elem = schema.AdditionalProperties.Schema.Value
+	}
+	if elem == nil {
+		return
+	}
+	elem.Description = ""
+	elem.Default = nil
+	elem.Enum = nil
+	elem.Format = ""
+	elem.WriteOnly = false
+	elem.MinLength, elem.MaxLength = 0, nil
+	elem.MinItems, elem.MaxItems = 0, nil
+	elem.MinProps, elem.MaxProps = 0, nil
+	elem.Min, elem.Max = nil, nil
+	resetElements(elem)
+}
+
+func customizeSchema(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || !hasRule(f.Tag.Get("validate"), "required") {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	if description := tag.Get("description"); description != "" {
+		schema.Description = description
+	}
+	if tag.Get("sensitive") == "true" {
+		schema.WriteOnly = true
+	}
+	if def, ok := tag.Lookup("default"); ok {
+		schema.Default = parseValue(schema, def)
+	}
+
+	for _, rule := range strings.Split(tag.Get("validate"), ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "oneof":
+			for _, v := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, parseValue(schema, v))
+			}
+		case "min", "max":
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			setBound(schema, key == "min", n)
+		case "url":
+			schema.Format = "uri"
+		}
+	}
+
+	return nil
+}
+
+func hasRule(validate string, rule string) bool {
+	for _, r := range strings.Split(validate, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// setBound sets the min or max constraint of the schema, which bounds the length of strings,
+// the number of items of arrays and properties of objects, and the value of numbers.
+func setBound(schema *openapi3.Schema, min bool, n uint64) {
+	switch {
+	case schema.Type.Is(openapi3.TypeString):
+		if min {
+			schema.MinLength = n
+		} else {
+			schema.MaxLength = &n
+		}
+	case schema.Type.Is(openapi3.TypeArray):
+		if min {
+			schema.MinItems = n
+		} else {
+			schema.MaxItems = &n
+		}
+	case schema.Type.Is(openapi3.TypeObject):
+		if min {
+			schema.MinProps = n
+		} else {
+			schema.MaxProps = &n
+		}
+	default:
+		v := float64(n)
+		if min {
+			schema.Min = &v
+		} else {
+			schema.Max = &v
+		}
+	}
+}
+
+// parseValue parses the tag value by the type of the schema
+func parseValue(schema *openapi3.Schema, s string) interface{} {
+	switch {
+	case schema.Type.Is(openapi3.TypeInteger), schema.Type.Is(openapi3.TypeNumber):
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			return v
+		}
+	case schema.Type.Is(openapi3.TypeBoolean):
+		if v, err := strconv.ParseBool(s); err == nil {
+			return v
+		}
+	}
+	return s
+}
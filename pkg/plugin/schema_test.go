@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaTestNested struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type schemaTestConfig struct {
+	URL     string             `json:"url" validate:"required,url" description:"The URL."`
+	Mode    string             `json:"mode" validate:"oneof=fast slow" default:"fast"`
+	Retries int                `json:"retries" validate:"min=1,max=10" default:"3"`
+	Secret  string             `json:"secret" sensitive:"true"`
+	Headers map[string]string  `json:"headers" validate:"max=5" description:"The headers."`
+	Items   []schemaTestNested `json:"items"`
+	Ignored string             `json:"-"`
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema(&schemaTestConfig{})
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(schema)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"required": ["url"],
+		"properties": {
+			"url": {"type": "string", "format": "uri", "description": "The URL."},
+			"mode": {"type": "string", "enum": ["fast", "slow"], "default": "fast"},
+			"retries": {"type": "integer", "minimum": 1, "maximum": 10, "default": 3},
+			"secret": {"type": "string", "writeOnly": true},
+			"headers": {"type": "object", "maxProperties": 5, "description": "The headers.", "additionalProperties": {"type": "string"}},
+			"items": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {"name": {"type": "string"}}
+				}
+			}
+		}
+	}`, string(b))
+}
@@ -9,6 +9,7 @@ type Message struct {
 	Value       []byte
 	Time        time.Time
 	WorkspaceID string
+	TraceParent string
 }
 
 type HandlerFunc func(ctx context.Context, messages []*Message) error
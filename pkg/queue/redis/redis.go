@@ -52,6 +52,7 @@ func (q *RedisQueue) Enqueue(ctx context.Context, message *queue.Message) error
 			"data", message.Value,
 			"time", message.Time.UnixMilli(),
 			"ws_id", message.WorkspaceID,
+			"traceparent", message.TraceParent,
 		},
 	}
 	return q.c.XAdd(ctx, args).Err()
@@ -73,6 +74,10 @@ func toMessage(values map[string]interface{}) *queue.Message {
 		message.WorkspaceID = wsid
 	}
 
+	if traceparent, ok := values["traceparent"].(string); ok {
+		message.TraceParent = traceparent
+	}
+
 	return message
 }
 
@@ -0,0 +1,123 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// EnvProvider reads secrets from environment variables
+type EnvProvider struct{}
+
+func (p *EnvProvider) GetValue(_ context.Context, path string, key string) (string, error) {
+	if key != "" {
+		return "", fmt.Errorf("env provider does not support keys")
+	}
+	value, ok := os.LookupEnv(path)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable '%s' is not set", ErrNotFound, path)
+	}
+	return value, nil
+}
+
+// FileProvider reads secrets from files, the trailing newline is trimmed
+type FileProvider struct{}
+
+func (p *FileProvider) GetValue(_ context.Context, path string, key string) (string, error) {
+	if key != "" {
+		return "", fmt.Errorf("file provider does not support keys")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: file '%s' does not exist", ErrNotFound, path)
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// VaultProvider reads secrets from a HashiCorp Vault compatible server.
+// Both KV version 1 and version 2 secrets engines are supported,
+// e.g. {secret://vault/secret/data/webhookx#password} reads the field "password" of KV v2 secret "webhookx".
+type VaultProvider struct {
+	Address   string
+	Token     string
+	Namespace string
+	Client    *http.Client
+}
+
+type vaultResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+func (p *VaultProvider) GetValue(ctx context.Context, path string, key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("vault provider requires a key, e.g. {secret://vault/%s#<key>}", path)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	url := strings.TrimRight(p.Address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: vault secret '%s' does not exist", ErrNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var res vaultResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return "", fmt.Errorf("invalid vault response: %v", err)
+	}
+
+	data := res.Data
+	// KV version 2 wraps the secret in data.data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: vault secret '%s' has no key '%s'", ErrNotFound, path, key)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	default:
+		b, _ := json.Marshal(v)
+		return string(b), nil
+	}
+}
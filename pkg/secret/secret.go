@@ -0,0 +1,328 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// referenceRegex matches secret references in the form of "{secret://<provider>/<path>[#<key>]}"
+var referenceRegex = regexp.MustCompile(`\{secret://([a-z0-9_]+)/([^}#]+)(?:#([^}]+))?\}`)
+
+var ErrNotFound = errors.New("secret not found")
+
+// Provider retrieves secrets from a secret store
+type Provider interface {
+	// GetValue returns the secret at path, key selects a field when the secret has multiple fields
+	GetValue(ctx context.Context, path string, key string) (string, error)
+}
+
+// Reference is a reference to a secret
+type Reference struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+func (r Reference) String() string {
+	s := "{secret://" + r.Provider + "/" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s + "}"
+}
+
+// HasReference reports whether the value contains secret references
+func HasReference(value string) bool {
+	return strings.Contains(value, "{secret://") && referenceRegex.MatchString(value)
+}
+
+// ParseReferences returns the secret references of the value
+func ParseReferences(value string) []Reference {
+	matches := referenceRegex.FindAllStringSubmatch(value, -1)
+	refs := make([]Reference, 0, len(matches))
+	for _, match := range matches {
+		refs = append(refs, Reference{Provider: match[1], Path: match[2], Key: match[3]})
+	}
+	return refs
+}
+
+// Policy restricts the secret references that can be used, it applies to plugin configurations
+// which are managed through the Admin API rather than by operators.
+type Policy struct {
+	// Providers is the allowed providers
+	Providers []string
+	// PathPrefixes is the allowed path prefixes, all paths are allowed if it is empty.
+	// A prefix matches whole path segments, "secret/app" allows "secret/app/key" but not "secret/apps".
+	PathPrefixes []string
+}
+
+// Allows reports whether the reference is allowed by the policy, paths containing ".." are never allowed
+func (p *Policy) Allows(ref Reference) bool {
+	if !slices.Contains(p.Providers, ref.Provider) {
+		return false
+	}
+	if slices.Contains(strings.Split(ref.Path, "/"), "..") {
+		return false
+	}
+	if len(p.PathPrefixes) == 0 {
+		return true
+	}
+	name := path.Clean(ref.Path)
+	for _, prefix := range p.PathPrefixes {
+		prefix = strings.TrimSuffix(path.Clean(prefix), "/")
+		if name == prefix || strings.HasPrefix(name, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error if the value contains references that are not allowed by the policy
+func (p *Policy) Check(value string) error {
+	for _, ref := range ParseReferences(value) {
+		if !p.Allows(ref) {
+			return fmt.Errorf("secret reference %s is not allowed", ref)
+		}
+	}
+	return nil
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Manager resolves secret references with registered providers.
+// Resolved secrets are cached for CacheTTL, a secret is fetched again after it
+// expires so that rotated secrets are picked up.
+type Manager struct {
+	providers map[string]Provider
+	ttl       time.Duration
+
+	mux   sync.Mutex
+	cache map[Reference]*cacheEntry
+}
+
+type Options struct {
+	// CacheTTL is the duration for which resolved secrets are cached, zero disables caching
+	CacheTTL time.Duration
+}
+
+// NewManager returns a manager with env and file providers registered
+func NewManager(opts Options) *Manager {
+	m := &Manager{
+		providers: make(map[string]Provider),
+		ttl:       opts.CacheTTL,
+		cache:     make(map[Reference]*cacheEntry),
+	}
+	m.Register("env", &EnvProvider{})
+	m.Register("file", &FileProvider{})
+	return m
+}
+
+// Register registers a provider
+func (m *Manager) Register(name string, provider Provider) {
+	m.providers[name] = provider
+}
+
+// Get returns the secret of the reference
+func (m *Manager) Get(ctx context.Context, ref Reference) (string, error) {
+	provider, ok := m.providers[ref.Provider]
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider '%s'", ref.Provider)
+	}
+
+	if m.ttl <= 0 {
+		return provider.GetValue(ctx, ref.Path, ref.Key)
+	}
+
+	m.mux.Lock()
+	entry, ok := m.cache[ref]
+	m.mux.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := provider.GetValue(ctx, ref.Path, ref.Key)
+	if err != nil {
+		if ok {
+			// serves the stale secret while the provider is unavailable
+			zap.S().Warnf("failed to refresh secret %s: %v", ref, err)
+			return entry.value, nil
+		}
+		return "", err
+	}
+
+	m.mux.Lock()
+	m.cache[ref] = &cacheEntry{value: value, expiresAt: time.Now().Add(m.ttl)}
+	m.mux.Unlock()
+	return value, nil
+}
+
+// Resolve replaces secret references in the value with their secrets
+func (m *Manager) Resolve(ctx context.Context, value string) (string, error) {
+	if !HasReference(value) {
+		return value, nil
+	}
+	var err error
+	resolved := referenceRegex.ReplaceAllStringFunc(value, func(s string) string {
+		if err != nil {
+			return s
+		}
+		match := referenceRegex.FindStringSubmatch(s)
+		ref := Reference{Provider: match[1], Path: match[2], Key: match[3]}
+		v, e := m.Get(ctx, ref)
+		if e != nil {
+			err = fmt.Errorf("failed to resolve secret %s: %w", ref, e)
+			return s
+		}
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// ResolveJSON resolves secret references in string values of the JSON document.
+// The document is returned as is when it contains no references.
+func (m *Manager) ResolveJSON(ctx context.Context, data []byte) ([]byte, error) {
+	if !HasReference(string(data)) {
+		return data, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v, err := m.resolveValue(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (m *Manager) resolveValue(ctx context.Context, v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return m.Resolve(ctx, val)
+	case map[string]interface{}:
+		for k, e := range val {
+			resolved, err := m.resolveValue(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = resolved
+		}
+	case []interface{}:
+		for i, e := range val {
+			resolved, err := m.resolveValue(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = resolved
+		}
+	}
+	return v, nil
+}
+
+// ResolveStruct resolves secret references in string fields, string slices and string maps of the struct pointer
+func (m *Manager) ResolveStruct(ctx context.Context, ptr interface{}) error {
+	return m.resolveReflect(ctx, reflect.ValueOf(ptr))
+}
+
+func (m *Manager) resolveReflect(ctx context.Context, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return m.resolveReflect(ctx, v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := m.resolveReflect(ctx, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := m.resolveReflect(ctx, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			resolved, err := m.Resolve(ctx, iter.Value().String())
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(resolved).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		resolved, err := m.Resolve(ctx, v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(resolved)
+	}
+	return nil
+}
+
+var manager atomic.Pointer[Manager]
+
+var policy atomic.Pointer[Policy]
+
+// DefaultPolicy only allows references to the vault provider, env and file providers would
+// expose the environment variables and files of the host.
+var DefaultPolicy = Policy{Providers: []string{"vault"}}
+
+func init() {
+	Set(NewManager(Options{}))
+	SetPolicy(&DefaultPolicy)
+}
+
+// Set sets the global manager
+func Set(m *Manager) {
+	manager.Store(m)
+}
+
+// SetPolicy sets the global policy
+func SetPolicy(p *Policy) {
+	policy.Store(p)
+}
+
+// GetPolicy returns the global policy
+func GetPolicy() *Policy {
+	return policy.Load()
+}
+
+// Resolve resolves secret references in the value with the global manager
+func Resolve(ctx context.Context, value string) (string, error) {
+	return manager.Load().Resolve(ctx, value)
+}
+
+// ResolveJSON resolves secret references in the JSON document with the global manager
+func ResolveJSON(ctx context.Context, data []byte) ([]byte, error) {
+	return manager.Load().ResolveJSON(ctx, data)
+}
@@ -0,0 +1,204 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProvider struct {
+	value string
+	err   error
+	calls int
+}
+
+func (p *mockProvider) GetValue(_ context.Context, path string, key string) (string, error) {
+	p.calls++
+	return p.value, p.err
+}
+
+func TestParseReferences(t *testing.T) {
+	refs := ParseReferences("{secret://env/FOO} and {secret://vault/secret/data/app#password}")
+	assert.Equal(t, []Reference{
+		{Provider: "env", Path: "FOO"},
+		{Provider: "vault", Path: "secret/data/app", Key: "password"},
+	}, refs)
+	assert.Equal(t, "{secret://vault/secret/data/app#password}", refs[1].String())
+	assert.False(t, HasReference("plain"))
+	assert.False(t, HasReference("{secret://}"))
+}
+
+func TestPolicy(t *testing.T) {
+	p := &Policy{Providers: []string{"vault"}, PathPrefixes: []string{"secret/data/webhookx/"}}
+	assert.True(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/webhookx/app"}))
+	assert.False(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/admin"}))
+	assert.False(t, p.Allows(Reference{Provider: "env", Path: "secret/data/webhookx/app"}))
+	assert.True(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/webhookx"}))
+	assert.True(t, p.Allows(Reference{Provider: "vault", Path: "secret/data//webhookx/app"}))
+	assert.False(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/webhookx-admin/app"}))
+	assert.False(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/webhookx/../admin"}))
+	assert.False(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/webhookx/../../../etc/passwd"}))
+	assert.False(t, p.Allows(Reference{Provider: "vault", Path: "secret/data/webhookx/app/.."}))
+
+	fp := &Policy{Providers: []string{"file"}, PathPrefixes: []string{"/"}}
+	assert.True(t, fp.Allows(Reference{Provider: "file", Path: "/run/secrets/key"}))
+	assert.False(t, fp.Allows(Reference{Provider: "file", Path: "run/secrets/key"}))
+	assert.False(t, fp.Allows(Reference{Provider: "file", Path: "/run/secrets/../../etc/passwd"}))
+	assert.NoError(t, p.Check("plain"))
+	assert.EqualError(t, p.Check("{secret://vault/secret/data/webhookx/app} {secret://env/FOO}"),
+		"secret reference {secret://env/FOO} is not allowed")
+
+	assert.True(t, DefaultPolicy.Allows(Reference{Provider: "vault", Path: "any"}))
+	assert.False(t, DefaultPolicy.Allows(Reference{Provider: "file", Path: "/etc/passwd"}))
+	assert.False(t, DefaultPolicy.Allows(Reference{Provider: "vault", Path: "secret/../sys/policy"}))
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("WEBHOOKX_TEST_SECRET", "foo")
+	filename := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(filename, []byte("bar\n"), 0600))
+
+	m := NewManager(Options{})
+
+	value, err := m.Resolve(context.TODO(), "{secret://env/WEBHOOKX_TEST_SECRET}")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", value)
+
+	value, err = m.Resolve(context.TODO(), "Bearer {secret://file/"+filename+"}")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer bar", value)
+
+	value, err = m.Resolve(context.TODO(), "plain")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", value)
+
+	_, err = m.Resolve(context.TODO(), "{secret://env/WEBHOOKX_TEST_NOT_EXIST}")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.EqualError(t, err, "failed to resolve secret {secret://env/WEBHOOKX_TEST_NOT_EXIST}: secret not found: environment variable 'WEBHOOKX_TEST_NOT_EXIST' is not set")
+
+	_, err = m.Resolve(context.TODO(), "{secret://unknown/foo}")
+	assert.EqualError(t, err, "failed to resolve secret {secret://unknown/foo}: unknown secret provider 'unknown'")
+}
+
+func TestCache(t *testing.T) {
+	m := NewManager(Options{CacheTTL: 50 * time.Millisecond})
+	provider := &mockProvider{value: "v1"}
+	m.Register("mock", provider)
+
+	value, err := m.Resolve(context.TODO(), "{secret://mock/foo}")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	// cached
+	provider.value = "v2"
+	value, _ = m.Resolve(context.TODO(), "{secret://mock/foo}")
+	assert.Equal(t, "v1", value)
+	assert.Equal(t, 1, provider.calls)
+
+	// refreshed after expiration
+	time.Sleep(60 * time.Millisecond)
+	value, _ = m.Resolve(context.TODO(), "{secret://mock/foo}")
+	assert.Equal(t, "v2", value)
+	assert.Equal(t, 2, provider.calls)
+
+	// stale value when provider fails
+	time.Sleep(60 * time.Millisecond)
+	provider.err = errors.New("unavailable")
+	value, err = m.Resolve(context.TODO(), "{secret://mock/foo}")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", value)
+}
+
+func TestResolveJSON(t *testing.T) {
+	t.Setenv("WEBHOOKX_TEST_SECRET", "foo")
+	m := NewManager(Options{})
+
+	data := []byte(`{"a": "{secret://env/WEBHOOKX_TEST_SECRET}", "b": ["{secret://env/WEBHOOKX_TEST_SECRET}"], "c": {"d": 1}}`)
+	resolved, err := m.ResolveJSON(context.TODO(), data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"a": "foo", "b": ["foo"], "c": {"d": 1}}`, string(resolved))
+
+	data = []byte(`{"a": "b"}`)
+	resolved, err = m.ResolveJSON(context.TODO(), data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, resolved)
+}
+
+func TestResolveStruct(t *testing.T) {
+	t.Setenv("WEBHOOKX_TEST_SECRET", "foo")
+	m := NewManager(Options{})
+
+	type Password string
+	type Nested struct {
+		Password Password
+	}
+	v := struct {
+		Name   string
+		Nested Nested
+		Ptr    *Nested
+		List   []string
+		Map    map[string]string
+		Number int
+	}{
+		Name:   "{secret://env/WEBHOOKX_TEST_SECRET}",
+		Nested: Nested{Password: "{secret://env/WEBHOOKX_TEST_SECRET}"},
+		Ptr:    &Nested{Password: "{secret://env/WEBHOOKX_TEST_SECRET}"},
+		List:   []string{"{secret://env/WEBHOOKX_TEST_SECRET}"},
+		Map:    map[string]string{"k": "{secret://env/WEBHOOKX_TEST_SECRET}"},
+		Number: 1,
+	}
+	assert.NoError(t, m.ResolveStruct(context.TODO(), &v))
+	assert.Equal(t, "foo", v.Name)
+	assert.EqualValues(t, "foo", v.Nested.Password)
+	assert.EqualValues(t, "foo", v.Ptr.Password)
+	assert.Equal(t, []string{"foo"}, v.List)
+	assert.Equal(t, map[string]string{"k": "foo"}, v.Map)
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(403)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app":
+			_, _ = w.Write([]byte(`{"data": {"data": {"password": "v2"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/app":
+			_, _ = w.Write([]byte(`{"data": {"password": "v1"}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	provider := &VaultProvider{Address: server.URL, Token: "token"}
+
+	value, err := provider.GetValue(context.TODO(), "secret/data/app", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", value)
+
+	value, err = provider.GetValue(context.TODO(), "kv/app", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	_, err = provider.GetValue(context.TODO(), "kv/app", "username")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = provider.GetValue(context.TODO(), "kv/notfound", "password")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = provider.GetValue(context.TODO(), "kv/app", "")
+	assert.EqualError(t, err, "vault provider requires a key, e.g. {secret://vault/kv/app#<key>}")
+
+	provider.Token = "invalid"
+	_, err = provider.GetValue(context.TODO(), "kv/app", "password")
+	assert.EqualError(t, err, "vault returned status 403")
+}
@@ -0,0 +1,55 @@
+package semaphore
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireScript holds permits in a sorted set scored by expiration time,
+// expired permits are removed before counting, so permits of crashed holders are not leaked.
+//
+// KEYS[1]: key
+// ARGV[1]: holder
+// ARGV[2]: limit
+// ARGV[3]: ttl in milliseconds
+var acquireScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local ttl = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+if redis.call('ZSCORE', KEYS[1], ARGV[1]) == false and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+  return 0
+end
+redis.call('ZADD', KEYS[1], now + ttl, ARGV[1])
+local pttl = redis.call('PTTL', KEYS[1])
+if pttl < ttl then
+  redis.call('PEXPIRE', KEYS[1], ttl)
+end
+return 1
+`)
+
+type RedisSemaphore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisSemaphore(client *redis.Client, prefix string) *RedisSemaphore {
+	return &RedisSemaphore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisSemaphore) Acquire(ctx context.Context, key string, holder string, limit int, ttl time.Duration) (bool, error) {
+	res, err := acquireScript.Run(ctx, s.client, []string{s.prefix + key}, holder, limit, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func (s *RedisSemaphore) Release(ctx context.Context, key string, holder string) error {
+	return s.client.ZRem(ctx, s.prefix+key, holder).Err()
+}
@@ -0,0 +1,15 @@
+package semaphore
+
+import (
+	"context"
+	"time"
+)
+
+// Semaphore is a distributed counting semaphore
+type Semaphore interface {
+	// Acquire acquires a permit of key for holder, reports false when all permits of key are held.
+	// The permit is released automatically after ttl if holder fails to release it.
+	Acquire(ctx context.Context, key string, holder string, limit int, ttl time.Duration) (bool, error)
+	// Release releases the permit of key held by holder
+	Release(ctx context.Context, key string, holder string) error
+}
@@ -1,8 +1,10 @@
 package taskqueue
 
 type MessageData struct {
-	EventID    string `json:"event_id"`
-	EndpointId string `json:"endpoint_id"`
-	Attempt    int    `json:"attempt"`
-	Event      string `json:"event"`
+	EventID     string `json:"event_id"`
+	EndpointId  string `json:"endpoint_id"`
+	Attempt     int    `json:"attempt"`
+	Event       string `json:"event"`
+	EventType   string `json:"event_type,omitempty"`
+	TraceParent string `json:"traceparent,omitempty"`
 }
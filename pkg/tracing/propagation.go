@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const traceparentHeader = "traceparent"
+
+var traceContext = propagation.TraceContext{}
+
+// TraceParent returns the W3C traceparent of the span in ctx, or an empty string if ctx has no valid span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+	return carrier.Get(traceparentHeader)
+}
+
+// ContextWithTraceParent returns a copy of ctx carrying the remote span described by traceparent,
+// so that spans started from it continue the same trace.
+func ContextWithTraceParent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	return traceContext.Extract(ctx, propagation.MapCarrier{traceparentHeader: traceparent})
+}
+
+// InjectHeader injects the W3C trace context of the span in ctx into header.
+func InjectHeader(ctx context.Context, header http.Header) {
+	traceContext.Inject(ctx, propagation.HeaderCarrier(header))
+}
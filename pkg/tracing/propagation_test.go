@@ -0,0 +1,26 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceParent(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		ctx := ContextWithTraceParent(context.Background(), traceparent)
+		assert.Equal(t, traceparent, TraceParent(ctx))
+	})
+
+	t.Run("without span", func(t *testing.T) {
+		assert.Equal(t, "", TraceParent(context.Background()))
+	})
+
+	t.Run("empty or invalid traceparent", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Equal(t, ctx, ContextWithTraceParent(ctx, ""))
+		assert.Equal(t, "", TraceParent(ContextWithTraceParent(ctx, "invalid")))
+	})
+}
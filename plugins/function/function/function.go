@@ -1,19 +1,22 @@
 package function
 
 import (
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/plugins/function/function/javascript"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
-	"time"
 )
 
 type Function interface {
 	Execute(ctx *sdk.ExecutionContext) (sdk.ExecutionResult, error)
+	// ExecuteResponse executes the response handler after Execute, it does nothing if there is no response handler
+	ExecuteResponse(ctx *sdk.ExecutionContext) (sdk.ExecutionResult, error)
 }
 
-func New(language string, script string) Function {
+func New(language string, script string, limits plugin.Limits) Function {
 	if language == "javascript" {
 		return javascript.New(script, javascript.Options{
-			Timeout: time.Second,
+			Timeout:       limits.Timeout,
+			MaxOutputSize: limits.MaxOutputSize,
 		})
 	}
 	panic("unsupported language: " + language)
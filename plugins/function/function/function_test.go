@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
 	"github.com/webhookx-io/webhookx/utils"
 	"go.uber.org/zap"
@@ -13,13 +14,14 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 func NewJavaScript(script string) Function {
-	return New("javascript", script)
+	return New("javascript", script, plugin.Limits{Timeout: time.Second})
 }
 
 var _ = Describe("JavaScript", Ordered, func() {
@@ -269,7 +271,10 @@ var _ = Describe("JavaScript", Ordered, func() {
 			`
 			function := NewJavaScript(script)
 			_, err := function.Execute(nil)
-			assert.Equal(GinkgoT(), "timeout", err.Error())
+			assert.Equal(GinkgoT(), "execution exceeded the time limit of 1s", err.Error())
+			e, ok := plugin.AsLimitError(err)
+			assert.True(GinkgoT(), ok)
+			assert.Equal(GinkgoT(), plugin.LimitTimeout, e.Limit)
 		})
 
 		It("timeout during executing function", func() {
@@ -280,7 +285,165 @@ var _ = Describe("JavaScript", Ordered, func() {
 			}`
 			function := NewJavaScript(script)
 			_, err := function.Execute(nil)
-			assert.Equal(GinkgoT(), "timeout", err.Error())
+			assert.Equal(GinkgoT(), "execution exceeded the time limit of 1s", err.Error())
+			e, ok := plugin.AsLimitError(err)
+			assert.True(GinkgoT(), ok)
+			assert.Equal(GinkgoT(), plugin.LimitTimeout, e.Limit)
+		})
+	})
+
+	Context("limits", func() {
+		It("should interrupt infinite loop", func() {
+			function := New("javascript", `function handle() { while (true) {} }`, plugin.Limits{Timeout: time.Millisecond * 100})
+			start := time.Now()
+			_, err := function.Execute(nil)
+			assert.Less(GinkgoT(), time.Since(start), time.Second)
+			_, ok := plugin.AsLimitError(err)
+			assert.True(GinkgoT(), ok)
+		})
+
+		It("should limit the body size", func() {
+			function := New("javascript", `function handle() { webhookx.request.setBody("0123456789") }`, plugin.Limits{MaxOutputSize: 5})
+			_, err := function.Execute(&sdk.ExecutionContext{HTTPRequest: &sdk.HTTPRequest{R: &http.Request{}}})
+			assert.EqualError(GinkgoT(), err, "execution exceeded the output_size limit of 5 bytes")
+		})
+
+		It("should limit the response size", func() {
+			function := New("javascript", `function handle() { webhookx.response.exit(200, {}, "0123456789") }`, plugin.Limits{MaxOutputSize: 5})
+			_, err := function.Execute(&sdk.ExecutionContext{HTTPRequest: &sdk.HTTPRequest{R: &http.Request{}}})
+			assert.EqualError(GinkgoT(), err, "execution exceeded the output_size limit of 5 bytes")
+		})
+	})
+
+	Context("outbound", func() {
+		newContext := func() *sdk.ExecutionContext {
+			return &sdk.ExecutionContext{
+				Outbound: &plugin.Outbound{
+					URL:     "https://example.com",
+					Method:  "POST",
+					Headers: map[string]string{"Content-Type": "application/json"},
+					Payload: `{"key":"value"}`,
+				},
+				PluginContext: &plugin.Context{
+					Event:    &plugin.Event{ID: "evt_1", EventType: "foo.bar"},
+					Endpoint: &plugin.Endpoint{ID: "ep_1", Name: "test", Metadata: map[string]string{"region": "eu"}},
+				},
+			}
+		}
+
+		It("should rewrite request", func() {
+			script := `function handle() {
+				var req = webhookx.request
+				req.setURL(req.getURL() + "/" + webhookx.endpoint.metadata.region)
+				req.setMethod("PUT")
+				req.setHeader("x-event-type", webhookx.event.event_type)
+				req.setHeader("x-content-type", req.getHeader("content-type"))
+				req.removeHeader("Content-Type")
+				var body = JSON.parse(req.getBody())
+				body.event_id = webhookx.event.id
+				body.endpoint = webhookx.endpoint.name
+				req.setBody(JSON.stringify(body))
+				var bytes = webhookx.utils.hmac('SHA-256', "secret", req.getBody())
+				req.setHeader("x-signature", webhookx.utils.encode('hex', bytes))
+			}`
+			ctx := newContext()
+			_, err := NewJavaScript(script).Execute(ctx)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "https://example.com/eu", ctx.Outbound.URL)
+			assert.Equal(GinkgoT(), "PUT", ctx.Outbound.Method)
+			assert.Equal(GinkgoT(), `{"key":"value","event_id":"evt_1","endpoint":"test"}`, ctx.Outbound.Payload)
+			utilsSDK := sdk.NewUtilsSDK()
+			assert.Equal(GinkgoT(), map[string]string{
+				"x-event-type":   "foo.bar",
+				"x-content-type": "application/json",
+				"x-signature":    utilsSDK.Encode("hex", utilsSDK.Hmac("SHA-256", "secret", ctx.Outbound.Payload)),
+			}, ctx.Outbound.Headers)
+			assert.False(GinkgoT(), ctx.Outbound.Dropped)
+		})
+
+		It("should drop delivery", func() {
+			script := `function handle() {
+				if (webhookx.event.event_type === 'foo.bar') {
+					webhookx.request.drop()
+				}
+			}`
+			ctx := newContext()
+			_, err := NewJavaScript(script).Execute(ctx)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), ctx.Outbound.Dropped)
+		})
+
+		It("should return null for missing header", func() {
+			script := `function handle() { return webhookx.request.getHeader("x-missing") === null }`
+			res, err := NewJavaScript(script).Execute(newContext())
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), true, res.ReturnValue)
+		})
+
+		Context("response", func() {
+			newResponseContext := func(body string) *sdk.ExecutionContext {
+				ctx := newContext()
+				ctx.Response = &plugin.Response{
+					StatusCode: 200,
+					Headers:    map[string]string{"Content-Type": "application/json"},
+					Body:       body,
+					Success:    true,
+				}
+				return ctx
+			}
+
+			It("should override the result of the attempt", func() {
+				script := `
+				var count = 0
+				function handle() { count++ }
+				function handleResponse() {
+					var res = webhookx.response
+					if (res.getStatus() === 200 && res.getHeader("content-type") === "application/json" && !JSON.parse(res.getBody()).ok) {
+						res.setSuccess(false)
+						res.setErrorCode("RESPONSE_REJECTED")
+					}
+					return [count, webhookx.request.getURL(), webhookx.event.id]
+				}`
+				fn := NewJavaScript(script)
+				_, err := fn.Execute(newContext())
+				assert.NoError(GinkgoT(), err)
+
+				ctx := newResponseContext(`{"ok":false}`)
+				res, err := fn.ExecuteResponse(ctx)
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), []interface{}{int64(1), "https://example.com", "evt_1"}, res.ReturnValue)
+				assert.False(GinkgoT(), ctx.Response.Success)
+				assert.Equal(GinkgoT(), "RESPONSE_REJECTED", ctx.Response.ErrorCode)
+
+				ctx = newResponseContext(`{"ok":true}`)
+				_, err = fn.ExecuteResponse(ctx)
+				assert.NoError(GinkgoT(), err)
+				assert.True(GinkgoT(), ctx.Response.Success)
+				assert.Equal(GinkgoT(), "", ctx.Response.ErrorCode)
+			})
+
+			It("should return null for missing error code", func() {
+				script := `
+				function handle() {}
+				function handleResponse() { return webhookx.response.getErrorCode() === null }`
+				fn := NewJavaScript(script)
+				_, err := fn.Execute(newContext())
+				assert.NoError(GinkgoT(), err)
+				res, err := fn.ExecuteResponse(newResponseContext(`{}`))
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), true, res.ReturnValue)
+			})
+
+			It("should do nothing when handleResponse is not defined", func() {
+				fn := NewJavaScript(`function handle() {}`)
+				_, err := fn.Execute(newContext())
+				assert.NoError(GinkgoT(), err)
+				ctx := newResponseContext(`{}`)
+				res, err := fn.ExecuteResponse(ctx)
+				assert.NoError(GinkgoT(), err)
+				assert.Nil(GinkgoT(), res.ReturnValue)
+				assert.True(GinkgoT(), ctx.Response.Success)
+			})
 		})
 	})
 
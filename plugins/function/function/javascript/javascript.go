@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/dop251/goja"
 	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
 	"strings"
 	"time"
@@ -14,10 +15,14 @@ type JavaScript struct {
 	opts   Options
 	vm     *goja.Runtime
 	script string
+	// loaded indicates whether the script has been run in the vm
+	loaded bool
 }
 
 type Options struct {
 	Timeout time.Duration
+	// MaxOutputSize is the maximum size of the request body and response body set by the script
+	MaxOutputSize int64
 }
 
 func New(script string, opts Options) *JavaScript {
@@ -32,7 +37,18 @@ func New(script string, opts Options) *JavaScript {
 
 var cache, _ = lru.New[string, *goja.Program](128)
 
+// Execute calls the handle function
 func (m *JavaScript) Execute(ctx *sdk.ExecutionContext) (res sdk.ExecutionResult, err error) {
+	return m.execute(ctx, "handle", true)
+}
+
+// ExecuteResponse calls the handleResponse function in the vm of the previous execution,
+// it does nothing if handleResponse is not defined.
+func (m *JavaScript) ExecuteResponse(ctx *sdk.ExecutionContext) (res sdk.ExecutionResult, err error) {
+	return m.execute(ctx, "handleResponse", false)
+}
+
+func (m *JavaScript) execute(ctx *sdk.ExecutionContext, name string, required bool) (res sdk.ExecutionResult, err error) {
 	vm := m.vm
 
 	err = vm.GlobalObject().Set("webhookx", sdk.NewSDK(&sdk.Options{
@@ -62,31 +78,39 @@ func (m *JavaScript) Execute(ctx *sdk.ExecutionContext) (res sdk.ExecutionResult
 	}
 
 	if m.opts.Timeout > 0 {
-		timer := time.AfterFunc(m.opts.Timeout, func() { vm.Interrupt(errors.New("timeout")) })
+		timer := time.AfterFunc(m.opts.Timeout, func() {
+			vm.Interrupt(&plugin.LimitError{Limit: plugin.LimitTimeout, Value: int64(m.opts.Timeout)})
+		})
 		defer timer.Stop()
 	}
 
-	program, ok := cache.Get(m.script)
-	if !ok {
-		program, err = goja.Compile("", m.script, false)
-		if err != nil {
-			return res, err
+	if !m.loaded {
+		program, ok := cache.Get(m.script)
+		if !ok {
+			program, err = goja.Compile("", m.script, false)
+			if err != nil {
+				return res, err
+			}
+			cache.Add(m.script, program)
 		}
-		cache.Add(m.script, program)
-	}
 
-	_, err = vm.RunProgram(program)
-	if err != nil {
-		if e, ok := err.(*goja.InterruptedError); ok {
-			err = e.Unwrap()
+		_, err = vm.RunProgram(program)
+		if err != nil {
+			if e, ok := err.(*goja.InterruptedError); ok {
+				err = e.Unwrap()
+			}
+			return
 		}
-		return
+		m.loaded = true
 	}
 
 	var handle func() (interface{}, error)
-	handleFunction := vm.Get("handle")
+	handleFunction := vm.Get(name)
 	if handleFunction == nil {
-		return res, errors.New("handle is not defined")
+		if !required {
+			return
+		}
+		return res, errors.New(name + " is not defined")
 	}
 	err = vm.ExportTo(handleFunction, &handle)
 	if err != nil {
@@ -106,5 +130,26 @@ func (m *JavaScript) Execute(ctx *sdk.ExecutionContext) (res sdk.ExecutionResult
 
 	res.ReturnValue = output
 
+	err = m.checkOutputSize(ctx, &res)
 	return
 }
+
+func (m *JavaScript) checkOutputSize(ctx *sdk.ExecutionContext, res *sdk.ExecutionResult) error {
+	limits := plugin.Limits{MaxOutputSize: m.opts.MaxOutputSize}
+	if ctx != nil && ctx.HTTPRequest != nil {
+		if err := limits.CheckOutputSize(len(ctx.HTTPRequest.Body)); err != nil {
+			return err
+		}
+	}
+	if ctx != nil && ctx.Outbound != nil {
+		if err := limits.CheckOutputSize(len(ctx.Outbound.Payload)); err != nil {
+			return err
+		}
+	}
+	if res.HTTPResponse != nil {
+		if err := limits.CheckOutputSize(len(res.HTTPResponse.Body)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
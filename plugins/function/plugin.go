@@ -5,14 +5,38 @@ import (
 	"github.com/webhookx-io/webhookx/plugins/function/function"
 	"github.com/webhookx-io/webhookx/plugins/function/sdk"
 	"github.com/webhookx-io/webhookx/utils"
+	"sync"
+	"time"
 )
 
+var (
+	limitsMux sync.RWMutex
+	limits    = plugin.Limits{Timeout: time.Second}
+)
+
+// SetLimits sets the resource limits of function executions
+func SetLimits(l plugin.Limits) {
+	limitsMux.Lock()
+	defer limitsMux.Unlock()
+	limits = l
+}
+
+func getLimits() plugin.Limits {
+	limitsMux.RLock()
+	defer limitsMux.RUnlock()
+	return limits
+}
+
 type Config struct {
-	Function string `json:"function" validate:"required,max=1048576"`
+	Function string `json:"function" validate:"required,max=1048576" description:"The JavaScript source that defines the handle function."`
 }
 
 type FunctionPlugin struct {
 	plugin.BasePlugin[Config]
+
+	// fn and outbound are of the outbound execution, which are reused by the response execution
+	fn       function.Function
+	outbound *plugin.Outbound
 }
 
 func New(config []byte) (plugin.Plugin, error) {
@@ -33,7 +57,7 @@ func (p *FunctionPlugin) ValidateConfig() error {
 }
 
 func (p *FunctionPlugin) ExecuteInbound(inbound *plugin.Inbound) (result plugin.InboundResult, err error) {
-	fn := function.New("javascript", p.Config.Function)
+	fn := function.New("javascript", p.Config.Function, getLimits())
 
 	req := sdk.HTTPRequest{
 		R:    inbound.Request,
@@ -60,3 +84,27 @@ func (p *FunctionPlugin) ExecuteInbound(inbound *plugin.Inbound) (result plugin.
 	result.Payload = req.Body
 	return
 }
+
+func (p *FunctionPlugin) ExecuteOutbound(outbound *plugin.Outbound, context *plugin.Context) error {
+	p.fn = function.New("javascript", p.Config.Function, getLimits())
+	p.outbound = outbound
+
+	_, err := p.fn.Execute(&sdk.ExecutionContext{
+		Outbound:      outbound,
+		PluginContext: context,
+	})
+	return err
+}
+
+func (p *FunctionPlugin) ExecuteResponse(response *plugin.Response, context *plugin.Context) error {
+	if p.fn == nil {
+		return nil
+	}
+
+	_, err := p.fn.ExecuteResponse(&sdk.ExecutionContext{
+		Outbound:      p.outbound,
+		Response:      response,
+		PluginContext: context,
+	})
+	return err
+}
@@ -3,14 +3,19 @@ package sdk
 import (
 	"github.com/dop251/goja"
 	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"net/http"
 )
 
 type SDK struct {
-	Request  *RequestSDK  `json:"request"`
-	Response *ResponseSDK `json:"response"`
-	Utils    *UtilsSDK    `json:"utils"`
-	Log      *LogSDK      `json:"log"`
+	// Request is *RequestSDK for inbound executions or *OutboundRequestSDK for outbound executions
+	Request interface{} `json:"request"`
+	// Response is *ResponseSDK for inbound executions or *OutboundResponseSDK for response executions
+	Response interface{}      `json:"response"`
+	Utils    *UtilsSDK        `json:"utils"`
+	Log      *LogSDK          `json:"log"`
+	Event    *plugin.Event    `json:"event"`
+	Endpoint *plugin.Endpoint `json:"endpoint"`
 
 	opts *Options
 }
@@ -22,13 +27,24 @@ type Options struct {
 }
 
 func NewSDK(opts *Options) *SDK {
-	return &SDK{
+	sdk := &SDK{
 		Request:  NewRequestSDK(opts),
 		Utils:    NewUtilsSDK(),
 		Log:      NewLogSDK(),
 		Response: NewResponseSDK(opts),
 		opts:     opts,
 	}
+	if ctx := opts.Context; ctx != nil && ctx.Outbound != nil {
+		sdk.Request = NewOutboundRequestSDK(opts)
+		if ctx.Response != nil {
+			sdk.Response = NewOutboundResponseSDK(opts)
+		}
+		if ctx.PluginContext != nil {
+			sdk.Event = ctx.PluginContext.Event
+			sdk.Endpoint = ctx.PluginContext.Endpoint
+		}
+	}
+	return sdk
 }
 
 type HTTPRequest struct {
@@ -45,6 +61,12 @@ type HTTPResponse struct {
 type ExecutionContext struct {
 	HTTPRequest *HTTPRequest
 
+	// Outbound is the outbound request of outbound executions
+	Outbound *plugin.Outbound
+	// Response is the response of the delivery in response executions
+	Response      *plugin.Response
+	PluginContext *plugin.Context
+
 	Workspace *entities.Workspace
 	Source    *entities.Source
 	Event     *entities.Event
@@ -0,0 +1,145 @@
+package sdk
+
+import (
+	"github.com/dop251/goja"
+	"maps"
+	"strings"
+)
+
+// OutboundRequestSDK is the request SDK of outbound executions
+type OutboundRequestSDK struct {
+	opts *Options
+}
+
+func NewOutboundRequestSDK(opts *Options) *OutboundRequestSDK {
+	return &OutboundRequestSDK{
+		opts: opts,
+	}
+}
+
+func (sdk *OutboundRequestSDK) GetURL() string {
+	return sdk.opts.Context.Outbound.URL
+}
+
+func (sdk *OutboundRequestSDK) SetURL(url string) {
+	sdk.opts.Context.Outbound.URL = url
+}
+
+func (sdk *OutboundRequestSDK) GetMethod() string {
+	return sdk.opts.Context.Outbound.Method
+}
+
+func (sdk *OutboundRequestSDK) SetMethod(method string) {
+	sdk.opts.Context.Outbound.Method = method
+}
+
+func (sdk *OutboundRequestSDK) GetHeaders() map[string]string {
+	return maps.Clone(sdk.opts.Context.Outbound.Headers)
+}
+
+// headerKey returns the key of the header matched case-insensitively
+func (sdk *OutboundRequestSDK) headerKey(name string) (string, bool) {
+	headers := sdk.opts.Context.Outbound.Headers
+	if _, ok := headers[name]; ok {
+		return name, true
+	}
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func (sdk *OutboundRequestSDK) GetHeader(call goja.FunctionCall) goja.Value {
+	key, ok := sdk.headerKey(call.Argument(0).String())
+	if !ok {
+		return goja.Null()
+	}
+	return sdk.opts.VM.ToValue(sdk.opts.Context.Outbound.Headers[key])
+}
+
+func (sdk *OutboundRequestSDK) SetHeader(name string, value string) {
+	outbound := sdk.opts.Context.Outbound
+	if key, ok := sdk.headerKey(name); ok {
+		delete(outbound.Headers, key)
+	}
+	if outbound.Headers == nil {
+		outbound.Headers = make(map[string]string)
+	}
+	outbound.Headers[name] = value
+}
+
+func (sdk *OutboundRequestSDK) RemoveHeader(name string) {
+	if key, ok := sdk.headerKey(name); ok {
+		delete(sdk.opts.Context.Outbound.Headers, key)
+	}
+}
+
+func (sdk *OutboundRequestSDK) GetBody() string {
+	return sdk.opts.Context.Outbound.Payload
+}
+
+func (sdk *OutboundRequestSDK) SetBody(body string) {
+	sdk.opts.Context.Outbound.Payload = body
+}
+
+// Drop drops the delivery
+func (sdk *OutboundRequestSDK) Drop() {
+	sdk.opts.Context.Outbound.Dropped = true
+}
+
+// OutboundResponseSDK is the response SDK of response executions, which inspect the response of the delivery
+type OutboundResponseSDK struct {
+	opts *Options
+}
+
+func NewOutboundResponseSDK(opts *Options) *OutboundResponseSDK {
+	return &OutboundResponseSDK{
+		opts: opts,
+	}
+}
+
+func (sdk *OutboundResponseSDK) GetStatus() int {
+	return sdk.opts.Context.Response.StatusCode
+}
+
+func (sdk *OutboundResponseSDK) GetHeaders() map[string]string {
+	return maps.Clone(sdk.opts.Context.Response.Headers)
+}
+
+func (sdk *OutboundResponseSDK) GetHeader(call goja.FunctionCall) goja.Value {
+	name := call.Argument(0).String()
+	for k, v := range sdk.opts.Context.Response.Headers {
+		if strings.EqualFold(k, name) {
+			return sdk.opts.VM.ToValue(v)
+		}
+	}
+	return goja.Null()
+}
+
+func (sdk *OutboundResponseSDK) GetBody() string {
+	return sdk.opts.Context.Response.Body
+}
+
+// IsSuccess reports whether the attempt succeeded
+func (sdk *OutboundResponseSDK) IsSuccess() bool {
+	return sdk.opts.Context.Response.Success
+}
+
+// SetSuccess overrides whether the attempt succeeded
+func (sdk *OutboundResponseSDK) SetSuccess(success bool) {
+	sdk.opts.Context.Response.Success = success
+}
+
+func (sdk *OutboundResponseSDK) GetErrorCode() goja.Value {
+	if code := sdk.opts.Context.Response.ErrorCode; code != "" {
+		return sdk.opts.VM.ToValue(code)
+	}
+	return goja.Null()
+}
+
+// SetErrorCode sets the error code of the attempt, e.g. "RESPONSE_REJECTED"
+func (sdk *OutboundResponseSDK) SetErrorCode(code string) {
+	sdk.opts.Context.Response.ErrorCode = code
+}
@@ -3,12 +3,28 @@ package plugins
 import (
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/plugins/function"
+	"github.com/webhookx-io/webhookx/plugins/transform"
 	"github.com/webhookx-io/webhookx/plugins/wasm"
 	"github.com/webhookx-io/webhookx/plugins/webhookx_signature"
 )
 
 func LoadPlugins() {
-	plugin.RegisterPlugin(plugin.TypeInbound, "function", function.New)
-	plugin.RegisterPlugin(plugin.TypeOutbound, "wasm", wasm.New)
-	plugin.RegisterPlugin(plugin.TypeOutbound, "webhookx-signature", webhookx_signature.New)
+	// plugins with higher priority are executed first, e.g. requests are signed after being transformed
+	plugin.RegisterPlugin(plugin.TypeOutbound, "transform", transform.New,
+		plugin.WithConfig(transform.Config{}),
+		plugin.WithPriority(1000),
+		plugin.WithDescription("Transforms outbound requests declaratively with templates, JSONata or JMESPath."))
+	plugin.RegisterPlugin(plugin.TypeAny, "function", function.New,
+		plugin.WithConfig(function.Config{}),
+		plugin.WithPriority(900),
+		plugin.WithDescription("Customizes inbound and outbound requests with JavaScript."))
+	plugin.RegisterPlugin(plugin.TypeAny, "wasm", wasm.New,
+		plugin.WithConfig(wasm.Config{}),
+		plugin.WithPriority(800),
+		plugin.WithDescription("Customizes inbound and outbound requests with WebAssembly modules."),
+		plugin.WithShutdown(wasm.Shutdown))
+	plugin.RegisterPlugin(plugin.TypeOutbound, "webhookx-signature", webhookx_signature.New,
+		plugin.WithConfig(webhookx_signature.Config{}),
+		plugin.WithPriority(100),
+		plugin.WithDescription("Signs outbound requests with HMAC-SHA256."))
 }
@@ -0,0 +1,100 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"text/template"
+
+	"github.com/blues/jsonata-go"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/jmespath/go-jmespath"
+)
+
+const (
+	EngineTemplate = "template"
+	EngineJSONata  = "jsonata"
+	EngineJMESPath = "jmespath"
+)
+
+// expression is a compiled expression of an engine
+type expression interface {
+	// evaluate evaluates the expression against input, a nil result means undefined
+	evaluate(input interface{}) (interface{}, error)
+}
+
+var cache, _ = lru.New[string, expression](1024)
+
+// compile compiles the source of the engine, compiled expressions are cached
+func compile(engine string, source string) (expression, error) {
+	key := engine + "\x00" + source
+	if expr, ok := cache.Get(key); ok {
+		return expr, nil
+	}
+
+	var expr expression
+	switch engine {
+	case EngineTemplate:
+		tmpl, err := template.New("").Option("missingkey=zero").Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return nil, err
+		}
+		expr = &templateExpression{tmpl: tmpl}
+	case EngineJSONata:
+		e, err := jsonata.Compile(source)
+		if err != nil {
+			return nil, err
+		}
+		expr = &jsonataExpression{expr: e}
+	case EngineJMESPath:
+		e, err := jmespath.Compile(source)
+		if err != nil {
+			return nil, err
+		}
+		expr = &jmespathExpression{expr: e}
+	default:
+		return nil, errors.New("unknown engine: " + engine)
+	}
+
+	cache.Add(key, expr)
+	return expr, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+type templateExpression struct {
+	tmpl *template.Template
+}
+
+func (e *templateExpression) evaluate(input interface{}) (interface{}, error) {
+	var buf bytes.Buffer
+	if err := e.tmpl.Execute(&buf, input); err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+type jsonataExpression struct {
+	expr *jsonata.Expr
+}
+
+func (e *jsonataExpression) evaluate(input interface{}) (interface{}, error) {
+	v, err := e.expr.Eval(input)
+	if errors.Is(err, jsonata.ErrUndefined) {
+		return nil, nil
+	}
+	return v, err
+}
+
+type jmespathExpression struct {
+	expr *jmespath.JMESPath
+}
+
+func (e *jmespathExpression) evaluate(input interface{}) (interface{}, error) {
+	return e.expr.Search(input)
+}
@@ -0,0 +1,180 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/utils"
+	"sync"
+	"time"
+)
+
+var (
+	limitsMux sync.RWMutex
+	limits    = plugin.Limits{Timeout: time.Second}
+)
+
+// SetLimits sets the resource limits of expression evaluations
+func SetLimits(l plugin.Limits) {
+	limitsMux.Lock()
+	defer limitsMux.Unlock()
+	limits = l
+}
+
+func getLimits() plugin.Limits {
+	limitsMux.RLock()
+	defer limitsMux.RUnlock()
+	return limits
+}
+
+type Config struct {
+	Engine  string            `json:"engine" validate:"oneof=template jsonata jmespath" default:"template" description:"The expression engine."`
+	Payload string            `json:"payload" validate:"required" description:"The expression producing the payload."`
+	Headers map[string]string `json:"headers" description:"The expressions producing header values."`
+	URL     string            `json:"url" description:"The expression producing the URL."`
+	Method  string            `json:"method" description:"The expression producing the method."`
+}
+
+type TransformPlugin struct {
+	plugin.BasePlugin[Config]
+}
+
+func New(config []byte) (plugin.Plugin, error) {
+	p := &TransformPlugin{}
+	p.Name = "transform"
+
+	p.Config.Engine = EngineTemplate
+
+	if config != nil {
+		if err := p.UnmarshalConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *TransformPlugin) ValidateConfig() error {
+	if err := utils.Validate(p.Config); err != nil {
+		return err
+	}
+
+	e := errs.NewValidateError(errs.ErrRequestValidation)
+	if _, err := compile(p.Config.Engine, p.Config.Payload); err != nil {
+		e.Fields["payload"] = fmt.Sprintf("invalid expression: %v", err)
+	}
+	if p.Config.URL != "" {
+		if _, err := compile(p.Config.Engine, p.Config.URL); err != nil {
+			e.Fields["url"] = fmt.Sprintf("invalid expression: %v", err)
+		}
+	}
+	if p.Config.Method != "" {
+		if _, err := compile(p.Config.Engine, p.Config.Method); err != nil {
+			e.Fields["method"] = fmt.Sprintf("invalid expression: %v", err)
+		}
+	}
+	headers := make(map[string]interface{})
+	for name, source := range p.Config.Headers {
+		if _, err := compile(p.Config.Engine, source); err != nil {
+			headers[name] = fmt.Sprintf("invalid expression: %v", err)
+		}
+	}
+	if len(headers) > 0 {
+		e.Fields["headers"] = headers
+	}
+	if len(e.Fields) > 0 {
+		return e
+	}
+	return nil
+}
+
+func (p *TransformPlugin) evaluate(source string, input interface{}) (interface{}, error) {
+	expr, err := compile(p.Config.Engine, source)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	err = getLimits().Run(func() error {
+		v, err := expr.evaluate(input)
+		value = v
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// evaluateString evaluates the expression to a string, ok is false when the result is undefined
+func (p *TransformPlugin) evaluateString(source string, input interface{}) (value string, ok bool, err error) {
+	v, err := p.evaluate(source, input)
+	if err != nil || v == nil {
+		return "", false, err
+	}
+	if s, isString := v.(string); isString {
+		return s, true, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false, err
+	}
+	return string(b), true, nil
+}
+
+func (p *TransformPlugin) ExecuteOutbound(outbound *plugin.Outbound, context *plugin.Context) error {
+	input := plugin.OutboundDocument(outbound, context)
+
+	payload, err := p.evaluate(p.Config.Payload, input)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate payload: %w", err)
+	}
+	if p.Config.Engine == EngineTemplate {
+		outbound.Payload = payload.(string)
+	} else {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate payload: %w", err)
+		}
+		outbound.Payload = string(b)
+	}
+	if err := getLimits().CheckOutputSize(len(outbound.Payload)); err != nil {
+		return err
+	}
+
+	if p.Config.URL != "" {
+		url, ok, err := p.evaluateString(p.Config.URL, input)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate url: %w", err)
+		}
+		if ok {
+			outbound.URL = url
+		}
+	}
+
+	if p.Config.Method != "" {
+		method, ok, err := p.evaluateString(p.Config.Method, input)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate method: %w", err)
+		}
+		if ok {
+			outbound.Method = method
+		}
+	}
+
+	for name, source := range p.Config.Headers {
+		value, ok, err := p.evaluateString(source, input)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to evaluate header '%s'", name), err)
+		}
+		if ok {
+			if outbound.Headers == nil {
+				outbound.Headers = make(map[string]string)
+			}
+			outbound.Headers[name] = value
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/pkg/errs"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+)
+
+func newPlugin(t *testing.T, config map[string]interface{}) plugin.OutboundPlugin {
+	b, err := json.Marshal(config)
+	assert.NoError(t, err)
+	p, err := New(b)
+	assert.NoError(t, err)
+	assert.NoError(t, p.ValidateConfig())
+	return p.(plugin.OutboundPlugin)
+}
+
+func newOutbound() *plugin.Outbound {
+	return &plugin.Outbound{
+		URL:     "https://example.com",
+		Method:  "POST",
+		Headers: map[string]string{"content-type": "application/json"},
+		Payload: `{"order":{"id":"o_1","total":42}}`,
+	}
+}
+
+func newContext() *plugin.Context {
+	return &plugin.Context{
+		Event:    &plugin.Event{ID: "evt_1", EventType: "order.paid"},
+		Endpoint: &plugin.Endpoint{ID: "ep_1", Name: "slack", Metadata: map[string]string{"channel": "#orders"}},
+		Attempt:  &plugin.Attempt{ID: "att_1", Number: 2},
+	}
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	p := newPlugin(t, map[string]interface{}{
+		"payload": `{"channel":{{ json .endpoint.metadata.channel }},"text":"{{ .event.event_type }} {{ .event.data.order.id }}"}`,
+		"headers": map[string]string{"x-attempt": "{{ .attempt.number }}"},
+		"url":     "{{ .endpoint.url }}/hooks/{{ .endpoint.name }}",
+	})
+
+	outbound := newOutbound()
+	assert.NoError(t, p.ExecuteOutbound(outbound, newContext()))
+	assert.JSONEq(t, `{"channel":"#orders","text":"order.paid o_1"}`, outbound.Payload)
+	assert.Equal(t, "https://example.com/hooks/slack", outbound.URL)
+	assert.Equal(t, "POST", outbound.Method)
+	assert.Equal(t, "2", outbound.Headers["x-attempt"])
+	assert.Equal(t, "application/json", outbound.Headers["content-type"])
+}
+
+func TestExecuteJSONata(t *testing.T) {
+	p := newPlugin(t, map[string]interface{}{
+		"engine":  "jsonata",
+		"payload": `{"id": event.id, "total": event.data.order.total * 2}`,
+		"method":  `"PUT"`,
+		"headers": map[string]string{"x-event-type": "event.event_type", "x-missing": "event.missing"},
+	})
+
+	outbound := newOutbound()
+	assert.NoError(t, p.ExecuteOutbound(outbound, newContext()))
+	assert.JSONEq(t, `{"id":"evt_1","total":84}`, outbound.Payload)
+	assert.Equal(t, "PUT", outbound.Method)
+	assert.Equal(t, "order.paid", outbound.Headers["x-event-type"])
+	_, ok := outbound.Headers["x-missing"]
+	assert.False(t, ok)
+}
+
+func TestExecuteJMESPath(t *testing.T) {
+	p := newPlugin(t, map[string]interface{}{
+		"engine":  "jmespath",
+		"payload": `{id: event.data.order.id, attempt: attempt.number}`,
+		"headers": map[string]string{"x-endpoint": "endpoint.id"},
+	})
+
+	outbound := newOutbound()
+	assert.NoError(t, p.ExecuteOutbound(outbound, newContext()))
+	assert.JSONEq(t, `{"id":"o_1","attempt":2}`, outbound.Payload)
+	assert.Equal(t, "ep_1", outbound.Headers["x-endpoint"])
+	assert.Equal(t, "https://example.com", outbound.URL)
+}
+
+func TestExecuteNonJSONPayload(t *testing.T) {
+	p := newPlugin(t, map[string]interface{}{
+		"payload": `raw={{ .event.data }}`,
+	})
+
+	outbound := newOutbound()
+	outbound.Payload = "foo"
+	assert.NoError(t, p.ExecuteOutbound(outbound, nil))
+	assert.Equal(t, "raw=foo", outbound.Payload)
+}
+
+func TestExecuteLimits(t *testing.T) {
+	defer SetLimits(getLimits())
+
+	p := newPlugin(t, map[string]interface{}{
+		"payload": `raw={{ .event.data }}`,
+	})
+
+	SetLimits(plugin.Limits{MaxOutputSize: 1})
+	err := p.ExecuteOutbound(newOutbound(), nil)
+	e, ok := plugin.AsLimitError(err)
+	assert.True(t, ok)
+	assert.Equal(t, plugin.LimitOutputSize, e.Limit)
+
+	SetLimits(plugin.Limits{MaxOutputSize: 1024})
+	assert.NoError(t, p.ExecuteOutbound(newOutbound(), nil))
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		fields map[string]interface{}
+	}{
+		{
+			name:   "invalid template",
+			config: map[string]interface{}{"payload": "{{ .event"},
+			fields: map[string]interface{}{"payload": "invalid expression: template: :1: unclosed action"},
+		},
+		{
+			name:   "invalid jsonata",
+			config: map[string]interface{}{"engine": "jsonata", "payload": "event", "url": "event.("},
+		},
+		{
+			name:   "invalid jmespath",
+			config: map[string]interface{}{"engine": "jmespath", "payload": "event", "headers": map[string]string{"x-foo": "[[["}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, _ := json.Marshal(test.config)
+			p, err := New(b)
+			assert.NoError(t, err)
+			err = p.ValidateConfig()
+			validateErr, ok := err.(*errs.ValidateError)
+			assert.True(t, ok)
+			if test.fields != nil {
+				assert.Equal(t, test.fields, validateErr.Fields)
+			} else {
+				assert.Len(t, validateErr.Fields, 1)
+			}
+		})
+	}
+
+	p, err := New([]byte(`{"engine":"xpath","payload":"foo"}`))
+	assert.NoError(t, err)
+	assert.Error(t, p.ValidateConfig())
+}
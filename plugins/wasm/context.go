@@ -3,10 +3,27 @@ package wasm
 import (
 	"context"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"net/http"
 )
 
 type key struct{}
 
+type inboundKey struct{}
+
+// inboundValue is the state of an inbound execution
+type inboundValue struct {
+	request *http.Request
+	body    []byte
+	// response is set when the module terminates the request
+	response *response
+}
+
+type response struct {
+	Code    int
+	Headers map[string]string
+	Body    []byte
+}
+
 func withContext(ctx context.Context, val *plugin.Outbound) context.Context {
 	return context.WithValue(ctx, key{}, val)
 }
@@ -15,3 +32,12 @@ func fromContext(ctx context.Context) (*plugin.Outbound, bool) {
 	value, ok := ctx.Value(key{}).(*plugin.Outbound)
 	return value, ok
 }
+
+func withInboundContext(ctx context.Context, val *inboundValue) context.Context {
+	return context.WithValue(ctx, inboundKey{}, val)
+}
+
+func fromInboundContext(ctx context.Context) (*inboundValue, bool) {
+	value, ok := ctx.Value(inboundKey{}).(*inboundValue)
+	return value, ok
+}
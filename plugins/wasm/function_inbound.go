@@ -0,0 +1,105 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/webhookx-io/webhookx/utils"
+	"go.uber.org/zap"
+)
+
+func inboundValueFromContext(ctx context.Context) (*inboundValue, bool) {
+	value, ok := fromInboundContext(ctx)
+	if !ok {
+		zap.S().Error("[wasm] invalid context")
+	}
+	return value, ok
+}
+
+func GetInboundRequestMethod(ctx context.Context, m api.Module, valuePtr, valueSizePtr uint32) Status {
+	value, ok := inboundValueFromContext(ctx)
+	if !ok {
+		return StatusInternalFailure
+	}
+	return returnString(ctx, m, value.request.Method, valuePtr, valueSizePtr)
+}
+
+func GetInboundRequestPath(ctx context.Context, m api.Module, valuePtr, valueSizePtr uint32) Status {
+	value, ok := inboundValueFromContext(ctx)
+	if !ok {
+		return StatusInternalFailure
+	}
+	return returnString(ctx, m, value.request.URL.Path, valuePtr, valueSizePtr)
+}
+
+func GetInboundRequestHeaders(ctx context.Context, m api.Module, jsonPtr, jsonSizePtr uint32) Status {
+	value, ok := inboundValueFromContext(ctx)
+	if !ok {
+		return StatusInternalFailure
+	}
+
+	bytes, err := json.Marshal(utils.HeaderMap(value.request.Header))
+	if err != nil {
+		zap.S().Errorf("[wasm] failed to marshal value: %v", err)
+		return StatusInternalFailure
+	}
+
+	return returnString(ctx, m, string(bytes), jsonPtr, jsonSizePtr)
+}
+
+func GetInboundRequestBody(ctx context.Context, m api.Module, valuePtr, valueSizePtr uint32) Status {
+	value, ok := inboundValueFromContext(ctx)
+	if !ok {
+		return StatusInternalFailure
+	}
+	return returnString(ctx, m, string(value.body), valuePtr, valueSizePtr)
+}
+
+func SetInboundRequestBody(ctx context.Context, m api.Module, valuePtr, valueSize uint32) Status {
+	str, ok := readString(m.Memory(), valuePtr, valueSize)
+	if !ok {
+		return StatusInvalidMemoryAccess
+	}
+
+	value, ok := inboundValueFromContext(ctx)
+	if !ok {
+		return StatusInternalFailure
+	}
+	value.body = []byte(str)
+
+	return StatusOk
+}
+
+func Exit(ctx context.Context, m api.Module, statusCode, headersPtr, headersSize, bodyPtr, bodySize uint32) Status {
+	if statusCode < 100 || statusCode > 999 {
+		return StatusBadArgument
+	}
+
+	var headers map[string]string
+	if headersSize > 0 {
+		str, ok := readString(m.Memory(), headersPtr, headersSize)
+		if !ok {
+			return StatusInvalidMemoryAccess
+		}
+		if err := json.Unmarshal([]byte(str), &headers); err != nil {
+			return StatusInvalidJSON
+		}
+	}
+
+	body, ok := readString(m.Memory(), bodyPtr, bodySize)
+	if !ok {
+		return StatusInvalidMemoryAccess
+	}
+
+	value, ok := inboundValueFromContext(ctx)
+	if !ok {
+		return StatusInternalFailure
+	}
+	value.response = &response{
+		Code:    int(statusCode),
+		Headers: headers,
+		Body:    []byte(body),
+	}
+
+	return StatusOk
+}
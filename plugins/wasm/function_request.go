@@ -21,30 +21,7 @@ func GetRequestJSON(ctx context.Context, m api.Module, jsonPtr, jsonSizePtr uint
 		return StatusInternalFailure
 	}
 
-	allocate := m.ExportedFunction("allocate")
-	if allocate == nil {
-		zap.S().Error("[wasm] exported function 'allocate' is not defined")
-		return StatusInternalFailure
-	}
-
-	str := string(bytes)
-	ptr, err := writeString(ctx, m.Memory(), allocate, str)
-	if err != nil {
-		return StatusInvalidMemoryAccess
-	}
-	if ptr == 0 {
-		zap.S().Error("[wasm] exported function 'allocate' returned 0")
-		return StatusInvalidMemoryAccess
-	}
-
-	if !m.Memory().WriteUint32Le(jsonPtr, ptr) {
-		return StatusInvalidMemoryAccess
-	}
-	if !m.Memory().WriteUint32Le(jsonSizePtr, uint32(len(str))) {
-		return StatusInvalidMemoryAccess
-	}
-
-	return StatusOk
+	return returnString(ctx, m, string(bytes), jsonPtr, jsonSizePtr)
 }
 
 func SetRequestJSON(ctx context.Context, m api.Module, jsonPtr, jsonSize uint32) Status {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/tetratelabs/wazero/api"
+	"go.uber.org/zap"
 )
 
 func writeString(ctx context.Context, memory api.Memory, allocate api.Function, str string) (uint32, error) {
@@ -24,3 +25,31 @@ func readString(memory api.Memory, ptr uint32, length uint32) (string, bool) {
 	buf, ok := memory.Read(ptr, length)
 	return string(buf), ok
 }
+
+// returnString writes the string to memory allocated by the module,
+// the memory address and size of the string are stored in valuePtr and valueSizePtr.
+func returnString(ctx context.Context, m api.Module, str string, valuePtr, valueSizePtr uint32) Status {
+	allocate := m.ExportedFunction("allocate")
+	if allocate == nil {
+		zap.S().Error("[wasm] exported function 'allocate' is not defined")
+		return StatusInternalFailure
+	}
+
+	ptr, err := writeString(ctx, m.Memory(), allocate, str)
+	if err != nil {
+		return StatusInvalidMemoryAccess
+	}
+	if ptr == 0 {
+		zap.S().Error("[wasm] exported function 'allocate' returned 0")
+		return StatusInvalidMemoryAccess
+	}
+
+	if !m.Memory().WriteUint32Le(valuePtr, ptr) {
+		return StatusInvalidMemoryAccess
+	}
+	if !m.Memory().WriteUint32Le(valueSizePtr, uint32(len(str))) {
+		return StatusInvalidMemoryAccess
+	}
+
+	return StatusOk
+}
@@ -3,16 +3,13 @@ package wasm
 import (
 	"context"
 	"fmt"
-	"github.com/tetratelabs/wazero"
-	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/utils"
-	"os"
 )
 
 type Config struct {
-	File string            `json:"file" validate:"required"`
-	Envs map[string]string `json:"envs"`
+	File string            `json:"file" validate:"required" description:"The filename of wasm module."`
+	Envs map[string]string `json:"envs" description:"The environment variables that are exposed to the wasm module."`
 }
 
 type WasmPlugin struct {
@@ -36,50 +33,66 @@ func (p *WasmPlugin) ValidateConfig() error {
 }
 
 func (p *WasmPlugin) ExecuteOutbound(outbound *plugin.Outbound, _ *plugin.Context) error {
-	source, err := os.ReadFile(p.Config.File)
-	if err != nil {
+	ctx := withContext(context.Background(), outbound)
+	if err := p.call(ctx, "transform"); err != nil {
 		return err
 	}
+	return getRuntime().limits.CheckOutputSize(len(outbound.Payload))
+}
 
-	ctx := context.Background()
-	runtime := wazero.NewRuntime(ctx)
-	defer func() { _ = runtime.Close(ctx) }()
-
-	_, err = runtime.NewHostModuleBuilder("env").
-		NewFunctionBuilder().WithFunc(Log).Export("log").
-		NewFunctionBuilder().WithFunc(GetRequestJSON).Export("get_request_json").
-		NewFunctionBuilder().WithFunc(SetRequestJSON).Export("set_request_json").
-		Instantiate(ctx)
-	if err != nil {
-		return err
+func (p *WasmPlugin) ExecuteInbound(inbound *plugin.Inbound) (result plugin.InboundResult, err error) {
+	value := &inboundValue{
+		request: inbound.Request,
+		body:    inbound.RawBody,
+	}
+	ctx := withInboundContext(context.Background(), value)
+	if err = p.call(ctx, "handle_inbound"); err != nil {
+		return
 	}
 
-	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+	limits := getRuntime().limits
+	if err = limits.CheckOutputSize(len(value.body)); err != nil {
+		return
+	}
+	if value.response != nil {
+		if err = limits.CheckOutputSize(len(value.response.Body)); err != nil {
+			return
+		}
+	}
 
-	cfg := wazero.NewModuleConfig().WithStartFunctions("_initialize", "_start")
-	for k, v := range p.Config.Envs {
-		cfg = cfg.WithEnv(k, v)
+	if value.response != nil {
+		for k, v := range value.response.Headers {
+			inbound.Response.Header().Set(k, v)
+		}
+		inbound.Response.WriteHeader(value.response.Code)
+		_, _ = inbound.Response.Write(value.response.Body)
+		result.Terminated = true
+		return
 	}
-	mod, err := runtime.InstantiateWithConfig(ctx, source, cfg)
+
+	result.Payload = value.body
+	return
+}
+
+// call calls the exported function of the module, the function must return 1 on success.
+func (p *WasmPlugin) call(ctx context.Context, function string) error {
+	runtime := getRuntime()
+
+	instance, err := runtime.Acquire(ctx, &p.Config, function)
 	if err != nil {
 		return err
 	}
 
-	transform := mod.ExportedFunction("transform")
-	if transform == nil {
-		return fmt.Errorf("exported function 'transform' is not defined in module")
-	}
-
-	ctx = withContext(ctx, outbound)
-	results, err := transform.Call(ctx)
+	results, err := instance.Call(ctx, function)
+	runtime.Release(ctx, instance)
 	if err != nil {
 		return err
 	}
 	if len(results) != 1 {
-		return fmt.Errorf("exported function 'transform' must return exactly one result")
+		return fmt.Errorf("exported function '%s' must return exactly one result", function)
 	}
 	if results[0] != 1 {
-		return fmt.Errorf("transform failed with value %d", results[0])
+		return fmt.Errorf("%s failed with value %d", function, results[0])
 	}
 
 	return nil
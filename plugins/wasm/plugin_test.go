@@ -2,14 +2,19 @@ package wasm
 
 import (
 	"bytes"
+	"context"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func initLogger() *bytes.Buffer {
@@ -49,7 +54,7 @@ var _ = Describe("wasm", Ordered, func() {
 				Headers: make(map[string]string),
 				Payload: "",
 			}
-			err = p.ExecuteOutbound(pluginReq, nil)
+			err = p.(*WasmPlugin).ExecuteOutbound(pluginReq, nil)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), "https://httpbin.org/anything", pluginReq.URL)
 			assert.Equal(GinkgoT(), "POST", pluginReq.Method)
@@ -68,7 +73,7 @@ var _ = Describe("wasm", Ordered, func() {
 			p, err := New(nil)
 			assert.Nil(GinkgoT(), err)
 			p.(*WasmPlugin).Config.File = "notfound.wasm"
-			err = p.ExecuteOutbound(nil, nil)
+			err = p.(*WasmPlugin).ExecuteOutbound(nil, nil)
 			assert.Error(GinkgoT(), err)
 			assert.Equal(GinkgoT(), "open notfound.wasm: no such file or directory", err.Error())
 		})
@@ -77,7 +82,7 @@ var _ = Describe("wasm", Ordered, func() {
 			p, err := New(nil)
 			assert.Nil(GinkgoT(), err)
 			p.(*WasmPlugin).Config.File = "./testdata/no_transform.wasm"
-			err = p.ExecuteOutbound(nil, nil)
+			err = p.(*WasmPlugin).ExecuteOutbound(nil, nil)
 			assert.Error(GinkgoT(), err)
 			assert.Equal(GinkgoT(), "exported function 'transform' is not defined in module", err.Error())
 		})
@@ -86,14 +91,227 @@ var _ = Describe("wasm", Ordered, func() {
 			p, err := New(nil)
 			assert.Nil(GinkgoT(), err)
 			p.(*WasmPlugin).Config.File = "./testdata/transform_return_1.wasm"
-			err = p.ExecuteOutbound(nil, nil)
+			err = p.(*WasmPlugin).ExecuteOutbound(nil, nil)
 			assert.Error(GinkgoT(), err)
 			assert.Equal(GinkgoT(), "transform failed with value 0", err.Error())
 		})
 	})
+
+	Context("inbound", func() {
+		newInbound := func(path string) (*plugin.Inbound, *httptest.ResponseRecorder) {
+			body := `{"key":"value"}`
+			r := httptest.NewRequest("POST", path, strings.NewReader(body))
+			w := httptest.NewRecorder()
+			return &plugin.Inbound{Request: r, Response: w, RawBody: []byte(body)}, w
+		}
+
+		It("should transform body", func() {
+			p, err := New([]byte(`{"file": "./testdata/inbound.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			inbound, _ := newInbound("/webhooks")
+			result, err := p.(*WasmPlugin).ExecuteInbound(inbound)
+			assert.NoError(GinkgoT(), err)
+			assert.False(GinkgoT(), result.Terminated)
+			assert.JSONEq(GinkgoT(),
+				`{"event_type":"inbound.received","data":{"method":"POST","path":"/webhooks","payload":{"key":"value"}}}`,
+				string(result.Payload))
+		})
+
+		It("should terminate with response", func() {
+			p, err := New([]byte(`{"file": "./testdata/inbound.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			inbound, w := newInbound("/reject")
+			result, err := p.(*WasmPlugin).ExecuteInbound(inbound)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), result.Terminated)
+			assert.Equal(GinkgoT(), 400, w.Code)
+			assert.Equal(GinkgoT(), "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(GinkgoT(), `{"message":"rejected"}`, w.Body.String())
+		})
+
+		It("handle_inbound not defined", func() {
+			p, err := New([]byte(`{"file": "./testdata/transform_return_1.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			inbound, _ := newInbound("/webhooks")
+			_, err = p.(*WasmPlugin).ExecuteInbound(inbound)
+			assert.Error(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "exported function 'handle_inbound' is not defined in module", err.Error())
+		})
+	})
+
+	Context("limits", func() {
+		AfterAll(func() {
+			SetLimits(plugin.Limits{})
+		})
+
+		It("should terminate execution exceeding timeout", func() {
+			SetLimits(plugin.Limits{Timeout: time.Millisecond * 100})
+			p, err := New([]byte(`{"file": "./testdata/infinite_loop.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			start := time.Now()
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{}, nil)
+			assert.Less(GinkgoT(), time.Since(start), time.Second)
+			assert.EqualError(GinkgoT(), err, "execution exceeded the time limit of 100ms")
+		})
+
+		It("should terminate start functions exceeding timeout", func() {
+			SetLimits(plugin.Limits{Timeout: time.Millisecond * 100})
+			p, err := New([]byte(`{"file": "./testdata/start_loop.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			start := time.Now()
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{}, nil)
+			assert.Less(GinkgoT(), time.Since(start), time.Second)
+			assert.EqualError(GinkgoT(), err, "execution exceeded the time limit of 100ms")
+		})
+
+		It("should limit memory", func() {
+			SetLimits(plugin.Limits{MaxMemory: 1024 * 1024})
+			p, err := New([]byte(`{"file": "./testdata/memory_grow.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{}, nil)
+			e, ok := plugin.AsLimitError(err)
+			assert.True(GinkgoT(), ok)
+			assert.Equal(GinkgoT(), plugin.LimitMemory, e.Limit)
+		})
+
+		It("should limit output size", func() {
+			SetLimits(plugin.Limits{MaxOutputSize: 1})
+			p, err := New([]byte(`{"file": "./testdata/tinygo/index.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{Headers: make(map[string]string)}, nil)
+			assert.EqualError(GinkgoT(), err, "execution exceeded the output_size limit of 1 bytes")
+		})
+	})
+
+	Context("cache", func() {
+		It("should reuse compiled module", func() {
+			initLogger()
+			runtime := getRuntime()
+			p, err := New([]byte(`{"file": "./testdata/tinygo/index.wasm"}`))
+			assert.Nil(GinkgoT(), err)
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{Headers: make(map[string]string)}, nil)
+			assert.NoError(GinkgoT(), err)
+
+			hash, err := runtime.hash("./testdata/tinygo/index.wasm")
+			assert.NoError(GinkgoT(), err)
+			entry, ok := runtime.modules.Get(hash)
+			assert.True(GinkgoT(), ok)
+
+			for i := 0; i < 3; i++ {
+				err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{Headers: make(map[string]string)}, nil)
+				assert.NoError(GinkgoT(), err)
+			}
+			current, _ := runtime.modules.Get(hash)
+			assert.Same(GinkgoT(), entry, current)
+			assert.Equal(GinkgoT(), 0, entry.refs)
+		})
+
+		It("should run executions in separate instances", func() {
+			ctx := context.Background()
+			runtime := getRuntime()
+			config := &Config{File: "./testdata/tinygo/index.wasm"}
+			i1, err := runtime.Acquire(ctx, config, "transform")
+			assert.NoError(GinkgoT(), err)
+			i2, err := runtime.Acquire(ctx, config, "transform")
+			assert.NoError(GinkgoT(), err)
+			assert.NotSame(GinkgoT(), i1.module, i2.module)
+			assert.Same(GinkgoT(), i1.entry, i2.entry)
+			runtime.Release(ctx, i1)
+			runtime.Release(ctx, i2)
+			assert.True(GinkgoT(), i1.module.IsClosed())
+			assert.True(GinkgoT(), i2.module.IsClosed())
+		})
+
+		It("should keep evicted module until it is released", func() {
+			ctx := context.Background()
+			runtime := getRuntime()
+			config := &Config{File: "./testdata/tinygo/index.wasm"}
+			instance, err := runtime.Acquire(ctx, config, "transform")
+			assert.NoError(GinkgoT(), err)
+
+			runtime.modules.Purge()
+			assert.True(GinkgoT(), instance.entry.evicted)
+			_, err = instance.Call(withContext(ctx, &plugin.Outbound{Headers: make(map[string]string)}), "transform")
+			assert.NoError(GinkgoT(), err)
+			runtime.Release(ctx, instance)
+
+			// the module is compiled again
+			instance2, err := runtime.Acquire(ctx, config, "transform")
+			assert.NoError(GinkgoT(), err)
+			assert.NotSame(GinkgoT(), instance.entry, instance2.entry)
+			runtime.Release(ctx, instance2)
+		})
+
+		It("should recompile when file content changes", func() {
+			filename := filepath.Join(GinkgoT().TempDir(), "plugin.wasm")
+			copyFile := func(src string) {
+				data, err := os.ReadFile(src)
+				assert.NoError(GinkgoT(), err)
+				assert.NoError(GinkgoT(), os.WriteFile(filename, data, 0644))
+			}
+
+			p, err := New(nil)
+			assert.Nil(GinkgoT(), err)
+			p.(*WasmPlugin).Config.File = filename
+
+			copyFile("./testdata/no_transform.wasm")
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{Headers: make(map[string]string)}, nil)
+			assert.Equal(GinkgoT(), "exported function 'transform' is not defined in module", err.Error())
+
+			copyFile("./testdata/tinygo/index.wasm")
+			err = p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{Headers: make(map[string]string)}, nil)
+			assert.NoError(GinkgoT(), err)
+		})
+	})
 })
 
 func Test(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Wasm Suite")
 }
+
+func benchmarkExecuteOutbound(b *testing.B, execute func(p plugin.Plugin) error) {
+	zap.ReplaceGlobals(zap.NewNop())
+	p, err := New([]byte(`{"file": "./testdata/tinygo/index.wasm"}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := execute(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExecuteOutbound(b *testing.B) {
+	benchmarkExecuteOutbound(b, func(p plugin.Plugin) error {
+		return p.(*WasmPlugin).ExecuteOutbound(&plugin.Outbound{Headers: make(map[string]string)}, nil)
+	})
+}
+
+// BenchmarkExecuteOutboundUncached compiles and instantiates the module in a new runtime on every call
+func BenchmarkExecuteOutboundUncached(b *testing.B) {
+	benchmarkExecuteOutbound(b, func(p plugin.Plugin) error {
+		ctx := context.Background()
+		runtime, err := NewRuntime(ctx, plugin.Limits{})
+		if err != nil {
+			return err
+		}
+		defer func() { _ = runtime.Close(ctx) }()
+		instance, err := runtime.Acquire(ctx, &p.(*WasmPlugin).Config, "transform")
+		if err != nil {
+			return err
+		}
+		defer runtime.Release(ctx, instance)
+		_, err = instance.Call(withContext(ctx, &plugin.Outbound{Headers: make(map[string]string)}), "transform")
+		return err
+	})
+}
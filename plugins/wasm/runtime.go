@@ -0,0 +1,339 @@
+package wasm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"golang.org/x/sync/singleflight"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxCompiledModules is the maximum number of cached compiled modules
+const maxCompiledModules = 100
+
+// pageSize is the size of a wasm memory page
+const pageSize = 65536
+
+var (
+	sharedRuntimeMux sync.Mutex
+	sharedRuntime    *Runtime
+	limits           plugin.Limits
+)
+
+// SetLimits sets the resource limits of wasm executions, it should be called before any execution
+// as the process-wide runtime is recreated.
+func SetLimits(l plugin.Limits) {
+	sharedRuntimeMux.Lock()
+	defer sharedRuntimeMux.Unlock()
+	if sharedRuntime != nil {
+		_ = sharedRuntime.Close(context.Background())
+		sharedRuntime = nil
+	}
+	limits = l
+}
+
+// Shutdown closes the process-wide runtime, a new runtime is created on the next execution
+func Shutdown() error {
+	sharedRuntimeMux.Lock()
+	defer sharedRuntimeMux.Unlock()
+	if sharedRuntime == nil {
+		return nil
+	}
+	err := sharedRuntime.Close(context.Background())
+	sharedRuntime = nil
+	return err
+}
+
+// getRuntime returns the process-wide runtime
+func getRuntime() *Runtime {
+	sharedRuntimeMux.Lock()
+	defer sharedRuntimeMux.Unlock()
+	if sharedRuntime == nil {
+		r, err := NewRuntime(context.Background(), limits)
+		if err != nil {
+			panic(err)
+		}
+		sharedRuntime = r
+	}
+	return sharedRuntime
+}
+
+type fileEntry struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+// moduleEntry is a cached compiled module, an evicted module is closed once it is no longer in use
+type moduleEntry struct {
+	compiled wazero.CompiledModule
+
+	mux     sync.Mutex
+	refs    int
+	evicted bool
+}
+
+// acquire reports false when the module has been evicted
+func (e *moduleEntry) acquire() bool {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if e.evicted {
+		return false
+	}
+	e.refs++
+	return true
+}
+
+func (e *moduleEntry) release(ctx context.Context) {
+	e.mux.Lock()
+	e.refs--
+	closable := e.evicted && e.refs == 0
+	e.mux.Unlock()
+	if closable {
+		_ = e.compiled.Close(ctx)
+	}
+}
+
+func (e *moduleEntry) evict(ctx context.Context) {
+	e.mux.Lock()
+	e.evicted = true
+	closable := e.refs == 0
+	e.mux.Unlock()
+	if closable {
+		_ = e.compiled.Close(ctx)
+	}
+}
+
+// Runtime is a shared wazero runtime that caches compiled modules by their content hash.
+// Every execution runs in a new instance of the compiled module, so that no state of guests
+// is shared between executions.
+type Runtime struct {
+	runtime wazero.Runtime
+	limits  plugin.Limits
+	group   singleflight.Group
+
+	mux   sync.Mutex
+	files map[string]fileEntry
+
+	modules *lru.Cache[string, *moduleEntry]
+}
+
+func NewRuntime(ctx context.Context, limits plugin.Limits) (*Runtime, error) {
+	cfg := wazero.NewRuntimeConfig().
+		WithCompilationCache(wazero.NewCompilationCache()).
+		WithCloseOnContextDone(true)
+	if limits.MaxMemory > 0 {
+		pages := (limits.MaxMemory + pageSize - 1) / pageSize
+		cfg = cfg.WithMemoryLimitPages(uint32(min(pages, 65536)))
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, cfg)
+
+	_, err := runtime.NewHostModuleBuilder("env").
+		NewFunctionBuilder().WithFunc(Log).Export("log").
+		NewFunctionBuilder().WithFunc(GetRequestJSON).Export("get_request_json").
+		NewFunctionBuilder().WithFunc(SetRequestJSON).Export("set_request_json").
+		NewFunctionBuilder().WithFunc(GetInboundRequestMethod).Export("get_inbound_request_method").
+		NewFunctionBuilder().WithFunc(GetInboundRequestPath).Export("get_inbound_request_path").
+		NewFunctionBuilder().WithFunc(GetInboundRequestHeaders).Export("get_inbound_request_headers").
+		NewFunctionBuilder().WithFunc(GetInboundRequestBody).Export("get_inbound_request_body").
+		NewFunctionBuilder().WithFunc(SetInboundRequestBody).Export("set_inbound_request_body").
+		NewFunctionBuilder().WithFunc(Exit).Export("exit").
+		Instantiate(ctx)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+
+	modules, _ := lru.NewWithEvict(maxCompiledModules, func(_ string, e *moduleEntry) {
+		e.evict(context.Background())
+	})
+
+	return &Runtime{
+		runtime: runtime,
+		limits:  limits,
+		files:   make(map[string]fileEntry),
+		modules: modules,
+	}, nil
+}
+
+// Close closes the runtime and all modules
+func (r *Runtime) Close(ctx context.Context) error {
+	r.modules.Purge()
+	return r.runtime.Close(ctx)
+}
+
+// hash returns the content hash of the file, the file is only read again when its size or modification time changes.
+func (r *Runtime) hash(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	r.mux.Lock()
+	entry, ok := r.files[filename]
+	r.mux.Unlock()
+	if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		return entry.hash, nil
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	entry = fileEntry{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		hash:    hex.EncodeToString(h.Sum(nil)),
+	}
+
+	r.mux.Lock()
+	r.files[filename] = entry
+	r.mux.Unlock()
+
+	return entry.hash, nil
+}
+
+// compile returns the compiled module of the file, the module must be released after use.
+func (r *Runtime) compile(ctx context.Context, filename string) (*moduleEntry, error) {
+	hash, err := r.hash(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if e, ok := r.modules.Get(hash); ok && e.acquire() {
+			return e, nil
+		}
+
+		v, err, _ := r.group.Do(hash, func() (interface{}, error) {
+			if e, ok := r.modules.Get(hash); ok {
+				return e, nil
+			}
+			source, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+			compiled, err := r.runtime.CompileModule(ctx, source)
+			if err != nil {
+				return nil, err
+			}
+			e := &moduleEntry{compiled: compiled}
+			r.modules.Add(hash, e)
+			return e, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		// the module may be evicted before it is acquired, it is compiled again in that case
+		if e := v.(*moduleEntry); e.acquire() {
+			return e, nil
+		}
+	}
+}
+
+// Acquire returns a new instance of the module for the plugin configuration that exports the function,
+// the instance must be closed by calling Release.
+func (r *Runtime) Acquire(ctx context.Context, config *Config, function string) (*Instance, error) {
+	entry, err := r.compile(ctx, config.File)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := entry.compiled.ExportedFunctions()[function]; !ok {
+		entry.release(ctx)
+		return nil, fmt.Errorf("exported function '%s' is not defined in module", function)
+	}
+
+	// an empty name allows instantiating the module multiple times in a runtime
+	cfg := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize", "_start")
+	for k, v := range config.Envs {
+		cfg = cfg.WithEnv(k, v)
+	}
+	// the start functions run during the instantiation, they are limited like the function calls
+	instantiateCtx, cancel := withTimeout(ctx, r.limits)
+	defer cancel()
+	module, err := r.runtime.InstantiateModule(instantiateCtx, entry.compiled, cfg)
+	if err != nil {
+		entry.release(ctx)
+		if isDeadlineExceeded(err) {
+			return nil, &plugin.LimitError{Limit: plugin.LimitTimeout, Value: int64(r.limits.Timeout)}
+		}
+		return nil, err
+	}
+	return &Instance{module: module, entry: entry, limits: r.limits}, nil
+}
+
+// withTimeout returns a context that is canceled after the timeout of the limits
+func withTimeout(ctx context.Context, limits plugin.Limits) (context.Context, context.CancelFunc) {
+	if limits.Timeout > 0 {
+		return context.WithTimeout(ctx, limits.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// isDeadlineExceeded reports whether the execution was terminated because the context deadline exceeded
+func isDeadlineExceeded(err error) bool {
+	var exitErr *sys.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == sys.ExitCodeDeadlineExceeded
+}
+
+// Release closes the instance
+func (r *Runtime) Release(ctx context.Context, instance *Instance) {
+	_ = instance.module.Close(ctx)
+	instance.entry.release(ctx)
+}
+
+// Instance is an instantiated module
+type Instance struct {
+	module api.Module
+	entry  *moduleEntry
+	limits plugin.Limits
+}
+
+func (i *Instance) Function(name string) api.Function {
+	return i.module.ExportedFunction(name)
+}
+
+// Call calls the exported function with the limits of the runtime
+func (i *Instance) Call(ctx context.Context, name string) ([]uint64, error) {
+	limits := i.limits
+	ctx, cancel := withTimeout(ctx, limits)
+	defer cancel()
+
+	results, err := i.Function(name).Call(ctx)
+	if err != nil {
+		if isDeadlineExceeded(err) {
+			return nil, &plugin.LimitError{Limit: plugin.LimitTimeout, Value: int64(limits.Timeout)}
+		}
+		// the memory cannot grow any further
+		if limits.MaxMemory > 0 {
+			if memory := i.module.Memory(); memory != nil && int64(memory.Size())+pageSize > limits.MaxMemory {
+				return nil, fmt.Errorf("%w: %v", &plugin.LimitError{Limit: plugin.LimitMemory, Value: limits.MaxMemory}, err)
+			}
+		}
+		return nil, err
+	}
+	return results, nil
+}
@@ -36,6 +36,26 @@ declare function set_request_json(value_data: usize, value_size: usize): i32;
 @external("env", "log")
 declare function log(log_level: i32, str_value: usize, str_size: i32): i32;
 
+// @ts-ignore: decorator
+@external("env", "get_inbound_request_method")
+declare function get_inbound_request_method(return_value_data: usize, return_value_size: usize): i32;
+
+// @ts-ignore: decorator
+@external("env", "get_inbound_request_path")
+declare function get_inbound_request_path(return_value_data: usize, return_value_size: usize): i32;
+
+// @ts-ignore: decorator
+@external("env", "get_inbound_request_body")
+declare function get_inbound_request_body(return_value_data: usize, return_value_size: usize): i32;
+
+// @ts-ignore: decorator
+@external("env", "set_inbound_request_body")
+declare function set_inbound_request_body(value_data: usize, value_size: usize): i32;
+
+// @ts-ignore: decorator
+@external("env", "exit")
+declare function exit(status_code: i32, headers_data: usize, headers_size: usize, body_data: usize, body_size: usize): i32;
+
 
 function log_string(level: LogLevel, str: string): void {
     let encoded = String.UTF8.encode(str)
@@ -66,3 +86,44 @@ export function transform(): i32 {
 
     return 1
 }
+
+// calls the host function that returns a string, null indicates failure
+function get_string(fn: (return_value_data: usize, return_value_size: usize) => i32): string | null {
+    let ptr = heap.alloc(4);
+    let size = heap.alloc(4);
+    if (fn(ptr, size) != OK) {
+        return null
+    }
+    return String.UTF8.decodeUnsafe(load<usize>(ptr), load<usize>(size))
+}
+
+export function handle_inbound(): i32 {
+    let path = get_string(get_inbound_request_path)
+    if (path === null) {
+        return 0
+    }
+
+    if (path == "/reject") {
+        let headers = String.UTF8.encode('{"Content-Type":"application/json"}')
+        let body = String.UTF8.encode('{"message":"rejected"}')
+        let status = exit(400, changetype<usize>(headers), headers.byteLength, changetype<usize>(body), body.byteLength)
+        if (status != OK) {
+            return 0
+        }
+        return 1
+    }
+
+    let method = get_string(get_inbound_request_method)
+    let body = get_string(get_inbound_request_body)
+    if (method === null || body === null) {
+        return 0
+    }
+
+    let event = String.UTF8.encode('{"event_type":"inbound.received","data":{"method":"' + method! + '","path":"' + path! + '","payload":' + body! + '}}')
+    let status = set_inbound_request_body(changetype<usize>(event), event.byteLength)
+    if (status != OK) {
+        return 0
+    }
+
+    return 1
+}
@@ -0,0 +1,240 @@
+(module
+ (type $0 (func (param i32 i32) (result i32)))
+ (type $1 (func (param i32 i32 i32 i32 i32) (result i32)))
+ (type $2 (func (result i32)))
+ (type $3 (func (param i32) (result i32)))
+ (type $4 (func (param i32 i32 i32 i32) (result i32)))
+ (type $5 (func (param i32 i32 i32) (result i32)))
+ (import "env" "get_inbound_request_method" (func $get_method (type $0)))
+ (import "env" "get_inbound_request_path" (func $get_path (type $0)))
+ (import "env" "get_inbound_request_body" (func $get_body (type $0)))
+ (import "env" "set_inbound_request_body" (func $set_body (type $0)))
+ (import "env" "exit" (func $exit (type $1)))
+ (memory $0 1)
+ (global $heap (mut i32) (i32.const 4096))
+ (export "memory" (memory $0))
+ (export "allocate" (func $allocate))
+ (export "handle_inbound" (func $handle_inbound))
+ ;; 0-7 holds the pointer and size of values returned by host functions
+ (data (i32.const 1024) "/reject")
+ (data (i32.const 1040) "{\"Content-Type\":\"application/json\"}")
+ (data (i32.const 1088) "{\"message\":\"rejected\"}")
+ (data (i32.const 1120) "{\"event_type\":\"inbound.received\",\"data\":{\"method\":\"")
+ (data (i32.const 1184) "\",\"path\":\"")
+ (data (i32.const 1200) "\",\"payload\":")
+ (data (i32.const 1216) "}}")
+
+ ;; allocate is a bump allocator, memory is never freed as instances are discarded after each execution
+ (func $allocate (type $3) (param $size i32) (result i32)
+  (local $ptr i32)
+  global.get $heap
+  local.set $ptr
+  global.get $heap
+  local.get $size
+  i32.add
+  global.set $heap
+  block $done
+   loop $grow
+    global.get $heap
+    memory.size
+    i32.const 16
+    i32.shl
+    i32.le_u
+    br_if $done
+    i32.const 1
+    memory.grow
+    i32.const -1
+    i32.eq
+    if
+     i32.const 0
+     return
+    end
+    br $grow
+   end
+  end
+  local.get $ptr
+ )
+
+ ;; equal reports whether the strings are equal
+ (func $equal (type $4) (param $a i32) (param $a_len i32) (param $b i32) (param $b_len i32) (result i32)
+  (local $i i32)
+  local.get $a_len
+  local.get $b_len
+  i32.ne
+  if
+   i32.const 0
+   return
+  end
+  i32.const 0
+  local.set $i
+  block $done
+   loop $compare
+    local.get $i
+    local.get $a_len
+    i32.ge_u
+    br_if $done
+    local.get $a
+    local.get $i
+    i32.add
+    i32.load8_u
+    local.get $b
+    local.get $i
+    i32.add
+    i32.load8_u
+    i32.ne
+    if
+     i32.const 0
+     return
+    end
+    local.get $i
+    i32.const 1
+    i32.add
+    local.set $i
+    br $compare
+   end
+  end
+  i32.const 1
+ )
+
+ ;; append copies the string to dst and returns the end of the copy
+ (func $append (type $5) (param $dst i32) (param $src i32) (param $len i32) (result i32)
+  local.get $dst
+  local.get $src
+  local.get $len
+  memory.copy
+  local.get $dst
+  local.get $len
+  i32.add
+ )
+
+ ;; handle_inbound rejects requests to /reject with 400, and wraps other requests in an event
+ ;; {"event_type":"inbound.received","data":{"method":"<method>","path":"<path>","payload":<body>}}
+ (func $handle_inbound (type $2) (result i32)
+  (local $path i32)
+  (local $path_len i32)
+  (local $method i32)
+  (local $method_len i32)
+  (local $body i32)
+  (local $body_len i32)
+  (local $out i32)
+  (local $pos i32)
+  i32.const 0
+  i32.const 4
+  call $get_path
+  if
+   i32.const 0
+   return
+  end
+  i32.const 0
+  i32.load
+  local.set $path
+  i32.const 4
+  i32.load
+  local.set $path_len
+
+  local.get $path
+  local.get $path_len
+  i32.const 1024
+  i32.const 7
+  call $equal
+  if
+   i32.const 400
+   i32.const 1040
+   i32.const 35
+   i32.const 1088
+   i32.const 22
+   call $exit
+   if
+    i32.const 0
+    return
+   end
+   i32.const 1
+   return
+  end
+
+  i32.const 0
+  i32.const 4
+  call $get_method
+  if
+   i32.const 0
+   return
+  end
+  i32.const 0
+  i32.load
+  local.set $method
+  i32.const 4
+  i32.load
+  local.set $method_len
+
+  i32.const 0
+  i32.const 4
+  call $get_body
+  if
+   i32.const 0
+   return
+  end
+  i32.const 0
+  i32.load
+  local.set $body
+  i32.const 4
+  i32.load
+  local.set $body_len
+
+  i32.const 75
+  local.get $method_len
+  i32.add
+  local.get $path_len
+  i32.add
+  local.get $body_len
+  i32.add
+  call $allocate
+  local.tee $out
+  local.set $pos
+  local.get $pos
+  i32.const 1120
+  i32.const 51
+  call $append
+  local.set $pos
+  local.get $pos
+  local.get $method
+  local.get $method_len
+  call $append
+  local.set $pos
+  local.get $pos
+  i32.const 1184
+  i32.const 10
+  call $append
+  local.set $pos
+  local.get $pos
+  local.get $path
+  local.get $path_len
+  call $append
+  local.set $pos
+  local.get $pos
+  i32.const 1200
+  i32.const 12
+  call $append
+  local.set $pos
+  local.get $pos
+  local.get $body
+  local.get $body_len
+  call $append
+  local.set $pos
+  local.get $pos
+  i32.const 1216
+  i32.const 2
+  call $append
+  local.set $pos
+
+  local.get $out
+  local.get $pos
+  local.get $out
+  i32.sub
+  call $set_body
+  if
+   i32.const 0
+   return
+  end
+  i32.const 1
+ )
+)
@@ -0,0 +1,13 @@
+(module
+ (type $0 (func (result i32)))
+ (memory $0 1)
+ (export "transform" (func $index/transform))
+ (export "handle_inbound" (func $index/transform))
+ (export "memory" (memory $0))
+ (func $index/transform (type $0) (result i32)
+  loop $continue
+   br $continue
+  end
+  unreachable
+ )
+)
@@ -0,0 +1,19 @@
+(module
+ (type $0 (func (result i32)))
+ (memory $0 1)
+ (export "transform" (func $index/transform))
+ (export "memory" (memory $0))
+ (func $index/transform (result i32)
+  loop $continue
+   i32.const 1
+   memory.grow
+   i32.const -1
+   i32.eq
+   if
+    unreachable
+   end
+   br $continue
+  end
+  unreachable
+ )
+)
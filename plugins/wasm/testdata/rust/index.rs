@@ -13,6 +13,11 @@ extern "C" {
     fn get_request_json(return_value_data: *mut usize, return_value_size: *mut usize) -> i32;
     fn set_request_json(value_data: *const u8, value_size: usize) -> i32;
     fn log(log_level: i32, str_value: *const u8, str_size: i32) -> i32;
+    fn get_inbound_request_method(return_value_data: *mut usize, return_value_size: *mut usize) -> i32;
+    fn get_inbound_request_path(return_value_data: *mut usize, return_value_size: *mut usize) -> i32;
+    fn get_inbound_request_body(return_value_data: *mut usize, return_value_size: *mut usize) -> i32;
+    fn set_inbound_request_body(value_data: *const u8, value_size: usize) -> i32;
+    fn exit(status_code: i32, headers_data: *const u8, headers_size: usize, body_data: *const u8, body_size: usize) -> i32;
 }
 
 const OK: i32 = 0;
@@ -71,3 +76,54 @@ pub extern "C" fn transform() -> i32 {
 
     1
 }
+
+/// Calls the host function that returns a string.
+fn get_string(f: unsafe extern "C" fn(*mut usize, *mut usize) -> i32) -> Option<&'static str> {
+    let mut ptr: usize = 0;
+    let mut size: usize = 0;
+    if unsafe { f(&mut ptr, &mut size) } != OK {
+        return None;
+    }
+    unsafe {
+        let slice = slice::from_raw_parts(ptr as *const u8, size);
+        Some(str::from_utf8_unchecked(slice))
+    }
+}
+
+#[no_mangle]
+pub extern "C" fn handle_inbound() -> i32 {
+    let path = match get_string(get_inbound_request_path) {
+        Some(path) => path,
+        None => return 0,
+    };
+
+    if path == "/reject" {
+        let headers = r#"{"Content-Type":"application/json"}"#;
+        let body = r#"{"message":"rejected"}"#;
+        let status = unsafe { exit(400, headers.as_ptr(), headers.len(), body.as_ptr(), body.len()) };
+        if status != OK {
+            return 0;
+        }
+        return 1;
+    }
+
+    let method = match get_string(get_inbound_request_method) {
+        Some(method) => method,
+        None => return 0,
+    };
+    let body = match get_string(get_inbound_request_body) {
+        Some(body) => body,
+        None => return 0,
+    };
+
+    let event = format!(
+        r#"{{"event_type":"inbound.received","data":{{"method":"{}","path":"{}","payload":{}}}}}"#,
+        method, path, body
+    );
+    let status = unsafe { set_inbound_request_body(event.as_ptr(), event.len()) };
+    if status != OK {
+        return 0;
+    }
+
+    1
+}
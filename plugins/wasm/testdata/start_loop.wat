@@ -0,0 +1,16 @@
+(module
+ (type $0 (func (result i32)))
+ (type $1 (func))
+ (memory $0 1)
+ (export "transform" (func $index/transform))
+ (export "_start" (func $index/start))
+ (export "memory" (memory $0))
+ (func $index/start (type $1)
+  loop $continue
+   br $continue
+  end
+ )
+ (func $index/transform (type $0) (result i32)
+  i32.const 1
+ )
+)
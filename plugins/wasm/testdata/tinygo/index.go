@@ -29,6 +29,21 @@ func setRequestJson(valueData uintptr, valueSize uint32) int32
 //go:wasmimport env log
 func log(logLevel int32, strValue uint32, strSize uint32) int32
 
+//go:wasmimport env get_inbound_request_method
+func getInboundRequestMethod(returnValueData uintptr, returnValueSize uintptr) int32
+
+//go:wasmimport env get_inbound_request_path
+func getInboundRequestPath(returnValueData uintptr, returnValueSize uintptr) int32
+
+//go:wasmimport env get_inbound_request_body
+func getInboundRequestBody(returnValueData uintptr, returnValueSize uintptr) int32
+
+//go:wasmimport env set_inbound_request_body
+func setInboundRequestBody(valueData uintptr, valueSize uint32) int32
+
+//go:wasmimport env exit
+func exit(statusCode int32, headersData uintptr, headersSize uint32, bodyData uintptr, bodySize uint32) int32
+
 func stringToPtr(s string) (uint32, uint32) {
 	ptr := unsafe.Pointer(unsafe.StringData(s))
 	return uint32(uintptr(ptr)), uint32(len(s))
@@ -76,3 +91,47 @@ func transform() int32 {
 
 	return 1
 }
+
+// getString calls the host function that returns a string
+func getString(fn func(uintptr, uintptr) int32) (string, bool) {
+	var ptr uint32
+	var size uint32
+	if fn(uintptr(unsafe.Pointer(&ptr)), uintptr(unsafe.Pointer(&size))) != OK {
+		return "", false
+	}
+	return ptrToString(ptr, size), true
+}
+
+//go:wasmexport handle_inbound
+func handleInbound() int32 {
+	path, ok := getString(getInboundRequestPath)
+	if !ok {
+		return 0
+	}
+
+	if path == "/reject" {
+		headersPtr, headersSize := stringToPtr(`{"Content-Type":"application/json"}`)
+		bodyPtr, bodySize := stringToPtr(`{"message":"rejected"}`)
+		if exit(400, uintptr(headersPtr), headersSize, uintptr(bodyPtr), bodySize) != OK {
+			return 0
+		}
+		return 1
+	}
+
+	method, ok := getString(getInboundRequestMethod)
+	if !ok {
+		return 0
+	}
+	body, ok := getString(getInboundRequestBody)
+	if !ok {
+		return 0
+	}
+
+	event := `{"event_type":"inbound.received","data":{"method":"` + method + `","path":"` + path + `","payload":` + body + `}}`
+	eventPtr, eventSize := stringToPtr(event)
+	if setInboundRequestBody(uintptr(eventPtr), eventSize) != OK {
+		return 0
+	}
+
+	return 1
+}
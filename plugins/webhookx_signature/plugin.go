@@ -11,7 +11,7 @@ import (
 )
 
 type Config struct {
-	SigningSecret string `json:"signing_secret" validate:"required"`
+	SigningSecret string `json:"signing_secret" validate:"required" sensitive:"true" description:"The secret used to sign requests, a random secret is generated if it is not provided."`
 }
 
 type SignaturePlugin struct {
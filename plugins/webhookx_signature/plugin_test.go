@@ -19,7 +19,7 @@ func TestExecute(t *testing.T) {
 		Headers: make(map[string]string),
 		Payload: "foo",
 	}
-	p.ExecuteOutbound(pluginReq, nil)
+	p.(*SignaturePlugin).ExecuteOutbound(pluginReq, nil)
 
 	assert.Equal(t, "https://example.com", pluginReq.URL)
 	assert.Equal(t, "POST", pluginReq.Method)
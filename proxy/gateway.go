@@ -40,6 +40,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"slices"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -238,24 +239,42 @@ func (gw *Gateway) handle(w http.ResponseWriter, r *http.Request) bool {
 		}
 	}
 
-	plugins, err := listSourcePlugins(ctx, gw.db, source.ID)
+	plugins, err := listSourcePlugins(ctx, gw.db, source)
 	if err != nil {
 		response.JSON(w, 500, types.ErrorResponse{Message: "internal error"})
 		return false
 	}
 
 	for _, p := range plugins {
-		executor, err := p.Plugin()
+		inbound := &plugin.Inbound{
+			Request:  r,
+			Response: w,
+			RawBody:  body,
+		}
+		matched, err := p.MatchInbound(inbound)
 		if err != nil {
+			if gw.handleLimitError(w, p.Name, err) {
+				return false
+			}
+			gw.log.Errorf("failed to match plugin: %v", err)
 			response.JSON(w, 500, types.ErrorResponse{Message: "internal error"})
 			return false
 		}
-		result, err := executor.ExecuteInbound(&plugin.Inbound{
-			Request:  r,
-			Response: w,
-			RawBody:  body,
-		})
+		if !matched {
+			continue
+		}
+
+		executor, err := p.InboundExecutor(ctx)
 		if err != nil {
+			gw.log.Errorf("failed to initialize plugin: %v", err)
+			response.JSON(w, 500, types.ErrorResponse{Message: "internal error"})
+			return false
+		}
+		result, err := executor.ExecuteInbound(inbound)
+		if err != nil {
+			if gw.handleLimitError(w, p.Name, err) {
+				return false
+			}
 			gw.log.Errorf("failed to execute plugin: %v", err)
 			response.JSON(w, 500, types.ErrorResponse{Message: "internal error"})
 			return false
@@ -275,6 +294,7 @@ func (gw *Gateway) handle(w http.ResponseWriter, r *http.Request) bool {
 	event.ID = utils.KSUID()
 	event.IngestedAt = types.Time{Time: time.Now()}
 	event.WorkspaceId = source.WorkspaceId
+	event.SourceId = &source.ID
 	if err := event.Validate(); err != nil {
 		response.JSON(w, 400, types.ErrorResponse{
 			Message: "Request Validation",
@@ -290,7 +310,11 @@ func (gw *Gateway) handle(w http.ResponseWriter, r *http.Request) bool {
 		return false
 	}
 	if gw.metrics.Enabled {
-		gw.metrics.EventTotalCounter.Add(1)
+		gw.metrics.EventTotalCounter.With(
+			"workspace", source.WorkspaceId,
+			"source", source.ID,
+			"event_type", event.EventType,
+		).Add(1)
 	}
 
 	headers := Headers{}
@@ -312,7 +336,24 @@ func (gw *Gateway) handle(w http.ResponseWriter, r *http.Request) bool {
 	return true
 }
 
+// handleLimitError responds 503 if err is a LimitError, it reports whether the error is handled
+func (gw *Gateway) handleLimitError(w http.ResponseWriter, name string, err error) bool {
+	e, ok := plugin.AsLimitError(err)
+	if !ok {
+		return false
+	}
+	if gw.metrics.Enabled {
+		gw.metrics.PluginLimitExceededCounter.With("plugin", name, "limit", e.Limit).Add(1)
+	}
+	gw.log.Warnf("plugin %s exceeded the resource limit: %v", name, err)
+	response.JSON(w, http.StatusServiceUnavailable, types.ErrorResponse{
+		Message: fmt.Sprintf("plugin '%s' %s", name, e.Error()),
+	})
+	return true
+}
+
 func (gw *Gateway) ingestEvent(ctx context.Context, async bool, event *entities.Event) error {
+	event.TraceParent = tracing.TraceParent(ctx)
 	if async {
 		if gw.queue == nil {
 			return ErrQueueDisabled
@@ -327,6 +368,7 @@ func (gw *Gateway) ingestEvent(ctx context.Context, async bool, event *entities.
 			Value:       bytes,
 			Time:        time.Now(),
 			WorkspaceID: event.WorkspaceId,
+			TraceParent: event.TraceParent,
 		}
 		return gw.queue.Enqueue(ctx, &msg)
 	}
@@ -367,14 +409,23 @@ func (gw *Gateway) Start() {
 		store.Set("router:version", utils.UUID())
 	})
 	gw.bus.Subscribe("plugin.crud", func(data interface{}) {
-		plugin := entities.Plugin{}
-		if err := json.Unmarshal(data.(*eventbus.CrudData).Data, &plugin); err != nil {
-			zap.S().Errorf("failed to unmarshal event data: %s", err)
-			return
+		crud := data.(*eventbus.CrudData)
+		var keys []string
+		for _, raw := range []json.RawMessage{crud.Data, crud.Previous} {
+			if len(raw) == 0 {
+				continue
+			}
+			plugin := entities.Plugin{}
+			if err := json.Unmarshal(raw, &plugin); err != nil {
+				zap.S().Errorf("failed to unmarshal event data: %s", err)
+				return
+			}
+			// the previous scope is invalidated as well when a plugin is moved
+			if key := pluginsCacheKey(&plugin, crud.WID); key != "" && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
 		}
-
-		if plugin.SourceId != nil {
-			cacheKey := constants.SourcePluginsKey.Build(*plugin.SourceId)
+		for _, cacheKey := range keys {
 			err := mcache.Invalidate(context.TODO(), cacheKey)
 			if err != nil {
 				zap.S().Errorf("failed to invalidate cache: key=%s %v", cacheKey, err)
@@ -427,6 +478,7 @@ func (gw *Gateway) HandleMessages(ctx context.Context, messages []*queue.Message
 			continue
 		}
 		event.WorkspaceId = message.WorkspaceID
+		event.TraceParent = message.TraceParent
 		events = append(events, &event)
 	}
 
@@ -463,18 +515,51 @@ func exit(w http.ResponseWriter, status int, body string, headers Headers) {
 	_, _ = w.Write([]byte(body))
 }
 
-func listSourcePlugins(ctx context.Context, db *db.DB, sourceId string) ([]*entities.Plugin, error) {
-	// refactor me
-	cacheKey := constants.SourcePluginsKey.Build(sourceId)
+// listSourcePlugins returns the plugins to execute for the source, which are resolved from the global,
+// workspace and source plugins.
+func listSourcePlugins(ctx context.Context, db *db.DB, source *entities.Source) ([]*entities.Plugin, error) {
+	global, err := loadPlugins(ctx, constants.GlobalPluginsKey.Build(""), func(ctx context.Context, _ string) ([]*entities.Plugin, error) {
+		return db.Plugins.ListGlobalPlugin(ctx)
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := loadPlugins(ctx, constants.WorkspacePluginsKey.Build(source.WorkspaceId), db.Plugins.ListWorkspacePlugin, source.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	plugins, err := loadPlugins(ctx, constants.SourcePluginsKey.Build(source.ID), db.Plugins.ListSourcePlugin, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ResolvePlugins(plugin.TypeInbound, global, workspace, plugins), nil
+}
+
+func loadPlugins(ctx context.Context, cacheKey string, fn func(ctx context.Context, id string) ([]*entities.Plugin, error), id string) ([]*entities.Plugin, error) {
 	plugins, err := mcache.Load(ctx, cacheKey, nil, func(ctx context.Context, id string) (*[]*entities.Plugin, error) {
-		plugins, err := db.Plugins.ListSourcePlugin(ctx, id)
+		plugins, err := fn(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 		return &plugins, nil
-	}, sourceId)
+	}, id)
 	if err != nil {
 		return nil, err
 	}
-	return *plugins, err
+	return *plugins, nil
+}
+
+// pluginsCacheKey returns the cache key of the plugin list that contains the plugin,
+// it returns empty for endpoint plugins which are not executed by the gateway
+func pluginsCacheKey(plugin *entities.Plugin, wid string) string {
+	switch {
+	case plugin.SourceId != nil:
+		return constants.SourcePluginsKey.Build(*plugin.SourceId)
+	case plugin.EndpointId != nil:
+		return ""
+	case plugin.Global:
+		return constants.GlobalPluginsKey.Build("")
+	default:
+		return constants.WorkspacePluginsKey.Build(wid)
+	}
 }
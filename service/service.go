@@ -5,6 +5,7 @@ import (
 	"github.com/webhookx-io/webhookx/constants"
 	"github.com/webhookx-io/webhookx/db"
 	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
 	"github.com/webhookx-io/webhookx/pkg/taskqueue"
 	"go.uber.org/zap"
 	"time"
@@ -14,11 +15,13 @@ type Service struct {
 	log   *zap.SugaredLogger
 	db    *db.DB
 	queue taskqueue.TaskQueue
+	tail  *livetail.Tail
 }
 
 type Options struct {
 	DB        *db.DB
 	TaskQueue taskqueue.TaskQueue
+	Tail      *livetail.Tail
 }
 
 func NewService(opts Options) *Service {
@@ -26,6 +29,7 @@ func NewService(opts Options) *Service {
 		log:   zap.S(),
 		db:    opts.DB,
 		queue: opts.TaskQueue,
+		tail:  opts.Tail,
 	}
 }
 
@@ -37,19 +41,23 @@ func (s *Service) ScheduleAttempts(ctx context.Context, attempts []*entities.Att
 	maxScheduleAt := time.Now().Add(constants.TaskQueuePreScheduleTimeWindow)
 	tasks := make([]*taskqueue.TaskMessage, 0)
 	ids := make([]string, 0)
+	queued := make([]*entities.Attempt, 0)
 	for _, attempt := range attempts {
 		if attempt.ScheduledAt.Before(maxScheduleAt) {
 			tasks = append(tasks, &taskqueue.TaskMessage{
 				ID:          attempt.ID,
 				ScheduledAt: attempt.ScheduledAt.Time,
 				Data: &taskqueue.MessageData{
-					EventID:    attempt.EventId,
-					EndpointId: attempt.EndpointId,
-					Attempt:    attempt.AttemptNumber,
-					Event:      string(attempt.Event.Data),
+					EventID:     attempt.EventId,
+					EndpointId:  attempt.EndpointId,
+					Attempt:     attempt.AttemptNumber,
+					Event:       string(attempt.Event.Data),
+					EventType:   attempt.Event.EventType,
+					TraceParent: attempt.Event.TraceParent,
 				},
 			})
 			ids = append(ids, attempt.ID)
+			queued = append(queued, attempt)
 		}
 	}
 
@@ -65,7 +73,16 @@ func (s *Service) ScheduleAttempts(ctx context.Context, attempts []*entities.Att
 	err = s.db.Attempts.UpdateStatusToQueued(ctx, ids)
 	if err != nil {
 		s.log.Warnf("failed to update attempts status: %v", err)
+		return
+	}
+
+	activities := make([]*livetail.Activity, 0, len(queued))
+	for _, attempt := range queued {
+		activity := livetail.NewAttemptActivity(attempt)
+		activity.Attempt.Status = entities.AttemptStatusQueued
+		activities = append(activities, activity)
 	}
+	s.tail.Publish(activities...)
 }
 
 func (s *Service) GetTasks(ctx context.Context, opts *taskqueue.GetOptions) ([]*taskqueue.TaskMessage, error) {
@@ -22,6 +22,7 @@ type API struct {
 	tracer         *tracing.Tracer
 	accessLogger   accesslog.AccessLogger
 	indicators     []*health.Indicator
+	metricsHandler http.Handler
 }
 
 func (api *API) Status(w http.ResponseWriter, r *http.Request) {
@@ -111,6 +112,10 @@ func (api *API) Handler() http.Handler {
 	r.HandleFunc("/", api.Status).Methods("GET")
 	r.HandleFunc("/health", api.Health).Methods("GET")
 
+	if api.metricsHandler != nil {
+		r.Handle("/metrics", api.metricsHandler).Methods("GET")
+	}
+
 	if api.debugEndpoints {
 		r.HandleFunc("/debug/pprof/profile", pprof.Profile).Methods("GET")
 		r.HandleFunc("/debug/pprof/symbol", pprof.Symbol).Methods("GET")
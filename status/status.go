@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/webhookx-io/webhookx/config"
 	"github.com/webhookx-io/webhookx/pkg/accesslog"
+	"github.com/webhookx-io/webhookx/pkg/metrics"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"github.com/webhookx-io/webhookx/status/health"
 	"go.uber.org/zap"
@@ -24,6 +25,7 @@ type Options struct {
 	AccessLog  accesslog.AccessLogger
 	Config     *config.Config
 	Indicators []*health.Indicator
+	Metrics    *metrics.Metrics
 }
 
 func NewStatus(cfg config.StatusConfig, tracer *tracing.Tracer, opts Options) *Status {
@@ -33,6 +35,9 @@ func NewStatus(cfg config.StatusConfig, tracer *tracing.Tracer, opts Options) *S
 		accessLogger:   opts.AccessLog,
 		indicators:     opts.Indicators,
 	}
+	if opts.Metrics != nil {
+		api.metricsHandler = opts.Metrics.Handler()
+	}
 	s := &http.Server{
 		Handler:      api.Handler(),
 		Addr:         cfg.Listen,
@@ -60,6 +65,10 @@ func (a *Status) Start() {
 
 	a.log.Infow(fmt.Sprintf(`listening on address "%s"`, a.cfg.Listen))
 
+	if a.api.metricsHandler != nil {
+		a.log.Infow("serving prometheus metrics at /metrics")
+	}
+
 	if a.cfg.DebugEndpoints {
 		a.log.Infow("serving debug endpoints at /debug", "pprof", "/debug/pprof/")
 	}
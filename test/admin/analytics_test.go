@@ -0,0 +1,191 @@
+package admin
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/admin/api"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/analytics"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("/analytics", Ordered, func() {
+
+	var adminClient *resty.Client
+	var app *app.Application
+	var db *db.DB
+	var endpoints []*entities.Endpoint
+	var source *entities.Source
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, nil)
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN": "0.0.0.0:8080",
+		}))
+		adminClient = helper.AdminClient()
+
+		ws := utils.Must(db.Workspaces.GetDefault(context.TODO()))
+		for i := 0; i < 2; i++ {
+			endpoint := factory.EndpointWS(ws.ID)
+			assert.NoError(GinkgoT(), db.Endpoints.Insert(context.TODO(), &endpoint))
+			endpoints = append(endpoints, &endpoint)
+		}
+		s := factory.SourceWS(ws.ID)
+		assert.NoError(GinkgoT(), db.Sources.Insert(context.TODO(), &s))
+		source = &s
+
+		now := time.Now()
+		for i := 0; i < 10; i++ {
+			event := &entities.Event{
+				ID:        utils.KSUID(),
+				EventType: "foo.bar",
+				Data:      []byte("{}"),
+				SourceId:  &source.ID,
+			}
+			if i%2 == 1 {
+				event.EventType = "foo.baz"
+			}
+			event.WorkspaceId = ws.ID
+			assert.NoError(GinkgoT(), db.Events.Insert(context.TODO(), event))
+
+			attempt := entities.Attempt{
+				ID:            utils.KSUID(),
+				EventId:       event.ID,
+				EndpointId:    endpoints[0].ID,
+				Status:        entities.AttemptStatusSuccess,
+				AttemptNumber: 1,
+				ScheduledAt:   types.NewTime(now),
+				AttemptedAt:   utils.Pointer(types.NewTime(now)),
+				Response:      &entities.AttemptResponse{Status: 200, Latency: int64(10 * (i + 1))},
+			}
+			if i == 9 {
+				attempt.EndpointId = endpoints[1].ID
+				attempt.Status = entities.AttemptStatusFailure
+				attempt.ErrorCode = utils.Pointer(entities.AttemptErrorCodeTimeout)
+				attempt.Response = nil
+			}
+			attempt.WorkspaceId = ws.ID
+			assert.NoError(GinkgoT(), db.Attempts.Insert(context.TODO(), &attempt))
+		}
+
+		// rollups are idempotent
+		for i := 0; i < 2; i++ {
+			assert.NoError(GinkgoT(), db.TX(context.TODO(), func(ctx context.Context) error {
+				ok, err := db.AttemptStats.Rollup(ctx, now.Add(-time.Hour))
+				assert.True(GinkgoT(), ok)
+				return err
+			}))
+		}
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	Context("GET /analytics/attempts", func() {
+		It("returns aggregates", func() {
+			resp, err := adminClient.R().
+				SetResult(api.AttemptAnalytics{}).
+				Get("/workspaces/default/analytics/attempts")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			result := resp.Result().(*api.AttemptAnalytics)
+			assert.Equal(GinkgoT(), analytics.IntervalHour, result.Interval)
+			assert.Len(GinkgoT(), result.Data, 1)
+			bucket := result.Data[0]
+			assert.EqualValues(GinkgoT(), 10, bucket.Total)
+			assert.EqualValues(GinkgoT(), 9, bucket.Statuses["SUCCESSFUL"])
+			assert.EqualValues(GinkgoT(), 1, bucket.Statuses["FAILED"])
+			assert.EqualValues(GinkgoT(), 1, bucket.ErrorCodes["TIMEOUT"])
+			assert.InDelta(GinkgoT(), 0.9, bucket.SuccessRate, 0.0001)
+			assert.InDelta(GinkgoT(), 50, bucket.Latency.Avg, 0.0001)
+			assert.True(GinkgoT(), bucket.Latency.P50 > 25 && bucket.Latency.P50 <= 50)
+			assert.True(GinkgoT(), bucket.Latency.P99 > 50 && bucket.Latency.P99 <= 100)
+		})
+
+		It("groups by endpoint", func() {
+			resp, err := adminClient.R().
+				SetResult(api.AttemptAnalytics{}).
+				Get("/workspaces/default/analytics/attempts?group_by=endpoint&interval=day")
+			assert.NoError(GinkgoT(), err)
+			result := resp.Result().(*api.AttemptAnalytics)
+			assert.Len(GinkgoT(), result.Data, 2)
+			totals := map[string]int64{}
+			for _, bucket := range result.Data {
+				totals[bucket.Key] = bucket.Total
+			}
+			assert.EqualValues(GinkgoT(), 9, totals[endpoints[0].ID])
+			assert.EqualValues(GinkgoT(), 1, totals[endpoints[1].ID])
+		})
+
+		It("groups by event type", func() {
+			resp, err := adminClient.R().
+				SetResult(api.AttemptAnalytics{}).
+				Get("/workspaces/default/analytics/attempts?group_by=event_type&source_id=" + source.ID)
+			assert.NoError(GinkgoT(), err)
+			result := resp.Result().(*api.AttemptAnalytics)
+			assert.Len(GinkgoT(), result.Data, 2)
+			assert.Equal(GinkgoT(), "foo.bar", result.Data[0].Key)
+			assert.EqualValues(GinkgoT(), 5, result.Data[0].Total)
+			assert.Equal(GinkgoT(), "foo.baz", result.Data[1].Key)
+			assert.EqualValues(GinkgoT(), 5, result.Data[1].Total)
+		})
+
+		It("filters by endpoint", func() {
+			resp, err := adminClient.R().
+				SetResult(api.AttemptAnalytics{}).
+				Get("/workspaces/default/analytics/attempts?endpoint_id=" + endpoints[1].ID)
+			assert.NoError(GinkgoT(), err)
+			result := resp.Result().(*api.AttemptAnalytics)
+			assert.Len(GinkgoT(), result.Data, 1)
+			assert.EqualValues(GinkgoT(), 1, result.Data[0].Total)
+			assert.EqualValues(GinkgoT(), 0, result.Data[0].SuccessRate)
+		})
+
+		It("returns empty data out of range", func() {
+			end := time.Now().Add(-48 * time.Hour).UnixMilli()
+			resp, err := adminClient.R().
+				SetResult(api.AttemptAnalytics{}).
+				Get("/workspaces/default/analytics/attempts?end=" + strconv.FormatInt(end, 10))
+			assert.NoError(GinkgoT(), err)
+			result := resp.Result().(*api.AttemptAnalytics)
+			assert.Len(GinkgoT(), result.Data, 0)
+		})
+
+		Context("errors", func() {
+			It("invalid interval", func() {
+				resp, err := adminClient.R().Get("/workspaces/default/analytics/attempts?interval=week")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"invalid interval: week"}`, string(resp.Body()))
+			})
+			It("invalid group_by", func() {
+				resp, err := adminClient.R().Get("/workspaces/default/analytics/attempts?group_by=foo")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"invalid group_by: foo"}`, string(resp.Body()))
+			})
+			It("invalid time range", func() {
+				resp, err := adminClient.R().Get("/workspaces/default/analytics/attempts?start=2000&end=1000")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"start must be before end"}`, string(resp.Body()))
+
+				resp, err = adminClient.R().Get("/workspaces/default/analytics/attempts?start=foo")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(), `{"message":"invalid start: foo"}`, string(resp.Body()))
+			})
+		})
+	})
+})
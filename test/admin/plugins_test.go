@@ -122,11 +122,13 @@ var _ = Describe("/plugins", Ordered, func() {
 				assert.Equal(GinkgoT(), true, result.Enabled)
 				data := make(map[string]string)
 				json.Unmarshal(result.Config, &data)
-				assert.Equal(GinkgoT(), 32, len(data["signing_secret"]))
+				assert.Equal(GinkgoT(), entities.RedactedSecret, data["signing_secret"])
 
 				e, err := db.Plugins.Get(context.TODO(), result.ID)
 				assert.Nil(GinkgoT(), err)
 				assert.NotNil(GinkgoT(), e)
+				json.Unmarshal(e.Config, &data)
+				assert.Equal(GinkgoT(), 32, len(data["signing_secret"]))
 			})
 
 			It("creates a plugin with plugin config", func() {
@@ -153,11 +155,13 @@ var _ = Describe("/plugins", Ordered, func() {
 				assert.Equal(GinkgoT(), true, result.Enabled)
 				data := make(map[string]string)
 				json.Unmarshal(result.Config, &data)
-				assert.Equal(GinkgoT(), "abcde", data["signing_secret"])
+				assert.Equal(GinkgoT(), entities.RedactedSecret, data["signing_secret"])
 
 				e, err := db.Plugins.Get(context.TODO(), result.ID)
 				assert.Nil(GinkgoT(), err)
 				assert.NotNil(GinkgoT(), e)
+				json.Unmarshal(e.Config, &data)
+				assert.Equal(GinkgoT(), "abcde", data["signing_secret"])
 			})
 
 		})
@@ -185,6 +189,171 @@ var _ = Describe("/plugins", Ordered, func() {
 			})
 		})
 
+		Context("workspace and global plugins", func() {
+			It("creates a workspace plugin", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":   "webhookx-signature",
+						"config": map[string]string{"signing_secret": "abcde"},
+					}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+				result := resp.Result().(*entities.Plugin)
+				assert.Nil(GinkgoT(), result.EndpointId)
+				assert.Nil(GinkgoT(), result.SourceId)
+				assert.False(GinkgoT(), result.Global)
+
+				plugins, err := db.Plugins.ListWorkspacePlugin(context.TODO(), ws.ID)
+				assert.Nil(GinkgoT(), err)
+				assert.Len(GinkgoT(), plugins, 1)
+				assert.Equal(GinkgoT(), result.ID, plugins[0].ID)
+
+				resp, err = adminClient.R().
+					SetBody(map[string]interface{}{"name": "webhookx-signature"}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"name":"plugin 'webhookx-signature' is already applied"}}}`,
+					string(resp.Body()))
+			})
+
+			It("creates a global plugin", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{"name": "transform", "global": true, "config": map[string]string{"payload": "{{ .event.data }}"}}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+				plugins, err := db.Plugins.ListGlobalPlugin(context.TODO())
+				assert.Nil(GinkgoT(), err)
+				assert.Len(GinkgoT(), plugins, 1)
+				assert.Equal(GinkgoT(), resp.Result().(*entities.Plugin).ID, plugins[0].ID)
+				assert.NoError(GinkgoT(), db.Truncate("plugins"))
+			})
+
+			It("requires a side for plugins applied to both sides", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":   "function",
+						"config": map[string]string{"function": "function handle() {}"},
+					}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"side":"side is required as plugin 'function' can be applied to inbound and outbound requests"}}}`,
+					string(resp.Body()))
+
+				for _, side := range []plugin.Type{plugin.TypeInbound, plugin.TypeOutbound} {
+					resp, err = adminClient.R().
+						SetBody(map[string]interface{}{
+							"name":   "function",
+							"side":   side,
+							"config": map[string]string{"function": "function handle() {}"},
+						}).
+						SetResult(entities.Plugin{}).
+						Post("/workspaces/default/plugins")
+					assert.Nil(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 201, resp.StatusCode())
+					assert.Equal(GinkgoT(), side, *resp.Result().(*entities.Plugin).Side)
+				}
+
+				plugins, err := db.Plugins.ListWorkspacePlugin(context.TODO(), ws.ID)
+				assert.Nil(GinkgoT(), err)
+				assert.Len(GinkgoT(), plugins, 2)
+				assert.NoError(GinkgoT(), db.Truncate("plugins"))
+			})
+
+			It("returns HTTP 400 when creating a global plugin in non-default workspace", func() {
+				workspace := factory.Workspace("plugins-test")
+				assert.NoError(GinkgoT(), db.Workspaces.Insert(context.TODO(), workspace))
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{"name": "webhookx-signature", "global": true}).
+					Post("/workspaces/plugins-test/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"global":"global plugins can only be created in the default workspace"}}}`,
+					string(resp.Body()))
+			})
+		})
+
+		Context("priority and condition", func() {
+			It("creates a plugin with priority and condition", func() {
+				endpoint := factory.EndpointP()
+				assert.Nil(GinkgoT(), db.Endpoints.Insert(context.TODO(), endpoint))
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":        "webhookx-signature",
+						"endpoint_id": endpoint.ID,
+						"priority":    2000,
+						"condition": map[string]interface{}{
+							"event_types": []string{"order.*"},
+							"expression":  `endpoint.metadata.env = "prod"`,
+						},
+					}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+				e, err := db.Plugins.Get(context.TODO(), resp.Result().(*entities.Plugin).ID)
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 2000, *e.Priority)
+				assert.Equal(GinkgoT(), []string{"order.*"}, []string(e.Condition.EventTypes))
+				assert.Equal(GinkgoT(), `endpoint.metadata.env = "prod"`, e.Condition.Expression)
+			})
+
+			It("returns HTTP 400 when the priority conflicts", func() {
+				endpoint := factory.EndpointP()
+				assert.Nil(GinkgoT(), db.Endpoints.Insert(context.TODO(), endpoint))
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":        "webhookx-signature",
+						"endpoint_id": endpoint.ID,
+					}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+				resp, err = adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":        "function",
+						"endpoint_id": endpoint.ID,
+						"priority":    100,
+						"config":      map[string]string{"function": "function handle() {}"},
+					}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"priority":"priority 100 conflicts with plugin 'webhookx-signature'"}}}`,
+					string(resp.Body()))
+			})
+
+			It("returns HTTP 400 for invalid condition expression", func() {
+				endpoint := factory.EndpointP()
+				assert.Nil(GinkgoT(), db.Endpoints.Insert(context.TODO(), endpoint))
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":        "webhookx-signature",
+						"endpoint_id": endpoint.ID,
+						"condition":   map[string]interface{}{"event_types": []string{"order.["}},
+					}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"condition":{"event_types":"invalid pattern 'order.['"}}}}`,
+					string(resp.Body()))
+			})
+		})
+
 		Context("errors", func() {
 			It("return HTTP 400", func() {
 				resp, err := adminClient.R().
@@ -198,6 +367,21 @@ var _ = Describe("/plugins", Ordered, func() {
 					string(resp.Body()))
 			})
 
+			It("returns HTTP 400 for secrets in the form of encrypted values", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"name":   "webhookx-signature",
+						"config": map[string]interface{}{"signing_secret": "$enc$v1$foo"},
+					}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"config":{"signing_secret":"value must not start with '$enc$v1$'"}}}}`,
+					string(resp.Body()))
+			})
+
 			It("returns HTTP 400 for unkown plugin name", func() {
 				resp, err := adminClient.R().
 					SetBody(map[string]interface{}{"name": "unknown"}).
@@ -210,27 +394,39 @@ var _ = Describe("/plugins", Ordered, func() {
 					string(resp.Body()))
 			})
 
-			It("returns HTTP 400 when missing endpoint_id for outbound type plugin", func() {
+			It("returns HTTP 400 when applying outbound type plugin to a source", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{"name": "outbound", "source_id": "test"}).
+					SetResult(entities.Plugin{}).
+					Post("/workspaces/default/plugins")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 400, resp.StatusCode())
+				assert.Equal(GinkgoT(),
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"source_id":"plugin 'outbound' cannot be applied to sources"}}}`,
+					string(resp.Body()))
+			})
+
+			It("returns HTTP 400 when applying inbound type plugin to an endpoint", func() {
 				resp, err := adminClient.R().
-					SetBody(map[string]interface{}{"name": "outbound"}).
+					SetBody(map[string]interface{}{"name": "inbound", "endpoint_id": "test"}).
 					SetResult(entities.Plugin{}).
 					Post("/workspaces/default/plugins")
 				assert.Nil(GinkgoT(), err)
 				assert.Equal(GinkgoT(), 400, resp.StatusCode())
 				assert.Equal(GinkgoT(),
-					`{"message":"Request Validation","error":{"message":"request validation","fields":{"endpoint_id":"endpoint_id is required for plugin 'outbound'"}}}`,
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"endpoint_id":"plugin 'inbound' cannot be applied to endpoints"}}}`,
 					string(resp.Body()))
 			})
 
-			It("returns HTTP 400 when missing source_id for inbound type plugin", func() {
+			It("returns HTTP 400 when applying global plugin to an endpoint", func() {
 				resp, err := adminClient.R().
-					SetBody(map[string]interface{}{"name": "inbound"}).
+					SetBody(map[string]interface{}{"name": "webhookx-signature", "endpoint_id": "test", "global": true}).
 					SetResult(entities.Plugin{}).
 					Post("/workspaces/default/plugins")
 				assert.Nil(GinkgoT(), err)
 				assert.Equal(GinkgoT(), 400, resp.StatusCode())
 				assert.Equal(GinkgoT(),
-					`{"message":"Request Validation","error":{"message":"request validation","fields":{"source_id":"source_id is required for plugin 'inbound'"}}}`,
+					`{"message":"Request Validation","error":{"message":"request validation","fields":{"global":"global plugins cannot be applied to an endpoint or a source"}}}`,
 					string(resp.Body()))
 			})
 
@@ -269,6 +465,49 @@ var _ = Describe("/plugins", Ordered, func() {
 		})
 	})
 
+	Context("/schemas", func() {
+		It("lists plugin schemas", func() {
+			resp, err := adminClient.R().
+				SetResult(api.Pagination[*api.PluginSchema]{}).
+				Get("/plugins/schemas")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			result := resp.Result().(*api.Pagination[*api.PluginSchema])
+			assert.EqualValues(GinkgoT(), len(plugin.GetRegistrations()), result.Total)
+			names := make([]string, 0, len(result.Data))
+			for _, schema := range result.Data {
+				names = append(names, schema.Name)
+			}
+			assert.Contains(GinkgoT(), names, "webhookx-signature")
+			assert.Contains(GinkgoT(), names, "function")
+		})
+
+		It("retrieves a plugin schema", func() {
+			resp, err := adminClient.R().
+				SetResult(api.PluginSchema{}).
+				Get("/plugins/schemas/webhookx-signature")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			result := resp.Result().(*api.PluginSchema)
+			assert.Equal(GinkgoT(), "webhookx-signature", result.Name)
+			assert.Equal(GinkgoT(), plugin.TypeOutbound, result.Type)
+			assert.NotEmpty(GinkgoT(), result.Description)
+			assert.Equal(GinkgoT(), "object", result.Schema.Type.Slice()[0])
+			property := result.Schema.Properties["signing_secret"]
+			assert.NotNil(GinkgoT(), property)
+			assert.True(GinkgoT(), property.Value.WriteOnly)
+		})
+
+		Context("errors", func() {
+			It("return HTTP 404", func() {
+				resp, err := adminClient.R().Get("/plugins/schemas/notfound")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 404, resp.StatusCode())
+				assert.Equal(GinkgoT(), "{\"message\":\"Not found\"}", string(resp.Body()))
+			})
+		})
+	})
+
 	Context("/{id}", func() {
 		Context("GET", func() {
 			var entity *entities.Plugin
@@ -300,9 +539,17 @@ var _ = Describe("/plugins", Ordered, func() {
 				assert.Equal(GinkgoT(), entity.Name, result.Name)
 				assert.Equal(GinkgoT(), entity.Enabled, result.Enabled)
 				assert.Equal(GinkgoT(), `{"signing_secret": "abcde"}`, string(entity.Config))
+				assert.Equal(GinkgoT(), `{"signing_secret":"******"}`, string(result.Config))
 			})
 
 			Context("errors", func() {
+				It("return HTTP 403 when revealing secrets is not allowed", func() {
+					resp, err := adminClient.R().Get("/workspaces/default/plugins/" + entity.ID + "?reveal_secrets=true")
+					assert.NoError(GinkgoT(), err)
+					assert.Equal(GinkgoT(), 403, resp.StatusCode())
+					assert.Equal(GinkgoT(), `{"message":"revealing secrets is not allowed"}`, string(resp.Body()))
+				})
+
 				It("return HTTP 404", func() {
 					resp, err := adminClient.R().Get("/workspaces/default/plugins/notfound")
 					assert.NoError(GinkgoT(), err)
@@ -359,6 +606,26 @@ var _ = Describe("/plugins", Ordered, func() {
 				assert.Equal(GinkgoT(), false, result.Enabled)
 			})
 
+			It("keeps secrets when updating with redacted secrets", func() {
+				resp, err := adminClient.R().
+					SetBody(map[string]interface{}{
+						"config": map[string]interface{}{
+							"signing_secret": entities.RedactedSecret,
+						},
+					}).
+					SetResult(entities.Plugin{}).
+					Put("/workspaces/default/plugins/" + plugin.ID)
+
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+				e, err := db.Plugins.Get(context.TODO(), plugin.ID)
+				assert.Nil(GinkgoT(), err)
+				data := make(map[string]string)
+				json.Unmarshal(e.Config, &data)
+				assert.Equal(GinkgoT(), "foo", data["signing_secret"])
+			})
+
 			Context("errors", func() {
 				It("should return HTTP 400 for unkown plugin name", func() {
 					resp, err := adminClient.R().
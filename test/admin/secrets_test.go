@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/encryption"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/utils"
+	"strings"
+)
+
+var _ = Describe("secrets", Ordered, func() {
+
+	var adminClient *resty.Client
+	var app *app.Application
+	var db *db.DB
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, nil)
+		adminClient = helper.AdminClient()
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN":         "0.0.0.0:8080",
+			"WEBHOOKX_ADMIN_REVEAL_SECRETS": "true",
+			"WEBHOOKX_ENCRYPTION_KEYS":      "k1:MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE=",
+		}))
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	It("encrypts secrets at rest", func() {
+		resp, err := adminClient.R().
+			SetBody(map[string]interface{}{
+				"request": map[string]interface{}{
+					"url": "https://example.com",
+					"headers": map[string]string{
+						"Authorization": "Bearer secret",
+						"X-Foo":         "bar",
+					},
+					"auth": map[string]interface{}{
+						"type": "bearer",
+						"bearer": map[string]string{
+							"token": "token",
+						},
+					},
+				},
+			}).
+			SetResult(entities.Endpoint{}).
+			Post("/workspaces/default/endpoints")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 201, resp.StatusCode())
+		result := resp.Result().(*entities.Endpoint)
+		assert.Equal(GinkgoT(), entities.RedactedSecret, result.Request.Headers["Authorization"])
+		assert.Equal(GinkgoT(), "bar", result.Request.Headers["X-Foo"])
+		assert.Equal(GinkgoT(), entities.RedactedSecret, result.Request.Auth.Bearer.Token)
+
+		var raw string
+		assert.NoError(GinkgoT(), db.DB.Get(&raw, "SELECT request FROM endpoints WHERE id = $1", result.ID))
+		assert.Equal(GinkgoT(), 2, strings.Count(raw, encryption.Prefix+"k1$"))
+		assert.NotContains(GinkgoT(), raw, "secret")
+
+		e, err := db.Endpoints.Get(context.TODO(), result.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), "Bearer secret", e.Request.Headers["Authorization"])
+		assert.Equal(GinkgoT(), "token", e.Request.Auth.Bearer.Token)
+
+		resp, err = adminClient.R().
+			SetResult(entities.Endpoint{}).
+			Get("/workspaces/default/endpoints/" + result.ID + "?reveal_secrets=true")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+		result = resp.Result().(*entities.Endpoint)
+		assert.Equal(GinkgoT(), "Bearer secret", result.Request.Headers["Authorization"])
+		assert.Equal(GinkgoT(), "token", result.Request.Auth.Bearer.Token)
+	})
+
+	It("redacts secrets in dumped configuration", func() {
+		resp, err := adminClient.R().
+			SetBody(map[string]interface{}{
+				"name": "dump",
+				"request": map[string]interface{}{
+					"url": "https://example.com",
+					"auth": map[string]interface{}{
+						"type": "bearer",
+						"bearer": map[string]string{
+							"token": "dump-token",
+						},
+					},
+				},
+			}).
+			SetResult(entities.Endpoint{}).
+			Post("/workspaces/default/endpoints")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 201, resp.StatusCode())
+		endpoint := resp.Result().(*entities.Endpoint)
+
+		resp, err = adminClient.R().
+			SetBody(map[string]interface{}{
+				"name":        "webhookx-signature",
+				"endpoint_id": endpoint.ID,
+				"config": map[string]string{
+					"signing_secret": "dump-signing-secret",
+				},
+			}).
+			Post("/workspaces/default/plugins")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 201, resp.StatusCode())
+
+		resp, err = adminClient.R().Post("/workspaces/default/config/dump")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+		assert.Contains(GinkgoT(), string(resp.Body()), entities.RedactedSecret)
+		assert.NotContains(GinkgoT(), string(resp.Body()), "dump-token")
+		assert.NotContains(GinkgoT(), string(resp.Body()), "dump-signing-secret")
+
+		resp, err = adminClient.R().Post("/workspaces/default/config/dump?reveal_secrets=true")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+		assert.Contains(GinkgoT(), string(resp.Body()), "dump-token")
+		assert.Contains(GinkgoT(), string(resp.Body()), "dump-signing-secret")
+	})
+})
@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+type streamMessage struct {
+	Event string
+	Data  string
+}
+
+// stream connects to the stream endpoint and sends received messages to the channel until ctx is done
+func stream(ctx context.Context, url string) (<-chan streamMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	c := make(chan streamMessage, 100)
+	go func() {
+		defer resp.Body.Close()
+		defer close(c)
+		scanner := bufio.NewScanner(resp.Body)
+		var msg streamMessage
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				msg.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				msg.Data = strings.TrimPrefix(line, "data: ")
+			case line == "" && msg.Event != "":
+				c <- msg
+				msg = streamMessage{}
+			}
+		}
+	}()
+	return c, nil
+}
+
+func receive(c <-chan streamMessage) *streamMessage {
+	select {
+	case msg := <-c:
+		return &msg
+	case <-time.After(time.Second * 10):
+		return nil
+	}
+}
+
+var _ = Describe("/events/stream", Ordered, func() {
+
+	var adminClient *resty.Client
+	var proxyClient *resty.Client
+	var app *app.Application
+
+	endpoint := factory.EndpointP()
+	entitiesConfig := helper.EntitiesConfig{
+		Endpoints: []*entities.Endpoint{endpoint},
+		Sources:   []*entities.Source{factory.SourceP()},
+	}
+
+	BeforeAll(func() {
+		helper.InitDB(true, &entitiesConfig)
+		adminClient = helper.AdminClient()
+		proxyClient = helper.ProxyClient()
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+			"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+			"WEBHOOKX_WORKER_ENABLED": "true",
+		}))
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	ingest := func(eventType string) {
+		assert.Eventually(GinkgoT(), func() bool {
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "` + eventType + `", "data": {"key": "value"}}`).
+				Post("/")
+			return err == nil && resp.StatusCode() == 200
+		}, time.Second*5, time.Second)
+	}
+
+	It("streams events and attempts", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c, err := stream(ctx, "http://localhost:8080/workspaces/default/events/stream")
+		assert.NoError(GinkgoT(), err)
+
+		ingest("foo.bar")
+
+		msg := receive(c)
+		assert.NotNil(GinkgoT(), msg)
+		assert.Equal(GinkgoT(), "event", msg.Event)
+		event := livetail.EventActivity{}
+		assert.NoError(GinkgoT(), json.Unmarshal([]byte(msg.Data), &event))
+		assert.Equal(GinkgoT(), "foo.bar", event.EventType)
+
+		statuses := make([]string, 0)
+		for len(statuses) < 3 {
+			msg = receive(c)
+			if !assert.NotNil(GinkgoT(), msg) {
+				break
+			}
+			assert.Equal(GinkgoT(), "attempt", msg.Event)
+			attempt := livetail.AttemptActivity{}
+			assert.NoError(GinkgoT(), json.Unmarshal([]byte(msg.Data), &attempt))
+			assert.Equal(GinkgoT(), event.ID, attempt.EventId)
+			assert.Equal(GinkgoT(), endpoint.ID, attempt.EndpointId)
+			assert.Equal(GinkgoT(), "foo.bar", attempt.EventType)
+			statuses = append(statuses, attempt.Status)
+		}
+		assert.Equal(GinkgoT(), []string{"INIT", "QUEUED", "SUCCESSFUL"}, statuses)
+	})
+
+	It("streams with filters", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c, err := stream(ctx, "http://localhost:8080/workspaces/default/events/stream?event_type=foo.baz&status=SUCCESSFUL")
+		assert.NoError(GinkgoT(), err)
+
+		ingest("foo.bar")
+		ingest("foo.baz")
+
+		msg := receive(c)
+		assert.NotNil(GinkgoT(), msg)
+		assert.Equal(GinkgoT(), "attempt", msg.Event)
+		attempt := livetail.AttemptActivity{}
+		assert.NoError(GinkgoT(), json.Unmarshal([]byte(msg.Data), &attempt))
+		assert.Equal(GinkgoT(), "foo.baz", attempt.EventType)
+		assert.Equal(GinkgoT(), "SUCCESSFUL", attempt.Status)
+	})
+
+	It("returns 400 for invalid status", func() {
+		resp, err := adminClient.R().Get("/workspaces/default/events/stream?status=foo")
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 400, resp.StatusCode())
+		assert.Equal(GinkgoT(), `{"message":"invalid status: foo"}`, string(resp.Body()))
+	})
+})
@@ -123,6 +123,60 @@ var _ = Describe("clustering", Ordered, func() {
 		})
 
 	})
+
+	Context("redis eventbus", func() {
+		var adminClient *resty.Client
+
+		var cp *app.Application
+		var worker *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			adminClient = helper.AdminClient()
+
+			cp = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_STATUS_LISTEN":    "off",
+				"WEBHOOKX_ROLE":             "cp",
+				"WEBHOOKX_EVENTBUS_BACKEND": "redis",
+				"WEBHOOKX_LOG_FILE":         "webhookx-cp.log",
+			}))
+			worker = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_STATUS_LISTEN":    "off",
+				"WEBHOOKX_ROLE":             "dp_worker",
+				"WEBHOOKX_EVENTBUS_BACKEND": "redis",
+				"WEBHOOKX_LOG_FILE":         "webhookx-worker1.log",
+			}))
+		})
+
+		AfterAll(func() {
+			cp.Stop()
+			worker.Stop()
+		})
+
+		It("events created from admin API must be delivered", func() {
+			for i := 0; i < 10; i++ {
+				resp, err := adminClient.R().
+					SetBody(`{"event_type": "foo.bar", "data": {"key":"value"}}`).
+					Post("/workspaces/default/events")
+				assert.Nil(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 201, resp.StatusCode())
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				q := query.AttemptQuery{
+					Status: utils.Pointer(entities.AttemptStatusSuccess),
+				}
+				n, err := db.Attempts.Count(context.TODO(), q.WhereMap())
+				assert.NoError(GinkgoT(), err)
+				return n == 10
+			}, time.Second*3, time.Second)
+		})
+	})
 })
 
 func TestClustering(t *testing.T) {
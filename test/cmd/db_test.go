@@ -19,11 +19,19 @@ var statusOutputInit = `1 init (⏳ pending)
 9 timestamp (⏳ pending)
 10 ratelimit (⏳ pending)
 11 event_unique_id (⏳ pending)
+12 endpoint_max_concurrency (⏳ pending)
+13 analytics (⏳ pending)
+14 endpoint_health (⏳ pending)
+15 endpoint_type (⏳ pending)
+16 plugin_priority (⏳ pending)
+17 plugin_scope (⏳ pending)
+18 attempts_attempted_at (⏳ pending)
+19 plugin_side (⏳ pending)
 Summary:
   Current version: 0
   Dirty: false
   Executed: 0
-  Pending: 11
+  Pending: 19
 `
 
 var statusOutputDone = `1 init (✅ executed)
@@ -37,10 +45,18 @@ var statusOutputDone = `1 init (✅ executed)
 9 timestamp (✅ executed)
 10 ratelimit (✅ executed)
 11 event_unique_id (✅ executed)
+12 endpoint_max_concurrency (✅ executed)
+13 analytics (✅ executed)
+14 endpoint_health (✅ executed)
+15 endpoint_type (✅ executed)
+16 plugin_priority (✅ executed)
+17 plugin_scope (✅ executed)
+18 attempts_attempted_at (✅ executed)
+19 plugin_side (✅ executed)
 Summary:
-  Current version: 11
+  Current version: 19
   Dirty: false
-  Executed: 11
+  Executed: 19
   Pending: 0
 `
 
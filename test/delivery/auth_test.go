@@ -0,0 +1,94 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("auth", Ordered, func() {
+	Context("bearer", func() {
+		var proxyClient *resty.Client
+		var adminClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		endpoint := factory.EndpointP()
+		// the response does not echo the request, so the token can only come from the request headers
+		endpoint.Request.URL = "http://localhost:9999/status/200"
+		endpoint.Request.Auth = &entities.AuthConfig{
+			Type:   entities.AuthTypeBearer,
+			Bearer: &entities.BearerAuth{Token: "bearer-token"},
+		}
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{endpoint},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+			adminClient = helper.AdminClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("does not store the credentials in attempt details", func() {
+			err := waitForServer("0.0.0.0:8081", time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			eventId := resp.Header().Get(constants.HeaderEventId)
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				q := query.AttemptQuery{}
+				q.EventId = &eventId
+				list, err := db.Attempts.List(context.TODO(), &q)
+				if err != nil || len(list) == 0 {
+					return false
+				}
+				attempt = list[0]
+				return attempt.Status == entities.AttemptStatusSuccess
+			}, time.Second*5, time.Second)
+
+			assert.Eventually(GinkgoT(), func() bool {
+				detail, err := db.AttemptDetails.Get(context.TODO(), attempt.ID)
+				return err == nil && detail != nil
+			}, time.Second*5, time.Second)
+
+			resp, err = adminClient.R().
+				SetResult(entities.Attempt{}).
+				Get("/workspaces/default/attempts/" + attempt.ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			result := resp.Result().(*entities.Attempt)
+			assert.Equal(GinkgoT(), entities.RedactedSecret, result.Request.Headers["Authorization"])
+			assert.NotContains(GinkgoT(), string(resp.Body()), "bearer-token")
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"github.com/webhookx-io/webhookx/constants"
 	"github.com/webhookx-io/webhookx/test/helper/factory"
 	"net"
+	"sort"
 	"strconv"
 	"testing"
 	"time"
@@ -396,6 +397,72 @@ var _ = Describe("delivery", Ordered, func() {
 		})
 	})
 
+	Context("max concurrency", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP(func(o *entities.Endpoint) {
+				o.Request.URL = "http://localhost:9999/delay/1"
+				o.MaxConcurrency = utils.Pointer(1)
+			})},
+			Sources: []*entities.Source{factory.SourceP()},
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("limits concurrent deliveries", func() {
+			err := waitForServer("0.0.0.0:8081", time.Second)
+			assert.NoError(GinkgoT(), err)
+
+			for i := 1; i <= 3; i++ {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+					Post("/")
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), 200, resp.StatusCode())
+			}
+
+			assert.Eventually(GinkgoT(), func() bool {
+				matched, err := helper.FileHasLine("webhookx.log", "^.*concurrency limit exceeded.*$")
+				return err == nil && matched
+			}, time.Second*5, time.Second)
+
+			// all deliveries eventually succeed one by one
+			q := query.AttemptQuery{}
+			q.EndpointId = &entitiesConfig.Endpoints[0].ID
+			q.Status = utils.Pointer(entities.AttemptStatusSuccess)
+			assert.Eventually(GinkgoT(), func() bool {
+				count, err := db.Attempts.Count(context.TODO(), q.WhereMap())
+				return err == nil && count == 3
+			}, time.Second*15, time.Second)
+
+			list, err := db.Attempts.List(context.TODO(), &q)
+			assert.NoError(GinkgoT(), err)
+			sort.Slice(list, func(i, j int) bool {
+				return list[i].AttemptedAt.Time.Before(list[j].AttemptedAt.Time)
+			})
+			for i := 1; i < len(list); i++ {
+				// deliveries do not overlap
+				assert.True(GinkgoT(), list[i].AttemptedAt.Time.Sub(list[i-1].AttemptedAt.Time) >= time.Second)
+			}
+		})
+	})
+
 	Context("unique_id", func() {
 		var proxyClient *resty.Client
 
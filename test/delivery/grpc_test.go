@@ -0,0 +1,126 @@
+package delivery
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/db/query"
+	deliveryv1 "github.com/webhookx-io/webhookx/proto/delivery/v1"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type deliveryServer struct {
+	deliveryv1.UnimplementedDeliveryServiceServer
+}
+
+func (s *deliveryServer) Deliver(ctx context.Context, event *deliveryv1.Event) (*deliveryv1.Ack, error) {
+	if strings.Contains(string(event.Payload), "invalid") {
+		return nil, status.Error(codes.InvalidArgument, "invalid event")
+	}
+	return &deliveryv1.Ack{}, nil
+}
+
+var _ = Describe("grpc endpoints", Ordered, func() {
+
+	var proxyClient *resty.Client
+
+	var app *app.Application
+	var db *db.DB
+	var server *grpc.Server
+
+	endpoint := factory.EndpointP(func(o *entities.Endpoint) {
+		o.Type = entities.EndpointTypeGRPC
+		o.Events = []string{"foo.bar"}
+		o.Retry.Config.Attempts = []int64{0, 1}
+	})
+	entitiesConfig := helper.EntitiesConfig{
+		Endpoints: []*entities.Endpoint{endpoint},
+		Sources:   []*entities.Source{factory.SourceP()},
+	}
+
+	ingest := func(body string) string {
+		var eventId string
+		assert.Eventually(GinkgoT(), func() bool {
+			resp, err := proxyClient.R().SetBody(body).Post("/")
+			if err != nil || resp.StatusCode() != 200 {
+				return false
+			}
+			eventId = resp.Header().Get("X-Webhookx-Event-Id")
+			return true
+		}, time.Second*5, time.Second)
+		return eventId
+	}
+
+	listAttempts := func(eventId string) []*entities.Attempt {
+		var q query.AttemptQuery
+		q.EventId = &eventId
+		attempts, err := db.Attempts.List(context.TODO(), &q)
+		if err != nil {
+			return nil
+		}
+		return attempts
+	}
+
+	BeforeAll(func() {
+		listener := utils.Must(net.Listen("tcp", "127.0.0.1:0"))
+		server = grpc.NewServer()
+		deliveryv1.RegisterDeliveryServiceServer(server, &deliveryServer{})
+		go func() { _ = server.Serve(listener) }()
+		endpoint.Request.URL = "grpc://" + listener.Addr().String()
+
+		db = helper.InitDB(true, &entitiesConfig)
+		proxyClient = helper.ProxyClient()
+
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+			"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+			"WEBHOOKX_WORKER_ENABLED": "true",
+		}))
+	})
+
+	AfterAll(func() {
+		app.Stop()
+		server.Stop()
+	})
+
+	It("delivers event", func() {
+		eventId := ingest(`{"event_type": "foo.bar", "data": {"key": "value"}}`)
+		assert.Eventually(GinkgoT(), func() bool {
+			attempts := listAttempts(eventId)
+			return len(attempts) == 1 && attempts[0].Status == entities.AttemptStatusSuccess
+		}, time.Second*10, time.Second)
+
+		attempt := listAttempts(eventId)[0]
+		assert.Equal(GinkgoT(), 200, attempt.Response.Status)
+		assert.Equal(GinkgoT(), 0, *attempt.Response.GRPCStatus)
+	})
+
+	It("does not retry permanent failures", func() {
+		eventId := ingest(`{"event_type": "foo.bar", "data": {"key": "invalid"}}`)
+		assert.Eventually(GinkgoT(), func() bool {
+			attempts := listAttempts(eventId)
+			return len(attempts) == 1 && attempts[0].Status == entities.AttemptStatusFailure
+		}, time.Second*10, time.Second)
+
+		attempt := listAttempts(eventId)[0]
+		assert.True(GinkgoT(), attempt.Exhausted)
+		assert.Equal(GinkgoT(), 400, attempt.Response.Status)
+		assert.Equal(GinkgoT(), int(codes.InvalidArgument), *attempt.Response.GRPCStatus)
+
+		time.Sleep(time.Second * 2)
+		assert.Len(GinkgoT(), listAttempts(eventId), 1)
+	})
+})
@@ -0,0 +1,172 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker"
+)
+
+var _ = Describe("endpoint health", Ordered, func() {
+
+	var proxyClient *resty.Client
+	var adminClient *resty.Client
+
+	var app *app.Application
+	var db *db.DB
+
+	endpoint := factory.EndpointP(func(o *entities.Endpoint) {
+		o.Events = []string{"foo.bar", constants.EventTypeEndpointFailing}
+		o.Request.URL = "http://localhost:9999/status/500"
+		o.Retry.Config.Attempts = []int64{0}
+	})
+	alerting := factory.EndpointP(func(o *entities.Endpoint) {
+		o.Events = []string{constants.EventTypeEndpointFailing, constants.EventTypeEndpointRecovered}
+	})
+	entitiesConfig := helper.EntitiesConfig{
+		Endpoints: []*entities.Endpoint{endpoint, alerting},
+		Sources:   []*entities.Source{factory.SourceP()},
+	}
+
+	listEvents := func(endpointId string, eventType string) []*entities.Event {
+		var q query.AttemptQuery
+		q.EndpointId = &endpointId
+		attempts, err := db.Attempts.List(context.TODO(), &q)
+		if err != nil {
+			return nil
+		}
+		list := make([]*entities.Event, 0)
+		for _, attempt := range attempts {
+			event, err := db.Events.Get(context.TODO(), attempt.EventId)
+			if err == nil && event != nil && event.EventType == eventType {
+				list = append(list, event)
+			}
+		}
+		return list
+	}
+
+	listAlerts := func(eventType string) []*entities.Event {
+		return listEvents(alerting.ID, eventType)
+	}
+
+	ingest := func() {
+		assert.Eventually(GinkgoT(), func() bool {
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar", "data": {"key": "value"}}`).
+				Post("/")
+			return err == nil && resp.StatusCode() == 200
+		}, time.Second*5, time.Second)
+	}
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, &entitiesConfig)
+		proxyClient = helper.ProxyClient()
+		adminClient = helper.AdminClient()
+
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN":                    "0.0.0.0:8080",
+			"WEBHOOKX_PROXY_LISTEN":                    "0.0.0.0:8081",
+			"WEBHOOKX_WORKER_ENABLED":                  "true",
+			"WEBHOOKX_WORKER_HEALTH_ENABLED":           "true",
+			"WEBHOOKX_WORKER_HEALTH_FAILURE_THRESHOLD": "2",
+		}))
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	It("dispatches failing event when failures reach the threshold", func() {
+		ingest()
+		assert.Eventually(GinkgoT(), func() bool {
+			health, err := db.EndpointHealth.Get(context.TODO(), endpoint.ID)
+			return err == nil && health != nil && health.ConsecutiveFailures == 1
+		}, time.Second*10, time.Second)
+		assert.Empty(GinkgoT(), listAlerts(constants.EventTypeEndpointFailing))
+
+		ingest()
+		var alerts []*entities.Event
+		assert.Eventually(GinkgoT(), func() bool {
+			alerts = listAlerts(constants.EventTypeEndpointFailing)
+			return len(alerts) == 1
+		}, time.Second*10, time.Second)
+
+		data := worker.EndpointHealthEventData{}
+		assert.NoError(GinkgoT(), json.Unmarshal(alerts[0].Data, &data))
+		assert.Equal(GinkgoT(), endpoint.ID, data.Endpoint.ID)
+		assert.Equal(GinkgoT(), "http://localhost:9999/status/500", data.Endpoint.URL)
+		assert.Equal(GinkgoT(), entities.EndpointHealthStatusFailing, data.Health.Status)
+		assert.EqualValues(GinkgoT(), 2, data.Health.ConsecutiveFailures)
+		assert.NotNil(GinkgoT(), data.Health.LastFailureAt)
+		assert.Nil(GinkgoT(), data.Health.LastSuccessAt)
+
+		// the failing endpoint is not notified of its own failure
+		assert.Empty(GinkgoT(), listEvents(endpoint.ID, constants.EventTypeEndpointFailing))
+	})
+
+	It("does not dispatch failing event again while failing", func() {
+		ingest()
+		assert.Eventually(GinkgoT(), func() bool {
+			health, err := db.EndpointHealth.Get(context.TODO(), endpoint.ID)
+			return err == nil && health != nil && health.ConsecutiveFailures == 3
+		}, time.Second*10, time.Second)
+		time.Sleep(time.Second)
+		assert.Len(GinkgoT(), listAlerts(constants.EventTypeEndpointFailing), 1)
+	})
+
+	It("exposes health on endpoint", func() {
+		resp, err := adminClient.R().
+			SetResult(entities.Endpoint{}).
+			Get("/workspaces/default/endpoints/" + endpoint.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+		result := resp.Result().(*entities.Endpoint)
+		assert.NotNil(GinkgoT(), result.Health)
+		assert.Equal(GinkgoT(), entities.EndpointHealthStatusFailing, result.Health.Status)
+		assert.EqualValues(GinkgoT(), 3, result.Health.ConsecutiveFailures)
+		assert.True(GinkgoT(), result.Health.SuccessRate < 1)
+
+		resp, err = adminClient.R().
+			SetResult(entities.Endpoint{}).
+			Get("/workspaces/default/endpoints/" + alerting.ID)
+		assert.NoError(GinkgoT(), err)
+		result = resp.Result().(*entities.Endpoint)
+		assert.NotNil(GinkgoT(), result.Health)
+		assert.Equal(GinkgoT(), entities.EndpointHealthStatusHealthy, result.Health.Status)
+	})
+
+	It("dispatches recovered event on success", func() {
+		resp, err := adminClient.R().
+			SetBody(map[string]interface{}{
+				"request": map[string]interface{}{"url": "http://localhost:9999/anything"},
+			}).
+			Put("/workspaces/default/endpoints/" + endpoint.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+		ingest()
+		var alerts []*entities.Event
+		assert.Eventually(GinkgoT(), func() bool {
+			alerts = listAlerts(constants.EventTypeEndpointRecovered)
+			return len(alerts) == 1
+		}, time.Second*10, time.Second)
+
+		data := worker.EndpointHealthEventData{}
+		assert.NoError(GinkgoT(), json.Unmarshal(alerts[0].Data, &data))
+		assert.Equal(GinkgoT(), entities.EndpointHealthStatusHealthy, data.Health.Status)
+		assert.EqualValues(GinkgoT(), 0, data.Health.ConsecutiveFailures)
+		assert.NotNil(GinkgoT(), data.Health.LastSuccessAt)
+	})
+})
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"strings"
+
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+type Config struct {
+	Contains  string `json:"contains" validate:"required"`
+	ErrorCode string `json:"error_code"`
+}
+
+// ResponsePlugin fails attempts whose response body contains the configured string
+type ResponsePlugin struct {
+	plugin.BasePlugin[Config]
+}
+
+func New(config []byte) (plugin.Plugin, error) {
+	p := &ResponsePlugin{}
+	p.Name = "response"
+
+	if config != nil {
+		if err := p.UnmarshalConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (p *ResponsePlugin) ValidateConfig() error {
+	return utils.Validate(p.Config)
+}
+
+func (p *ResponsePlugin) ExecuteOutbound(outbound *plugin.Outbound, _ *plugin.Context) error {
+	return nil
+}
+
+func (p *ResponsePlugin) ExecuteResponse(response *plugin.Response, _ *plugin.Context) error {
+	if strings.Contains(response.Body, p.Config.Contains) {
+		response.Success = false
+		response.ErrorCode = p.Config.ErrorCode
+	}
+	return nil
+}
@@ -148,8 +148,8 @@ func DB() *db.DB {
 	}
 	eventbus := eventbus.NewEventBus(
 		uuid.NewV4().String(),
-		cfg.Database.GetDSN(),
-		logger, sqlDB)
+		eventbus.NewPostgresBroker(cfg.Database.GetDSN(), sqlDB, logger),
+		logger)
 
 	db, err := db.NewDB(sqlDB, logger, eventbus)
 	if err != nil {
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var _ = Describe("prometheus", Ordered, func() {
+	var proxyClient *resty.Client
+	var statusClient *resty.Client
+	var app *app.Application
+	var entitiesConfig helper.EntitiesConfig
+
+	BeforeAll(func() {
+		entitiesConfig = helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+		helper.InitDB(true, &entitiesConfig)
+		proxyClient = helper.ProxyClient()
+		statusClient = helper.StatusClient()
+		var err error
+		app, err = helper.Start(map[string]string{
+			"WEBHOOKX_PROXY_LISTEN":          "0.0.0.0:8081",
+			"WEBHOOKX_WORKER_ENABLED":        "true",
+			"WEBHOOKX_METRICS_EXPORTS":       "prometheus",
+			"WEBHOOKX_METRICS_PUSH_INTERVAL": "1",
+		})
+		assert.Nil(GinkgoT(), err)
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	It("exposes metrics at /metrics", func() {
+		assert.Eventually(GinkgoT(), func() bool {
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar", "data": {"key": "value"}}`).
+				Post("/")
+			return err == nil && resp.StatusCode() == 200
+		}, time.Second*5, time.Second)
+
+		expected := []string{
+			"webhookx_runtime_num_goroutine",
+			"webhookx_runtime_alloc_bytes",
+			"webhookx_request_total",
+			"webhookx_request_duration_seconds",
+			"webhookx_event_total",
+			"webhookx_event_persisted",
+			"webhookx_attempt_total",
+			"webhookx_attempt_response_duration_seconds",
+		}
+		assert.Eventually(GinkgoT(), func() bool {
+			resp, err := statusClient.R().Get("/metrics")
+			if err != nil || resp.StatusCode() != 200 {
+				return false
+			}
+			for _, name := range expected {
+				if !strings.Contains(string(resp.Body()), name) {
+					return false
+				}
+			}
+			return true
+		}, time.Second*15, time.Second)
+	})
+
+	It("records labeled metrics", func() {
+		endpointID := entitiesConfig.Endpoints[0].ID
+		sourceID := entitiesConfig.Sources[0].ID
+		assert.Eventually(GinkgoT(), func() bool {
+			resp, err := statusClient.R().Get("/metrics")
+			if err != nil || resp.StatusCode() != 200 {
+				return false
+			}
+			body := string(resp.Body())
+			return regexp.MustCompile(`webhookx_event_total\{event_type="foo.bar",.*source="`+sourceID+`".*\} [1-9]`).MatchString(body) &&
+				regexp.MustCompile(`webhookx_attempt_total\{endpoint="`+endpointID+`",error_code="none",.*status_class="2xx".*\} [1-9]`).MatchString(body)
+		}, time.Second*15, time.Second)
+	})
+})
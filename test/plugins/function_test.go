@@ -111,4 +111,105 @@ var _ = Describe("function", Ordered, func() {
 			}, time.Second*5, time.Second)
 		})
 	})
+
+	Context("outbound", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+				factory.WithPluginName("function"),
+				factory.WithPluginConfig(function.Config{
+					Function: `
+					function handle() {
+						var body = JSON.parse(webhookx.request.getBody())
+						if (body.drop) {
+							webhookx.request.drop()
+							return
+						}
+						webhookx.request.setHeader('X-Endpoint-Id', webhookx.endpoint.id)
+						webhookx.request.setHeader('X-Event-Type', webhookx.event.event_type)
+						body.transformed = true
+						webhookx.request.setBody(JSON.stringify(body))
+					}`,
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("should transform request", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) == 0 {
+					return false
+				}
+				attempt = list[0]
+				return attempt.Status == entities.AttemptStatusSuccess
+			}, time.Second*5, time.Second)
+
+			var attemptDetail *entities.AttemptDetail
+			assert.Eventually(GinkgoT(), func() bool {
+				val, err := db.AttemptDetails.Get(context.TODO(), attempt.ID)
+				if err != nil || val == nil {
+					return false
+				}
+				attemptDetail = val
+				return true
+			}, time.Second*5, time.Second)
+
+			assert.Equal(GinkgoT(), entitiesConfig.Endpoints[0].ID, attemptDetail.RequestHeaders["X-Endpoint-Id"])
+			assert.Equal(GinkgoT(), "foo.bar", attemptDetail.RequestHeaders["X-Event-Type"])
+			assert.JSONEq(GinkgoT(), `{"key": "value", "transformed": true}`, *attemptDetail.RequestBody)
+		})
+
+		It("should drop delivery", func() {
+			assert.Nil(GinkgoT(), db.Truncate("attempts"))
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar","data": {"drop": true}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) == 0 {
+					return false
+				}
+				attempt = list[0]
+				return attempt.Status == entities.AttemptStatusCanceled
+			}, time.Second*5, time.Second)
+			assert.Equal(GinkgoT(), entities.AttemptErrorCodePluginDropped, *attempt.ErrorCode)
+		})
+	})
 })
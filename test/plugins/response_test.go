@@ -0,0 +1,182 @@
+package plugins
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/pkg/plugin"
+	"github.com/webhookx-io/webhookx/plugins/function"
+	"github.com/webhookx-io/webhookx/test/fixtures/plugins/response"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("response phase", Ordered, func() {
+
+	plugin.RegisterPlugin(plugin.TypeOutbound, "response", response.New)
+
+	var proxyClient *resty.Client
+
+	var app *app.Application
+	var db *db.DB
+
+	entitiesConfig := helper.EntitiesConfig{
+		Endpoints: []*entities.Endpoint{factory.EndpointP()},
+		Sources:   []*entities.Source{factory.SourceP()},
+	}
+	entitiesConfig.Endpoints[0].Retry.Config.Attempts = []int64{0}
+
+	entitiesConfig.Plugins = []*entities.Plugin{
+		factory.PluginP(
+			factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+			factory.WithPluginName("response"),
+			factory.WithPluginConfig(response.Config{
+				Contains:  `"ok": false`,
+				ErrorCode: "RESPONSE_REJECTED",
+			}),
+		),
+	}
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, &entitiesConfig)
+		proxyClient = helper.ProxyClient()
+
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+			"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+			"WEBHOOKX_WORKER_ENABLED": "true",
+		}))
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	It("overrides the result of the attempt", func() {
+		for _, ok := range []string{"true", "false"} {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar", "data": {"ok": ` + ok + `}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+		}
+
+		var list []*entities.Attempt
+		assert.Eventually(GinkgoT(), func() bool {
+			attempts, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+			if err != nil || len(attempts) != 2 {
+				return false
+			}
+			for _, attempt := range attempts {
+				if attempt.Status != entities.AttemptStatusSuccess && attempt.Status != entities.AttemptStatusFailure {
+					return false
+				}
+			}
+			list = attempts
+			return true
+		}, time.Second*5, time.Second)
+
+		statuses := make(map[string]*string)
+		for _, attempt := range list {
+			statuses[attempt.Status] = attempt.ErrorCode
+		}
+		assert.Len(GinkgoT(), statuses, 2)
+		assert.Nil(GinkgoT(), statuses[entities.AttemptStatusSuccess])
+		assert.Equal(GinkgoT(), "RESPONSE_REJECTED", utils.PointerValue(statuses[entities.AttemptStatusFailure]))
+	})
+})
+
+var _ = Describe("function response phase", Ordered, func() {
+
+	var proxyClient *resty.Client
+
+	var app *app.Application
+	var db *db.DB
+
+	entitiesConfig := helper.EntitiesConfig{
+		Endpoints: []*entities.Endpoint{factory.EndpointP()},
+		Sources:   []*entities.Source{factory.SourceP()},
+	}
+	entitiesConfig.Endpoints[0].Retry.Config.Attempts = []int64{0}
+
+	entitiesConfig.Plugins = []*entities.Plugin{
+		factory.PluginP(
+			factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+			factory.WithPluginName("function"),
+			factory.WithPluginConfig(function.Config{
+				Function: `
+				function handle() {}
+				function handleResponse() {
+					var body = webhookx.response.getBody()
+					if (body.indexOf('"ok": false') >= 0) {
+						webhookx.response.setSuccess(false)
+						webhookx.response.setErrorCode("RESPONSE_REJECTED")
+					} else if (body.indexOf('"ok": null') >= 0) {
+						webhookx.response.setSuccess(false)
+						webhookx.response.setErrorCode("this error code is invalid and too long")
+					}
+				}`,
+			}),
+		),
+	}
+
+	BeforeAll(func() {
+		db = helper.InitDB(true, &entitiesConfig)
+		proxyClient = helper.ProxyClient()
+
+		app = utils.Must(helper.Start(map[string]string{
+			"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+			"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+			"WEBHOOKX_WORKER_ENABLED": "true",
+		}))
+	})
+
+	AfterAll(func() {
+		app.Stop()
+	})
+
+	It("overrides the result of the attempt", func() {
+		for _, ok := range []string{"true", "false", "null"} {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar", "data": {"ok": ` + ok + `}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+		}
+
+		var list []*entities.Attempt
+		assert.Eventually(GinkgoT(), func() bool {
+			attempts, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+			if err != nil || len(attempts) != 3 {
+				return false
+			}
+			for _, attempt := range attempts {
+				if attempt.Status != entities.AttemptStatusSuccess && attempt.Status != entities.AttemptStatusFailure {
+					return false
+				}
+			}
+			list = attempts
+			return true
+		}, time.Second*5, time.Second)
+
+		codes := make(map[string]int)
+		for _, attempt := range list {
+			codes[attempt.Status+":"+utils.PointerValue(attempt.ErrorCode)]++
+		}
+		assert.Equal(GinkgoT(), map[string]int{
+			entities.AttemptStatusSuccess + ":":                  1,
+			entities.AttemptStatusFailure + ":RESPONSE_REJECTED": 1,
+			entities.AttemptStatusFailure + ":":                  1,
+		}, codes)
+	})
+})
@@ -0,0 +1,285 @@
+package plugins
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/app"
+	"github.com/webhookx-io/webhookx/db"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/plugins/transform"
+	"github.com/webhookx-io/webhookx/test/helper"
+	"github.com/webhookx-io/webhookx/test/helper/factory"
+	"github.com/webhookx-io/webhookx/utils"
+)
+
+var _ = Describe("transform", Ordered, func() {
+
+	Context("sanity", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+				factory.WithPluginName("transform"),
+				factory.WithPluginConfig(transform.Config{
+					Engine:  "jsonata",
+					Payload: `{"text": event.event_type & ": " & event.data.key}`,
+					Headers: map[string]string{"x-event-id": "event.id"},
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("transforms the payload", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{
+					    "event_type": "foo.bar",
+					    "data": {"key": "value"}
+					}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) == 0 {
+					return false
+				}
+				attempt = list[0]
+				return attempt.Status == entities.AttemptStatusSuccess
+			}, time.Second*5, time.Second)
+
+			var attemptDetail *entities.AttemptDetail
+			assert.Eventually(GinkgoT(), func() bool {
+				val, err := db.AttemptDetails.Get(context.TODO(), attempt.ID)
+				if err != nil || val == nil {
+					return false
+				}
+				attemptDetail = val
+				return true
+			}, time.Second*5, time.Second)
+
+			assert.Equal(GinkgoT(), `{"text":"foo.bar: value"}`, *attemptDetail.RequestBody)
+			assert.Equal(GinkgoT(), attempt.EventId, attemptDetail.RequestHeaders["X-Event-Id"])
+		})
+	})
+
+	Context("condition", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+				factory.WithPluginName("transform"),
+				factory.WithPluginConfig(transform.Config{
+					Engine:  "jsonata",
+					Payload: `{"transformed": true}`,
+				}),
+			),
+		}
+		entitiesConfig.Plugins[0].Condition = &entities.PluginCondition{Expression: `event.data.key = "transform"`}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("executes the plugin only if the condition is met", func() {
+			for _, value := range []string{"transform", "skip"} {
+				assert.Eventually(GinkgoT(), func() bool {
+					resp, err := proxyClient.R().
+						SetBody(`{"event_type": "foo.bar", "data": {"key": "` + value + `"}}`).
+						Post("/")
+					return err == nil && resp.StatusCode() == 200
+				}, time.Second*5, time.Second)
+			}
+
+			bodies := make(map[string]bool)
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) != 2 {
+					return false
+				}
+				for _, attempt := range list {
+					detail, err := db.AttemptDetails.Get(context.TODO(), attempt.ID)
+					if err != nil || detail == nil || detail.RequestBody == nil {
+						return false
+					}
+					bodies[*detail.RequestBody] = true
+				}
+				return true
+			}, time.Second*5, time.Second)
+
+			assert.True(GinkgoT(), bodies[`{"transformed":true}`])
+			assert.True(GinkgoT(), bodies[`{"key": "skip"}`])
+		})
+	})
+
+	Context("workspace plugin", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP(), factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginName("transform"),
+				factory.WithPluginConfig(transform.Config{
+					Engine:  "jsonata",
+					Payload: `{"scope": "workspace"}`,
+				}),
+			),
+			factory.PluginP(
+				factory.WithPluginEndpointID(entitiesConfig.Endpoints[1].ID),
+				factory.WithPluginName("transform"),
+				factory.WithPluginConfig(transform.Config{
+					Engine:  "jsonata",
+					Payload: `{"scope": "endpoint"}`,
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("applies the workspace plugin unless it is overridden by the endpoint", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar", "data": {"key": "value"}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			bodies := make(map[string]string)
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) != 2 {
+					return false
+				}
+				for _, attempt := range list {
+					detail, err := db.AttemptDetails.Get(context.TODO(), attempt.ID)
+					if err != nil || detail == nil || detail.RequestBody == nil {
+						return false
+					}
+					bodies[attempt.EndpointId] = *detail.RequestBody
+				}
+				return true
+			}, time.Second*5, time.Second)
+
+			assert.Equal(GinkgoT(), `{"scope":"workspace"}`, bodies[entitiesConfig.Endpoints[0].ID])
+			assert.Equal(GinkgoT(), `{"scope":"endpoint"}`, bodies[entitiesConfig.Endpoints[1].ID])
+		})
+
+		It("stops applying the plugin to the previous endpoint when it is moved", func() {
+			list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+			assert.NoError(GinkgoT(), err)
+			previous := make(map[string]bool)
+			for _, attempt := range list {
+				previous[attempt.ID] = true
+			}
+
+			resp, err := helper.AdminClient().R().
+				SetBody(map[string]interface{}{
+					"endpoint_id": entitiesConfig.Endpoints[0].ID,
+				}).
+				Put("/workspaces/default/plugins/" + entitiesConfig.Plugins[1].ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 200, resp.StatusCode())
+
+			time.Sleep(time.Second)
+
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar", "data": {"key": "value"}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			bodies := make(map[string]string)
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) != len(previous)+2 {
+					return false
+				}
+				for _, attempt := range list {
+					if previous[attempt.ID] {
+						continue
+					}
+					detail, err := db.AttemptDetails.Get(context.TODO(), attempt.ID)
+					if err != nil || detail == nil || detail.RequestBody == nil {
+						return false
+					}
+					bodies[attempt.EndpointId] = *detail.RequestBody
+				}
+				return true
+			}, time.Second*5, time.Second)
+
+			assert.Equal(GinkgoT(), `{"scope":"endpoint"}`, bodies[entitiesConfig.Endpoints[0].ID])
+			assert.Equal(GinkgoT(), `{"scope":"workspace"}`, bodies[entitiesConfig.Endpoints[1].ID])
+		})
+	})
+})
@@ -95,4 +95,152 @@ var _ = Describe("wasm", Ordered, func() {
 			assert.Equal(GinkgoT(), `{"key": "value", "other": "other-value"}`, *attemptDetail.RequestBody)
 		})
 	})
+
+	Context("inbound", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP(), factory.SourceP(factory.WithSourcePath("/reject"))},
+		}
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginSourceID(entitiesConfig.Sources[0].ID),
+				factory.WithPluginName("wasm"),
+				factory.WithPluginConfig(wasm.Config{
+					File: test.FilePath("../plugins/wasm/testdata/inbound.wasm"),
+				}),
+			),
+			factory.PluginP(
+				factory.WithPluginSourceID(entitiesConfig.Sources[1].ID),
+				factory.WithPluginName("wasm"),
+				factory.WithPluginConfig(wasm.Config{
+					File: test.FilePath("../plugins/wasm/testdata/inbound.wasm"),
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":   "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":   "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED": "true",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("should transform event", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"key": "value"}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			var event *entities.Event
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Events.List(context.TODO(), &query.EventQuery{})
+				if err != nil || len(list) != 1 {
+					return false
+				}
+				event = list[0]
+				return true
+			}, time.Second*5, time.Second)
+			assert.Equal(GinkgoT(), "inbound.received", event.EventType)
+			assert.JSONEq(GinkgoT(), `{"method": "POST", "path": "/", "payload": {"key": "value"}}`, string(event.Data))
+		})
+
+		It("should return desired response", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"key": "value"}`).
+					Post("/reject")
+				return err == nil && resp.StatusCode() == 400 &&
+					string(resp.Body()) == `{"message":"rejected"}` &&
+					resp.Header().Get("Content-Type") == "application/json"
+			}, time.Second*5, time.Second)
+		})
+	})
+
+	Context("limits", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP(), factory.SourceP(factory.WithSourcePath("/limit"))},
+		}
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+				factory.WithPluginName("wasm"),
+				factory.WithPluginConfig(wasm.Config{
+					File: test.FilePath("../plugins/wasm/testdata/infinite_loop.wasm"),
+				}),
+			),
+			factory.PluginP(
+				factory.WithPluginSourceID(entitiesConfig.Sources[1].ID),
+				factory.WithPluginName("wasm"),
+				factory.WithPluginConfig(wasm.Config{
+					File: test.FilePath("../plugins/wasm/testdata/infinite_loop.wasm"),
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_ADMIN_LISTEN":        "0.0.0.0:8080",
+				"WEBHOOKX_PROXY_LISTEN":        "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED":      "true",
+				"WEBHOOKX_PLUGIN_WASM_TIMEOUT": "100",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("should cancel attempt when plugin exceeds timeout", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			var attempt *entities.Attempt
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) == 0 {
+					return false
+				}
+				attempt = list[0]
+				return attempt.Status == entities.AttemptStatusCanceled
+			}, time.Second*5, time.Second)
+			assert.Equal(GinkgoT(), entities.AttemptErrorCodePluginLimit, *attempt.ErrorCode)
+		})
+
+		It("should reject request when inbound plugin exceeds timeout", func() {
+			resp, err := proxyClient.R().
+				SetBody(`{"event_type": "foo.bar","data": {"key": "value"}}`).
+				Post("/limit")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), 503, resp.StatusCode())
+			assert.Equal(GinkgoT(), `{"message":"plugin 'wasm' execution exceeded the time limit of 100ms"}`, string(resp.Body()))
+		})
+	})
 })
@@ -2,6 +2,9 @@ package plugins
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/webhookx-io/webhookx/config"
 	"github.com/webhookx-io/webhookx/plugins/webhookx_signature"
@@ -131,4 +134,69 @@ var _ = Describe("webhookx-signature", Ordered, func() {
 			assert.Equal(GinkgoT(), "bar", attemptDetail.RequestHeaders["Foo"])
 		})
 	})
+
+	Context("secret reference", func() {
+		var proxyClient *resty.Client
+
+		var app *app.Application
+		var db *db.DB
+
+		entitiesConfig := helper.EntitiesConfig{
+			Endpoints: []*entities.Endpoint{factory.EndpointP()},
+			Sources:   []*entities.Source{factory.SourceP()},
+		}
+		entitiesConfig.Plugins = []*entities.Plugin{
+			factory.PluginP(
+				factory.WithPluginEndpointID(entitiesConfig.Endpoints[0].ID),
+				factory.WithPluginName("webhookx-signature"),
+				factory.WithPluginConfig(webhookx_signature.Config{
+					SigningSecret: "{secret://env/WEBHOOKX_TEST_SIGNING_SECRET}",
+				}),
+			),
+		}
+
+		BeforeAll(func() {
+			GinkgoT().Setenv("WEBHOOKX_TEST_SIGNING_SECRET", "abcdefg")
+			db = helper.InitDB(true, &entitiesConfig)
+			proxyClient = helper.ProxyClient()
+
+			app = utils.Must(helper.Start(map[string]string{
+				"WEBHOOKX_PROXY_LISTEN":            "0.0.0.0:8081",
+				"WEBHOOKX_WORKER_ENABLED":          "true",
+				"WEBHOOKX_SECRET_PLUGIN_PROVIDERS": "env",
+			}))
+		})
+
+		AfterAll(func() {
+			app.Stop()
+		})
+
+		It("signs with the referenced secret", func() {
+			assert.Eventually(GinkgoT(), func() bool {
+				resp, err := proxyClient.R().
+					SetBody(`{"event_type": "foo.bar", "data": {"key": "value"}}`).
+					Post("/")
+				return err == nil && resp.StatusCode() == 200
+			}, time.Second*5, time.Second)
+
+			var attemptDetail *entities.AttemptDetail
+			assert.Eventually(GinkgoT(), func() bool {
+				list, err := db.Attempts.List(context.TODO(), &query.AttemptQuery{})
+				if err != nil || len(list) == 0 {
+					return false
+				}
+				val, err := db.AttemptDetails.Get(context.TODO(), list[0].ID)
+				if err != nil || val == nil {
+					return false
+				}
+				attemptDetail = val
+				return true
+			}, time.Second*5, time.Second)
+
+			timestamp := attemptDetail.RequestHeaders["Webhookx-Timestamp"]
+			mac := hmac.New(sha256.New, []byte("abcdefg"))
+			mac.Write([]byte(timestamp + "." + `{"key": "value"}`))
+			assert.Equal(GinkgoT(), "v1="+hex.EncodeToString(mac.Sum(nil)), attemptDetail.RequestHeaders["Webhookx-Signature"])
+		})
+	})
 })
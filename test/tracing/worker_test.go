@@ -155,6 +155,41 @@ var _ = Describe("tracing worker", Ordered, func() {
 					return true
 				}, time.Second*60, time.Second)
 			})
+
+			It("continues the trace started at ingestion", func() {
+				traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+
+				n, err := helper.FileCountLine(helper.OtelCollectorTracesFile)
+				assert.Nil(GinkgoT(), err)
+				n++
+
+				assert.Eventually(GinkgoT(), func() bool {
+					resp, err := proxyClient.R().
+						SetHeader("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01").
+						SetBody(`{"event_type": "foo.bar", "data": {"key": "value"}}`).
+						Post("/")
+					return err == nil && resp.StatusCode() == 200
+				}, time.Second*5, time.Second)
+
+				gotSpans := make(map[string]bool)
+				assert.Eventually(GinkgoT(), func() bool {
+					line, err := helper.FileLine(helper.OtelCollectorTracesFile, n)
+					if err != nil || line == "" {
+						return false
+					}
+					n++
+
+					var trace ExportedTrace
+					if err := json.Unmarshal([]byte(line), &trace); err != nil {
+						return false
+					}
+					_, spanAttrs := trace.filterSpansByTraceID(traceID)
+					for name := range spanAttrs {
+						gotSpans[name] = true
+					}
+					return gotSpans["proxy.handle"] && gotSpans["worker.submit"] && gotSpans["worker.deliver"]
+				}, time.Second*60, time.Second)
+			})
 		})
 	}
 })
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -12,3 +13,13 @@ func HeaderMap(header http.Header) map[string]string {
 	}
 	return headers
 }
+
+// StripUserinfo removes the username and password from the URL
+func StripUserinfo(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,226 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/webhookx-io/webhookx/db/entities"
+	"golang.org/x/sync/singleflight"
+)
+
+var ErrAuthentication = errors.New("authentication failed")
+
+const (
+	// expirySkew refreshes tokens slightly before they actually expire
+	expirySkew = 30 * time.Second
+	// defaultExpiresIn is used when the token response has no expires_in
+	defaultExpiresIn = time.Hour
+	maxTokenBodySize = 1 << 20
+)
+
+// TokenTimeout is the timeout of fetching a token
+const TokenTimeout = 10 * time.Second
+
+// Token is an OAuth 2.0 access token
+type Token struct {
+	AccessToken string
+	TokenType   string
+	ExpiresAt   time.Time
+}
+
+func (t *Token) Expired() bool {
+	return time.Now().Add(expirySkew).After(t.ExpiresAt)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Authenticator applies the endpoint's outbound authentication to delivery requests.
+// Access tokens are cached in process memory only, and concurrent requests for the same
+// token are merged into one token request.
+type Authenticator struct {
+	client *http.Client
+
+	mux sync.Mutex
+	// tokens are cached in process memory rather than in mcache, as access tokens must not be written to Redis
+	tokens map[string]*Token
+	group  singleflight.Group
+}
+
+type Options struct {
+	// Client is used to request OAuth 2.0 access tokens
+	Client *http.Client
+}
+
+func NewAuthenticator(opts Options) *Authenticator {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &Authenticator{
+		client: client,
+		tokens: make(map[string]*Token),
+	}
+}
+
+// Authorize sets the Authorization header according to the auth config
+func (a *Authenticator) Authorize(ctx context.Context, cfg *entities.AuthConfig, headers map[string]string) error {
+	switch cfg.Type {
+	case entities.AuthTypeBasic:
+		credentials := cfg.Basic.Username + ":" + cfg.Basic.Password
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	case entities.AuthTypeBearer:
+		headers["Authorization"] = "Bearer " + cfg.Bearer.Token
+	case entities.AuthTypeOAuth2ClientCredentials:
+		token, err := a.token(ctx, cfg.OAuth2ClientCredentials)
+		if err != nil {
+			return err
+		}
+		headers["Authorization"] = token.TokenType + " " + token.AccessToken
+	default:
+		return fmt.Errorf("%w: unknown auth type '%s'", ErrAuthentication, cfg.Type)
+	}
+	return nil
+}
+
+// Refreshable reports whether the auth config holds credentials that can be refreshed
+func Refreshable(cfg *entities.AuthConfig) bool {
+	return cfg != nil && cfg.Type == entities.AuthTypeOAuth2ClientCredentials
+}
+
+// Invalidate removes the cached credentials, the next Authorize will obtain new ones
+func (a *Authenticator) Invalidate(ctx context.Context, cfg *entities.AuthConfig) error {
+	if !Refreshable(cfg) {
+		return nil
+	}
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	delete(a.tokens, tokenCacheKey(cfg.OAuth2ClientCredentials))
+	return nil
+}
+
+func tokenCacheKey(cfg *entities.OAuth2ClientCredentials) string {
+	h := sha256.New()
+	for _, s := range []string{cfg.TokenURL, cfg.ClientId, cfg.ClientSecret, strings.Join(cfg.Scopes, " "), cfg.Audience} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (a *Authenticator) cachedToken(key string) *Token {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	token, ok := a.tokens[key]
+	if !ok {
+		return nil
+	}
+	if token.Expired() {
+		delete(a.tokens, key)
+		return nil
+	}
+	return token
+}
+
+func (a *Authenticator) token(ctx context.Context, cfg *entities.OAuth2ClientCredentials) (*Token, error) {
+	key := tokenCacheKey(cfg)
+	if token := a.cachedToken(key); token != nil {
+		return token, nil
+	}
+
+	v, err, _ := a.group.Do(key, func() (interface{}, error) {
+		if token := a.cachedToken(key); token != nil {
+			return token, nil
+		}
+		token, err := a.fetchToken(context.WithoutCancel(ctx), cfg)
+		if err != nil {
+			return nil, err
+		}
+		a.mux.Lock()
+		for k, t := range a.tokens {
+			if t.Expired() {
+				delete(a.tokens, k)
+			}
+		}
+		a.tokens[key] = token
+		a.mux.Unlock()
+		return token, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Token), nil
+}
+
+func (a *Authenticator) fetchToken(ctx context.Context, cfg *entities.OAuth2ClientCredentials) (*Token, error) {
+	ctx, cancel := context.WithTimeout(ctx, TokenTimeout)
+	defer cancel()
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+	if cfg.Audience != "" {
+		form.Set("audience", cfg.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAuthentication, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cfg.ClientId), url.QueryEscape(cfg.ClientSecret))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to request token: %v", ErrAuthentication, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read token response: %v", ErrAuthentication, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: token endpoint returned status %d", ErrAuthentication, resp.StatusCode)
+	}
+
+	var res tokenResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("%w: invalid token response: %v", ErrAuthentication, err)
+	}
+	if res.AccessToken == "" {
+		return nil, fmt.Errorf("%w: token response has no access_token", ErrAuthentication)
+	}
+
+	token := &Token{
+		AccessToken: res.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   time.Now().Add(defaultExpiresIn),
+	}
+	if res.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	if strings.EqualFold(res.TokenType, "bearer") || res.TokenType == "" {
+		token.TokenType = "Bearer"
+	} else {
+		token.TokenType = res.TokenType
+	}
+	return token, nil
+}
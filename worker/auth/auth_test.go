@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/db/entities"
+)
+
+func TestAuthorize(t *testing.T) {
+	authenticator := NewAuthenticator(Options{})
+
+	t.Run("basic", func(t *testing.T) {
+		headers := make(map[string]string)
+		err := authenticator.Authorize(context.TODO(), &entities.AuthConfig{
+			Type:  entities.AuthTypeBasic,
+			Basic: &entities.BasicAuth{Username: "foo", Password: "bar"},
+		}, headers)
+		assert.NoError(t, err)
+		assert.Equal(t, "Basic Zm9vOmJhcg==", headers["Authorization"])
+	})
+
+	t.Run("bearer", func(t *testing.T) {
+		headers := make(map[string]string)
+		err := authenticator.Authorize(context.TODO(), &entities.AuthConfig{
+			Type:   entities.AuthTypeBearer,
+			Bearer: &entities.BearerAuth{Token: "token"},
+		}, headers)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token", headers["Authorization"])
+	})
+
+	t.Run("oauth2_client_credentials", func(t *testing.T) {
+		var requests atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := requests.Add(1)
+			_ = r.ParseForm()
+			username, password, _ := r.BasicAuth()
+			if username != "client" || password != "secret" || r.Form.Get("grant_type") != "client_credentials" {
+				w.WriteHeader(401)
+				return
+			}
+			assert.Equal(t, "read write", r.Form.Get("scope"))
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "token-" + string(rune('0'+n)),
+				"token_type":   "bearer",
+				"expires_in":   3600,
+			})
+		}))
+		defer server.Close()
+
+		cfg := &entities.AuthConfig{
+			Type: entities.AuthTypeOAuth2ClientCredentials,
+			OAuth2ClientCredentials: &entities.OAuth2ClientCredentials{
+				TokenURL:     server.URL,
+				ClientId:     "client",
+				ClientSecret: "secret",
+				Scopes:       []string{"read", "write"},
+			},
+		}
+
+		headers := make(map[string]string)
+		assert.NoError(t, authenticator.Authorize(context.TODO(), cfg, headers))
+		assert.Equal(t, "Bearer token-1", headers["Authorization"])
+
+		// cached
+		assert.NoError(t, authenticator.Authorize(context.TODO(), cfg, headers))
+		assert.Equal(t, "Bearer token-1", headers["Authorization"])
+		assert.EqualValues(t, 1, requests.Load())
+
+		// refreshed after invalidation
+		assert.NoError(t, authenticator.Invalidate(context.TODO(), cfg))
+		assert.NoError(t, authenticator.Authorize(context.TODO(), cfg, headers))
+		assert.Equal(t, "Bearer token-2", headers["Authorization"])
+		assert.EqualValues(t, 2, requests.Load())
+
+		// invalid credentials
+		cfg.OAuth2ClientCredentials.ClientSecret = "invalid"
+		err := authenticator.Authorize(context.TODO(), cfg, headers)
+		assert.True(t, errors.Is(err, ErrAuthentication))
+		assert.Equal(t, "authentication failed: token endpoint returned status 401", err.Error())
+	})
+}
+
+func TestTokenConcurrency(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(time.Millisecond * 100)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	authenticator := NewAuthenticator(Options{})
+	cfg := &entities.AuthConfig{
+		Type: entities.AuthTypeOAuth2ClientCredentials,
+		OAuth2ClientCredentials: &entities.OAuth2ClientCredentials{
+			TokenURL:     server.URL,
+			ClientId:     "client",
+			ClientSecret: "secret",
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			headers := make(map[string]string)
+			assert.NoError(t, authenticator.Authorize(context.TODO(), cfg, headers))
+			assert.Equal(t, "Bearer token", headers["Authorization"])
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestToken(t *testing.T) {
+	token := &Token{ExpiresAt: time.Now().Add(time.Minute)}
+	assert.False(t, token.Expired())
+	token.ExpiresAt = time.Now().Add(time.Second * 10)
+	assert.True(t, token.Expired())
+}
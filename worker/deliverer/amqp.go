@@ -0,0 +1,225 @@
+package deliverer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+	"net"
+	"net/url"
+	"time"
+)
+
+// amqpConnection is the AMQP connection used by AMQPDeliverer
+type amqpConnection interface {
+	Channel() (amqpChannel, error)
+	IsClosed() bool
+	Close() error
+}
+
+type amqpChannel interface {
+	Confirm(noWait bool) error
+	// Publish publishes the message and returns the publisher confirmation
+	Publish(ctx context.Context, exchange, key string, msg amqp.Publishing) (amqpConfirmation, error)
+	Close() error
+}
+
+type amqpConfirmation interface {
+	WaitContext(ctx context.Context) (bool, error)
+}
+
+type amqpConnectionAdapter struct {
+	*amqp.Connection
+}
+
+func (c amqpConnectionAdapter) Channel() (amqpChannel, error) {
+	ch, err := c.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+	return amqpChannelAdapter{ch}, nil
+}
+
+type amqpChannelAdapter struct {
+	*amqp.Channel
+}
+
+func (c amqpChannelAdapter) Publish(ctx context.Context, exchange, key string, msg amqp.Publishing) (amqpConfirmation, error) {
+	confirmation, err := c.Channel.PublishWithDeferredConfirmWithContext(ctx, exchange, key, false, false, msg)
+	if err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+type amqpDialFunc func(uri string, config amqp.Config) (amqpConnection, error)
+
+func amqpDial(uri string, config amqp.Config) (amqpConnection, error) {
+	conn, err := amqp.DialConfig(uri, config)
+	if err != nil {
+		return nil, err
+	}
+	return amqpConnectionAdapter{conn}, nil
+}
+
+// AMQPDeliverer delivers via AMQP 0-9-1.
+// The request URL is amqp[s]://host:5672/[vhost]?exchange=name&routing_key=key, the credentials
+// of the request are used for authentication, messages are published as persistent messages and are delivered once they are confirmed by the broker.
+type AMQPDeliverer struct {
+	log            *zap.SugaredLogger
+	requestTimeout time.Duration
+	dial           dialFunc
+	amqpDial       amqpDialFunc
+	connections    *clientCache[amqpConnection]
+}
+
+func NewAMQPDeliverer(opts Options) *AMQPDeliverer {
+	return &AMQPDeliverer{
+		log:            opts.Logger,
+		requestTimeout: opts.RequestTimeout,
+		dial:           defaultDial,
+		amqpDial:       amqpDial,
+		connections:    newClientCache(func(c amqpConnection) { _ = c.Close() }),
+	}
+}
+
+func (d *AMQPDeliverer) SetupACL(opts AclOptions) error {
+	d.dial = restrictedDialFunc(NewACL(opts))
+	return nil
+}
+
+type amqpTarget struct {
+	uri      string
+	exchange string
+	key      string
+}
+
+func parseAMQPURL(s string, credentials *Credentials) (*amqpTarget, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "amqp" && u.Scheme != "amqps" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	q := u.Query()
+	target := &amqpTarget{
+		exchange: q.Get("exchange"),
+		key:      q.Get("routing_key"),
+	}
+	u.RawQuery = ""
+	if credentials != nil {
+		u.User = credentials.userinfo()
+	}
+	target.uri = u.String()
+	return target, nil
+}
+
+func (d *AMQPDeliverer) getConnection(ctx context.Context, target *amqpTarget, opts *TLSOptions) (string, amqpConnection, error) {
+	key := clientKey(target.uri, opts)
+	connect := func() (amqpConnection, error) {
+		config := amqp.Config{
+			Heartbeat: 10 * time.Second,
+			// dials with the context of the delivery, the deadline covers the TLS and AMQP handshake
+			Dial: func(network, addr string) (net.Conn, error) {
+				conn, err := d.dial(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				}
+				if deadline, ok := ctx.Deadline(); ok {
+					_ = conn.SetDeadline(deadline)
+				}
+				return conn, nil
+			},
+		}
+		if opts != nil {
+			tlsConfig, err := opts.Build(DefaultTLSConfig)
+			if err != nil {
+				return nil, err
+			}
+			config.TLSClientConfig = tlsConfig
+		}
+		return d.amqpDial(target.uri, config)
+	}
+
+	conn, err := d.connections.Get(key, connect)
+	if err == nil && conn.IsClosed() {
+		// the connection was closed by the broker or due to network failure
+		d.connections.Remove(key)
+		conn, err = d.connections.Get(key, connect)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return key, conn, nil
+}
+
+func (d *AMQPDeliverer) publish(ctx context.Context, req *Request, target *amqpTarget) error {
+	key, conn, err := d.getConnection(ctx, target, req.TLS)
+	if err != nil {
+		return err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		d.connections.Remove(key)
+		return err
+	}
+	defer func() { _ = ch.Close() }()
+
+	if err := ch.Confirm(false); err != nil {
+		return err
+	}
+
+	msg := amqp.Publishing{
+		Headers:      amqp.Table{},
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         req.Payload,
+	}
+	for name, value := range messageHeaders(ctx, req) {
+		msg.Headers[name] = value
+	}
+
+	confirmation, err := ch.Publish(ctx, target.exchange, target.key, msg)
+	if err != nil {
+		return err
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return errors.New("message is rejected by broker")
+	}
+	return nil
+}
+
+func (d *AMQPDeliverer) Deliver(ctx context.Context, req *Request) (res *Response) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout(req, d.requestTimeout))
+	defer cancel()
+
+	res = &Response{
+		Request: req,
+	}
+
+	target, err := parseAMQPURL(req.URL, req.Credentials)
+	if err != nil {
+		res.setError(err)
+		return
+	}
+
+	res.Latancy = timing(func() {
+		res.setError(d.publish(ctx, req, target))
+	})
+
+	return
+}
+
+// Close closes the cached connections
+func (d *AMQPDeliverer) Close() error {
+	d.connections.Purge()
+	return nil
+}
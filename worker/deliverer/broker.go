@@ -0,0 +1,160 @@
+package deliverer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"golang.org/x/sync/singleflight"
+	"maps"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrDenied is the error returned when dialing an address that is denied by the ACL
+var ErrDenied = errors.New("denied")
+
+// maxBrokerClients is the maximum number of cached broker clients of a deliverer
+const maxBrokerClients = 100
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+var defaultDial dialFunc = (&net.Dialer{Timeout: 10 * time.Second}).DialContext
+
+// tlsDial returns a dialFunc that performs TLS handshake on connections dialed by dial
+func tlsDial(dial dialFunc, config *tls.Config) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		cfg := config
+		if cfg.ServerName == "" {
+			host, _, _ := net.SplitHostPort(addr)
+			cfg = config.Clone()
+			cfg.ServerName = host
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
+
+// checkACL checks the addresses against the ACL before connecting, it returns ErrDenied
+// if any of the addresses is resolved to denied IPs only.
+func checkACL(ctx context.Context, acl *ACL, addrs []string) error {
+	for _, addr := range addrs {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		ips, err := DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(ips, func(ip netip.Addr) bool { return acl.Allow(host, ip) }) {
+			return fmt.Errorf("request to %s(ip=%s) is %w", addr, ips[0], ErrDenied)
+		}
+	}
+	return nil
+}
+
+// clientCache caches the clients connected to brokers, so that deliveries to
+// the same broker share the connection. Evicted clients are closed.
+type clientCache[T any] struct {
+	cache *lru.Cache[string, T]
+	group singleflight.Group
+}
+
+func newClientCache[T any](close func(T)) *clientCache[T] {
+	cache, _ := lru.NewWithEvict(maxBrokerClients, func(_ string, client T) {
+		close(client)
+	})
+	return &clientCache[T]{cache: cache}
+}
+
+// Get returns the client cached on the key, the client is created by fn when it's absent
+func (c *clientCache[T]) Get(key string, fn func() (T, error)) (T, error) {
+	if client, ok := c.cache.Get(key); ok {
+		return client, nil
+	}
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		if client, ok := c.cache.Get(key); ok {
+			return client, nil
+		}
+		client, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		c.cache.Add(key, client)
+		return client, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// Remove removes and closes the client cached on the key
+func (c *clientCache[T]) Remove(key string) {
+	c.cache.Remove(key)
+}
+
+// Purge removes and closes all cached clients
+func (c *clientCache[T]) Purge() {
+	c.cache.Purge()
+}
+
+// clientKey returns the cache key of a client connected to the address with the TLS options
+func clientKey(addr string, opts *TLSOptions) string {
+	var sb strings.Builder
+	sb.WriteString(addr)
+	if opts != nil {
+		sb.WriteString("#")
+		sb.WriteString(opts.Hash())
+	}
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// messageHeaders returns the headers of the message published to brokers, including the trace context
+func messageHeaders(ctx context.Context, req *Request) map[string]string {
+	header := make(http.Header)
+	tracing.InjectHeader(ctx, header)
+	headers := make(map[string]string, len(header)+len(req.Headers))
+	for name := range header {
+		headers[name] = header.Get(name)
+	}
+	maps.Copy(headers, req.Headers)
+	return headers
+}
+
+func requestTimeout(req *Request, fallback time.Duration) time.Duration {
+	if req.Timeout != 0 {
+		return req.Timeout
+	}
+	return fallback
+}
+
+// setError sets the error of the response, the response is marked as denied if the error is caused by the ACL
+func (r *Response) setError(err error) {
+	if err == nil {
+		return
+	}
+	r.Error = err
+	if errors.Is(err, ErrDenied) {
+		r.ACL.Denied = true
+	}
+}
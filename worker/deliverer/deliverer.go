@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -18,6 +19,28 @@ type Request struct {
 	Payload []byte
 	Headers map[string]string
 	Timeout time.Duration
+	TLS     *TLSOptions
+	// Credentials authenticates with brokers, it takes precedence over the credentials of the URL
+	Credentials *Credentials
+}
+
+// Credentials is the username and password used to authenticate with brokers
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (c *Credentials) userinfo() *url.Userinfo {
+	return url.UserPassword(c.Username, c.Password)
+}
+
+// TLSOptions is the per-request TLS configuration
+type TLSOptions struct {
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+	CACert     string `json:"ca_cert"`
+	ServerName string `json:"server_name"`
+	MinVersion string `json:"min_version"`
 }
 
 type AclDecision struct {
@@ -25,20 +48,35 @@ type AclDecision struct {
 }
 
 type Response struct {
-	Request         *Request
-	ACL             AclDecision
-	StatusCode      int
-	Header          http.Header
-	ResponseBody    []byte
+	Request      *Request
+	ACL          AclDecision
+	StatusCode   int
+	Header       http.Header
+	ResponseBody []byte
+	// Truncated reports whether ResponseBody is truncated to the maximum response body size
+	Truncated       bool
 	Error           error
 	Latancy         time.Duration
 	ProxyStatusCode int
+	// GRPCStatus is the status code returned by gRPC endpoints
+	GRPCStatus *int
+	// Permanent reports whether the failure is permanent, the delivery is not retried if true
+	Permanent bool
 }
 
 func (r *Response) Is2xx() bool {
 	return r.StatusCode >= 200 && r.StatusCode <= 299
 }
 
+// Success reports whether the delivery succeeded. Deliveries to brokers
+// have no status code, they succeed when the broker accepts the message.
+func (r *Response) Success() bool {
+	if r.StatusCode != 0 {
+		return r.Is2xx()
+	}
+	return r.Error == nil
+}
+
 func (r *Response) String() string {
 	return fmt.Sprintf("%s %s %d %dms", r.Request.Method, r.Request.URL, r.StatusCode, r.Latancy.Milliseconds())
 }
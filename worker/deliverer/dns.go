@@ -0,0 +1,51 @@
+package deliverer
+
+import (
+	"context"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/sync/singleflight"
+	"net/netip"
+	"time"
+)
+
+// maxDNSCacheEntries is the maximum number of cached hosts
+const maxDNSCacheEntries = 10000
+
+type dnsCacheEntry struct {
+	addrs     []netip.Addr
+	expiresAt time.Time
+}
+
+// CachingResolver caches the lookup results of DefaultResolver for ttl
+type CachingResolver struct {
+	ttl   time.Duration
+	cache *lru.Cache[string, dnsCacheEntry]
+	group singleflight.Group
+}
+
+func NewCachingResolver(ttl time.Duration) *CachingResolver {
+	cache, _ := lru.New[string, dnsCacheEntry](maxDNSCacheEntries)
+	return &CachingResolver{
+		ttl:   ttl,
+		cache: cache,
+	}
+}
+
+func (r *CachingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	key := network + ":" + host
+	if entry, ok := r.cache.Get(key); ok && time.Now().Before(entry.expiresAt) {
+		return entry.addrs, nil
+	}
+	v, err, _ := r.group.Do(key, func() (interface{}, error) {
+		addrs, err := DefaultResolver.LookupNetIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		r.cache.Add(key, dnsCacheEntry{addrs: addrs, expiresAt: time.Now().Add(r.ttl)})
+		return addrs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]netip.Addr), nil
+}
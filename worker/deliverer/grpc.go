@@ -0,0 +1,198 @@
+package deliverer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	deliveryv1 "github.com/webhookx-io/webhookx/proto/delivery/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// grpcHTTPStatus maps gRPC status codes to the equivalent HTTP status codes
+var grpcHTTPStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// grpcPermanentCodes are the status codes indicating that the event will never be accepted,
+// the deliveries failed with them are not retried.
+var grpcPermanentCodes = map[codes.Code]bool{
+	codes.InvalidArgument:    true,
+	codes.NotFound:           true,
+	codes.AlreadyExists:      true,
+	codes.FailedPrecondition: true,
+	codes.OutOfRange:         true,
+	codes.Unimplemented:      true,
+}
+
+// GRPCDeliverer delivers via gRPC.
+// The request URL is grpc[s]://host:port, events are delivered by calling the Deliver method
+// of webhookx.delivery.v1.DeliveryService (see proto/delivery/v1/delivery.proto) with the request
+// headers as metadata.
+type GRPCDeliverer struct {
+	log            *zap.SugaredLogger
+	requestTimeout time.Duration
+	dial           dialFunc
+	acl            *ACL
+	conns          *clientCache[*grpc.ClientConn]
+}
+
+func NewGRPCDeliverer(opts Options) *GRPCDeliverer {
+	return &GRPCDeliverer{
+		log:            opts.Logger,
+		requestTimeout: opts.RequestTimeout,
+		dial:           defaultDial,
+		conns:          newClientCache(func(c *grpc.ClientConn) { _ = c.Close() }),
+	}
+}
+
+func (d *GRPCDeliverer) SetupACL(opts AclOptions) error {
+	d.acl = NewACL(opts)
+	d.dial = restrictedDialFunc(d.acl)
+	return nil
+}
+
+type grpcTarget struct {
+	addr   string
+	secure bool
+}
+
+func parseGRPCURL(s string) (*grpcTarget, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "grpc" && u.Scheme != "grpcs" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("invalid grpc url")
+	}
+	return &grpcTarget{
+		addr:   u.Host,
+		secure: u.Scheme == "grpcs",
+	}, nil
+}
+
+func (d *GRPCDeliverer) getConn(target *grpcTarget, opts *TLSOptions) (*grpc.ClientConn, error) {
+	secure := target.secure || opts != nil
+	addr := target.addr
+	if secure {
+		addr = "grpcs://" + addr
+	}
+	return d.conns.Get(clientKey(addr, opts), func() (*grpc.ClientConn, error) {
+		creds := insecure.NewCredentials()
+		if secure {
+			tlsConfig := DefaultTLSConfig
+			if opts != nil {
+				var err error
+				if tlsConfig, err = opts.Build(DefaultTLSConfig); err != nil {
+					return nil, err
+				}
+			}
+			creds = credentials.NewTLS(tlsConfig)
+		}
+		// the passthrough resolver leaves the name resolution to the dialer, which enforces the ACL
+		return grpc.NewClient("passthrough:///"+target.addr,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+				return d.dial(ctx, "tcp", addr)
+			}),
+		)
+	})
+}
+
+func (d *GRPCDeliverer) Deliver(ctx context.Context, req *Request) (res *Response) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout(req, d.requestTimeout))
+	defer cancel()
+
+	res = &Response{
+		Request: req,
+	}
+
+	target, err := parseGRPCURL(req.URL)
+	if err != nil {
+		res.setError(err)
+		return
+	}
+
+	if d.acl != nil {
+		// connections are established in the background, so the server address is checked in advance
+		if err := checkACL(ctx, d.acl, []string{target.addr}); err != nil {
+			res.setError(err)
+			return
+		}
+	}
+
+	conn, err := d.getConn(target, req.TLS)
+	if err != nil {
+		res.setError(err)
+		return
+	}
+
+	event := &deliveryv1.Event{
+		Id:         req.Headers["Webhookx-Event-Id"],
+		DeliveryId: req.Headers["Webhookx-Delivery-Id"],
+		Payload:    req.Payload,
+	}
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(messageHeaders(ctx, req)))
+
+	var header, trailer metadata.MD
+	res.Latancy = timing(func() {
+		client := deliveryv1.NewDeliveryServiceClient(conn)
+		_, err = client.Deliver(ctx, event, grpc.Header(&header), grpc.Trailer(&trailer))
+	})
+
+	if ctx.Err() != nil {
+		res.setError(ctx.Err())
+		return
+	}
+
+	st := status.Convert(err)
+	code := int(st.Code())
+	res.GRPCStatus = &code
+	res.StatusCode = grpcHTTPStatus[st.Code()]
+	res.Header = make(http.Header)
+	for name, values := range metadata.Join(header, trailer) {
+		for _, value := range values {
+			res.Header.Add(name, value)
+		}
+	}
+	if st.Code() != codes.OK {
+		res.ResponseBody = []byte(st.Message())
+		res.Permanent = grpcPermanentCodes[st.Code()]
+	}
+
+	return
+}
+
+// Close closes the cached connections
+func (d *GRPCDeliverer) Close() error {
+	d.conns.Purge()
+	return nil
+}
@@ -0,0 +1,161 @@
+package deliverer
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	deliveryv1 "github.com/webhookx-io/webhookx/proto/delivery/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type deliveryServer struct {
+	deliveryv1.UnimplementedDeliveryServiceServer
+	events   chan *deliveryv1.Event
+	metadata chan metadata.MD
+}
+
+func (s *deliveryServer) Deliver(ctx context.Context, event *deliveryv1.Event) (*deliveryv1.Ack, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.events <- event
+	s.metadata <- md
+	switch string(event.Payload) {
+	case "invalid":
+		return nil, status.Error(codes.InvalidArgument, "invalid event")
+	case "unavailable":
+		return nil, status.Error(codes.Unavailable, "try again later")
+	case "slow":
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-server", "test"))
+	return &deliveryv1.Ack{}, nil
+}
+
+func runGRPCServer(t *testing.T, opts ...grpc.ServerOption) (string, *deliveryServer) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := &deliveryServer{
+		events:   make(chan *deliveryv1.Event, 10),
+		metadata: make(chan metadata.MD, 10),
+	}
+	server := grpc.NewServer(opts...)
+	deliveryv1.RegisterDeliveryServiceServer(server, srv)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String(), srv
+}
+
+func TestGRPCDeliverer(t *testing.T) {
+	addr, server := runGRPCServer(t)
+
+	t.Run("sanity", func(t *testing.T) {
+		deliverer := NewGRPCDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		req := &Request{
+			URL:     "grpc://" + addr,
+			Payload: []byte(`{"foo": "bar"}`),
+			Headers: map[string]string{
+				"X-Key":                "value",
+				"Webhookx-Event-Id":    "event-id",
+				"Webhookx-Delivery-Id": "delivery-id",
+			},
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Success())
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, 0, *res.GRPCStatus)
+		assert.Equal(t, "test", res.Header.Get("X-Server"))
+
+		event := <-server.events
+		assert.Equal(t, "event-id", event.Id)
+		assert.Equal(t, "delivery-id", event.DeliveryId)
+		assert.Equal(t, `{"foo": "bar"}`, string(event.Payload))
+		md := <-server.metadata
+		assert.Equal(t, []string{"value"}, md.Get("x-key"))
+	})
+
+	t.Run("should map status code", func(t *testing.T) {
+		deliverer := NewGRPCDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		res := deliverer.Deliver(context.Background(), &Request{URL: "grpc://" + addr, Payload: []byte("unavailable")})
+		assert.NoError(t, res.Error)
+		assert.False(t, res.Success())
+		assert.Equal(t, 503, res.StatusCode)
+		assert.Equal(t, int(codes.Unavailable), *res.GRPCStatus)
+		assert.Equal(t, "try again later", string(res.ResponseBody))
+		assert.False(t, res.Permanent)
+
+		res = deliverer.Deliver(context.Background(), &Request{URL: "grpc://" + addr, Payload: []byte("invalid")})
+		assert.NoError(t, res.Error)
+		assert.Equal(t, 400, res.StatusCode)
+		assert.Equal(t, int(codes.InvalidArgument), *res.GRPCStatus)
+		assert.True(t, res.Permanent)
+	})
+
+	t.Run("should timeout", func(t *testing.T) {
+		deliverer := NewGRPCDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		res := deliverer.Deliver(context.Background(), &Request{
+			URL:     "grpc://" + addr,
+			Payload: []byte("slow"),
+			Timeout: time.Millisecond * 100,
+		})
+		assert.ErrorIs(t, res.Error, context.DeadlineExceeded)
+		assert.Equal(t, 0, res.StatusCode)
+	})
+
+	t.Run("should be denied by acl", func(t *testing.T) {
+		deliverer := NewGRPCDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+		assert.NoError(t, deliverer.SetupACL(AclOptions{Rules: []string{"@loopback"}}))
+
+		res := deliverer.Deliver(context.Background(), &Request{URL: "grpc://" + addr})
+		assert.ErrorIs(t, res.Error, ErrDenied)
+		assert.True(t, res.ACL.Denied)
+	})
+}
+
+func TestGRPCDelivererTLS(t *testing.T) {
+	httpServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer httpServer.Close()
+	creds := credentials.NewTLS(&tls.Config{Certificates: httpServer.TLS.Certificates})
+	addr, _ := runGRPCServer(t, grpc.Creds(creds))
+
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: httpServer.Certificate().Raw}))
+
+	t.Run("unknown authority", func(t *testing.T) {
+		deliverer := NewGRPCDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		res := deliverer.Deliver(context.Background(), &Request{URL: "grpcs://" + addr})
+		assert.False(t, res.Success())
+		assert.Equal(t, int(codes.Unavailable), *res.GRPCStatus)
+		assert.Contains(t, string(res.ResponseBody), "certificate signed by unknown authority")
+	})
+
+	t.Run("custom ca", func(t *testing.T) {
+		deliverer := NewGRPCDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		res := deliverer.Deliver(context.Background(), &Request{
+			URL: "grpcs://" + addr,
+			TLS: &TLSOptions{CACert: caCert, ServerName: "example.com"},
+		})
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Success())
+	})
+}
@@ -6,7 +6,10 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"github.com/webhookx-io/webhookx/utils"
 	"go.uber.org/zap"
 	"io"
 	"net"
@@ -24,16 +27,30 @@ type Resolver interface {
 var DefaultResolver Resolver = net.DefaultResolver
 var DefaultTLSConfig *tls.Config = nil
 
+// maxTLSClients is the maximum number of cached per-endpoint clients
+const maxTLSClients = 1000
+
 type contextKey struct{}
 
 // HTTPDeliverer delivers via HTTP
 type HTTPDeliverer struct {
-	log            *zap.SugaredLogger
-	requestTimeout time.Duration
-	client         *http.Client
+	log                 *zap.SugaredLogger
+	requestTimeout      time.Duration
+	maxResponseBodySize int64
+	// resolver resolves endpoint hosts, nil means DefaultResolver
+	resolver Resolver
+	client   *http.Client
+	// clients caches clients with custom TLS configurations, keyed on TLSOptions.Hash
+	clients *lru.Cache[string, *http.Client]
 }
 
 func restrictedDialFunc(acl *ACL) func(context.Context, string, string) (net.Conn, error) {
+	return resolvingDialFunc(nil, acl)
+}
+
+// resolvingDialFunc returns a dial function that resolves the host by the resolver (DefaultResolver if nil),
+// and dials the first IP allowed by the ACL. All IPs are allowed if acl is nil.
+func resolvingDialFunc(resolver Resolver, acl *ACL) func(context.Context, string, string) (net.Conn, error) {
 	dialer := &net.Dialer{}
 	return func(ctx context.Context, network, addr string) (net.Conn, error) {
 		host, port, err := net.SplitHostPort(addr)
@@ -41,13 +58,17 @@ func restrictedDialFunc(acl *ACL) func(context.Context, string, string) (net.Con
 			return nil, err
 		}
 
-		ips, err := DefaultResolver.LookupNetIP(ctx, "ip", host)
+		r := resolver
+		if r == nil {
+			r = DefaultResolver
+		}
+		ips, err := r.LookupNetIP(ctx, "ip", host)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, ip := range ips {
-			if acl.Allow(host, ip) {
+			if acl == nil || acl.Allow(host, ip) {
 				return dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
 			}
 		}
@@ -56,7 +77,7 @@ func restrictedDialFunc(acl *ACL) func(context.Context, string, string) (net.Con
 			res.ACL.Denied = true
 		}
 
-		return nil, fmt.Errorf("request to %s(ip=%s) is denied", addr, ips[0])
+		return nil, fmt.Errorf("request to %s(ip=%s) is %w", addr, ips[0], ErrDenied)
 	}
 }
 
@@ -71,26 +92,89 @@ type ProxyOptions struct {
 type Options struct {
 	Logger         *zap.SugaredLogger
 	RequestTimeout time.Duration
+
+	// HTTP transport options, the zero values are the defaults
+
+	// HTTP2 attempts HTTP/2 for HTTPS endpoints
+	HTTP2             bool
+	DisableKeepAlives bool
+	// MaxConnsPerHost limits the number of connections per host, zero means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout defaults to 30 seconds
+	IdleConnTimeout time.Duration
+	// DNSCacheTTL is the time DNS lookups are cached, zero disables caching
+	DNSCacheTTL time.Duration
+	// MaxResponseBodySize is the size response bodies are truncated to, zero means no limit
+	MaxResponseBodySize int64
 }
 
 func NewHTTPDeliverer(opts Options) *HTTPDeliverer {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(opts.HTTP2)
 	transport := &http.Transport{
 		MaxIdleConns:          1000,
 		MaxIdleConnsPerHost:   1000,
-		IdleConnTimeout:       30 * time.Second,
+		MaxConnsPerHost:       opts.MaxConnsPerHost,
+		IdleConnTimeout:       utils.DefaultIfZero(opts.IdleConnTimeout, 30*time.Second),
+		DisableKeepAlives:     opts.DisableKeepAlives,
 		TLSHandshakeTimeout:   5 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 		TLSClientConfig:       DefaultTLSConfig,
+		Protocols:             protocols,
 	}
 	client := &http.Client{
 		Transport: transport,
 	}
 
-	return &HTTPDeliverer{
-		log:            opts.Logger,
-		requestTimeout: opts.RequestTimeout,
-		client:         client,
+	clients, _ := lru.NewWithEvict(maxTLSClients, func(_ string, c *http.Client) {
+		c.CloseIdleConnections()
+	})
+
+	d := &HTTPDeliverer{
+		log:                 opts.Logger,
+		requestTimeout:      opts.RequestTimeout,
+		maxResponseBodySize: opts.MaxResponseBodySize,
+		client:              client,
+		clients:             clients,
+	}
+	if opts.DNSCacheTTL > 0 {
+		d.resolver = NewCachingResolver(opts.DNSCacheTTL)
+		transport.DialContext = resolvingDialFunc(d.resolver, nil)
+	}
+
+	return d
+}
+
+// getClient returns the client for the TLS options.
+// A client with its own transport is created for each distinct TLS options,
+// the transport inherits all settings (ACL, proxy) from the shared transport.
+func (d *HTTPDeliverer) getClient(opts *TLSOptions) (*http.Client, error) {
+	if opts == nil {
+		return d.client, nil
+	}
+
+	key := opts.Hash()
+	if client, ok := d.clients.Get(key); ok {
+		return client, nil
+	}
+
+	transport := d.client.Transport.(*http.Transport).Clone()
+	tlsConfig, err := opts.Build(transport.TLSClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{
+		Transport: transport,
 	}
+	d.clients.Add(key, client)
+	return client, nil
+}
+
+// Client returns the underlying HTTP client, it shares the ACL and proxy settings of the deliverer
+func (d *HTTPDeliverer) Client() *http.Client {
+	return d.client
 }
 
 func (d *HTTPDeliverer) SetupACL(opts AclOptions) error {
@@ -100,7 +184,7 @@ func (d *HTTPDeliverer) SetupACL(opts AclOptions) error {
 		return nil
 	}
 
-	transport.DialContext = restrictedDialFunc(NewACL(opts))
+	transport.DialContext = resolvingDialFunc(d.resolver, NewACL(opts))
 	d.log.Infow("ACL configured", "rule", opts.Rules)
 
 	return nil
@@ -188,6 +272,12 @@ func (d *HTTPDeliverer) Deliver(ctx context.Context, req *Request) (res *Respons
 		Request: req,
 	}
 
+	client, err := d.getClient(req.TLS)
+	if err != nil {
+		res.Error = err
+		return
+	}
+
 	ctx = context.WithValue(ctx, contextKey{}, res)
 	request, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bytes.NewBuffer(req.Payload))
 	if err != nil {
@@ -202,9 +292,10 @@ func (d *HTTPDeliverer) Deliver(ctx context.Context, req *Request) (res *Respons
 	for name, value := range req.Headers {
 		request.Header.Add(name, value)
 	}
+	tracing.InjectHeader(ctx, request.Header)
 
 	t := timing(func() {
-		response, err := d.client.Do(request)
+		response, err := client.Do(request)
 		if err != nil {
 			res.Error = err
 			return
@@ -212,12 +303,22 @@ func (d *HTTPDeliverer) Deliver(ctx context.Context, req *Request) (res *Respons
 		res.StatusCode = response.StatusCode
 		res.Header = response.Header
 
-		body, err := io.ReadAll(response.Body)
+		defer response.Body.Close()
+
+		var reader io.Reader = response.Body
+		if d.maxResponseBodySize > 0 {
+			// reads one more byte to tell whether the body is truncated
+			reader = io.LimitReader(response.Body, d.maxResponseBodySize+1)
+		}
+		body, err := io.ReadAll(reader)
 		if err != nil {
 			res.Error = err
 			return
 		}
-		response.Body.Close()
+		if d.maxResponseBodySize > 0 && int64(len(body)) > d.maxResponseBodySize {
+			body = body[:d.maxResponseBodySize]
+			res.Truncated = true
+		}
 		res.ResponseBody = body
 	})
 
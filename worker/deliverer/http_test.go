@@ -3,11 +3,16 @@ package deliverer
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -74,4 +79,152 @@ func Test(t *testing.T) {
 		assert.True(t, errors.Is(res.Error, context.DeadlineExceeded))
 	})
 
+	t.Run("should inject traceparent", func(t *testing.T) {
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10})
+
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		ctx := tracing.ContextWithTraceParent(context.Background(), traceparent)
+		res := deliverer.Deliver(ctx, &Request{URL: server.URL, Method: "POST"})
+		assert.NoError(t, res.Error)
+		data := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(res.ResponseBody, &data))
+		headers := data["headers"].(map[string]interface{})
+		assert.Equal(t, traceparent, headers["Traceparent"])
+	})
+
+	t.Run("should not inject traceparent without trace", func(t *testing.T) {
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10})
+		res := deliverer.Deliver(context.Background(), &Request{URL: server.URL, Method: "POST"})
+		assert.NoError(t, res.Error)
+		data := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(res.ResponseBody, &data))
+		headers := data["headers"].(map[string]interface{})
+		assert.Nil(t, headers["Traceparent"])
+	})
+}
+
+func TestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	t.Run("unknown authority", func(t *testing.T) {
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10})
+		res := deliverer.Deliver(context.Background(), &Request{URL: server.URL, Method: "GET"})
+		assert.Error(t, res.Error)
+		assert.Contains(t, res.Error.Error(), "certificate signed by unknown authority")
+	})
+
+	t.Run("custom ca", func(t *testing.T) {
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10})
+		req := &Request{
+			URL:    server.URL,
+			Method: "GET",
+			TLS:    &TLSOptions{CACert: caCert, ServerName: "example.com", MinVersion: "1.2"},
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.Equal(t, 200, res.StatusCode)
+
+		// cached client is reused
+		res = deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.Equal(t, 1, deliverer.clients.Len())
+	})
+
+	t.Run("invalid client certificate", func(t *testing.T) {
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10})
+		res := deliverer.Deliver(context.Background(), &Request{
+			URL:    server.URL,
+			Method: "GET",
+			TLS:    &TLSOptions{ClientCert: "invalid", ClientKey: "invalid"},
+		})
+		assert.Error(t, res.Error)
+		assert.Contains(t, res.Error.Error(), "failed to load client certificate")
+	})
+}
+
+func TestTransportOptions(t *testing.T) {
+	t.Run("should truncate response body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("0123456789"))
+		}))
+		defer server.Close()
+
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10, MaxResponseBodySize: 4})
+		res := deliverer.Deliver(context.Background(), &Request{URL: server.URL, Method: "GET"})
+		assert.NoError(t, res.Error)
+		assert.Equal(t, "0123", string(res.ResponseBody))
+		assert.True(t, res.Truncated)
+
+		deliverer = NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10, MaxResponseBodySize: 10})
+		res = deliverer.Deliver(context.Background(), &Request{URL: server.URL, Method: "GET"})
+		assert.NoError(t, res.Error)
+		assert.Equal(t, "0123456789", string(res.ResponseBody))
+		assert.False(t, res.Truncated)
+	})
+
+	t.Run("http2", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Proto))
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+		defer server.Close()
+
+		caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+		req := &Request{
+			URL:    server.URL,
+			Method: "GET",
+			TLS:    &TLSOptions{CACert: caCert, ServerName: "example.com"},
+		}
+
+		deliverer := NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10, HTTP2: true})
+		res := deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.Equal(t, "HTTP/2.0", string(res.ResponseBody))
+
+		deliverer = NewHTTPDeliverer(Options{RequestTimeout: time.Second * 10})
+		res = deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.Equal(t, "HTTP/1.1", string(res.ResponseBody))
+	})
+
+	t.Run("should cache dns lookups", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+		}))
+		defer server.Close()
+
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		resolver := &countingResolver{addr: netip.MustParseAddr("127.0.0.1")}
+		defaultResolver := DefaultResolver
+		DefaultResolver = resolver
+		defer func() { DefaultResolver = defaultResolver }()
+
+		deliverer := NewHTTPDeliverer(Options{
+			RequestTimeout:    time.Second * 10,
+			DisableKeepAlives: true,
+			DNSCacheTTL:       time.Minute,
+		})
+		for i := 0; i < 3; i++ {
+			res := deliverer.Deliver(context.Background(), &Request{URL: "http://webhookx.test:" + port, Method: "GET"})
+			assert.NoError(t, res.Error)
+			assert.Equal(t, 200, res.StatusCode)
+		}
+		assert.EqualValues(t, 1, resolver.count.Load())
+	})
+}
+
+type countingResolver struct {
+	addr  netip.Addr
+	count atomic.Int32
+}
+
+func (r *countingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	r.count.Add(1)
+	return []netip.Addr{r.addr}, nil
 }
@@ -0,0 +1,146 @@
+package deliverer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"go.uber.org/zap"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// KafkaDeliverer delivers via Kafka.
+// The request URL is kafka://broker1:9092[,broker2:9092]/topic,
+// the credentials of the request are used for SASL/PLAIN authentication.
+type KafkaDeliverer struct {
+	log            *zap.SugaredLogger
+	requestTimeout time.Duration
+	dial           dialFunc
+	acl            *ACL
+	clients        *clientCache[*kgo.Client]
+}
+
+func NewKafkaDeliverer(opts Options) *KafkaDeliverer {
+	return &KafkaDeliverer{
+		log:            opts.Logger,
+		requestTimeout: opts.RequestTimeout,
+		dial:           defaultDial,
+		clients:        newClientCache(func(c *kgo.Client) { c.Close() }),
+	}
+}
+
+func (d *KafkaDeliverer) SetupACL(opts AclOptions) error {
+	d.acl = NewACL(opts)
+	d.dial = restrictedDialFunc(d.acl)
+	return nil
+}
+
+type kafkaTarget struct {
+	brokers []string
+	topic   string
+	user    *url.Userinfo
+}
+
+func parseKafkaURL(s string, credentials *Credentials) (*kafkaTarget, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "kafka" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	topic := strings.Trim(u.Path, "/")
+	if u.Host == "" || topic == "" {
+		return nil, errors.New("invalid kafka url")
+	}
+	if credentials != nil {
+		u.User = credentials.userinfo()
+	}
+	return &kafkaTarget{
+		brokers: strings.Split(u.Host, ","),
+		topic:   topic,
+		user:    u.User,
+	}, nil
+}
+
+func (d *KafkaDeliverer) getClient(target *kafkaTarget, opts *TLSOptions) (*kgo.Client, error) {
+	addr := strings.Join(target.brokers, ",")
+	if target.user != nil {
+		addr = target.user.String() + "@" + addr
+	}
+	return d.clients.Get(clientKey(addr, opts), func() (*kgo.Client, error) {
+		dial := d.dial
+		if opts != nil {
+			tlsConfig, err := opts.Build(DefaultTLSConfig)
+			if err != nil {
+				return nil, err
+			}
+			dial = tlsDial(dial, tlsConfig)
+		}
+		kopts := []kgo.Opt{
+			kgo.SeedBrokers(target.brokers...),
+			kgo.Dialer(dial),
+		}
+		if target.user != nil {
+			password, _ := target.user.Password()
+			auth := plain.Auth{User: target.user.Username(), Pass: password}
+			kopts = append(kopts, kgo.SASL(auth.AsMechanism()))
+		}
+		return kgo.NewClient(kopts...)
+	})
+}
+
+func (d *KafkaDeliverer) Deliver(ctx context.Context, req *Request) (res *Response) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout(req, d.requestTimeout))
+	defer cancel()
+
+	res = &Response{
+		Request: req,
+	}
+
+	target, err := parseKafkaURL(req.URL, req.Credentials)
+	if err != nil {
+		res.setError(err)
+		return
+	}
+
+	if d.acl != nil {
+		// brokers are dialed in the background, so seed brokers are checked in advance
+		if err := checkACL(ctx, d.acl, target.brokers); err != nil {
+			res.setError(err)
+			return
+		}
+	}
+
+	client, err := d.getClient(target, req.TLS)
+	if err != nil {
+		res.setError(err)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic: target.topic,
+		Value: req.Payload,
+	}
+	headers := messageHeaders(ctx, req)
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: name, Value: []byte(headers[name])})
+	}
+
+	res.Latancy = timing(func() {
+		res.setError(client.ProduceSync(ctx, record).FirstErr())
+	})
+
+	return
+}
+
+// Close closes the cached clients
+func (d *KafkaDeliverer) Close() error {
+	d.clients.Purge()
+	return nil
+}
@@ -0,0 +1,91 @@
+package deliverer
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKafkaDeliverer(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "events"))
+	assert.NoError(t, err)
+	defer cluster.Close()
+
+	brokers := strings.Join(cluster.ListenAddrs(), ",")
+
+	t.Run("sanity", func(t *testing.T) {
+		deliverer := NewKafkaDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		req := &Request{
+			URL:     "kafka://" + brokers + "/events",
+			Method:  "POST",
+			Payload: []byte(`{"foo": "bar"}`),
+			Headers: map[string]string{
+				"X-Key": "value",
+			},
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.Equal(t, 0, res.StatusCode)
+		assert.True(t, res.Success())
+
+		client, err := kgo.NewClient(
+			kgo.SeedBrokers(cluster.ListenAddrs()...),
+			kgo.ConsumeTopics("events"),
+			kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		)
+		assert.NoError(t, err)
+		defer client.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		fetches := client.PollFetches(ctx)
+		assert.NoError(t, fetches.Err())
+		records := fetches.Records()
+		assert.Len(t, records, 1)
+		assert.Equal(t, `{"foo": "bar"}`, string(records[0].Value))
+		assert.Contains(t, records[0].Headers, kgo.RecordHeader{Key: "X-Key", Value: []byte("value")})
+	})
+
+	t.Run("should fail when topic does not exist", func(t *testing.T) {
+		deliverer := NewKafkaDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		req := &Request{
+			URL:     "kafka://" + brokers + "/unknown",
+			Payload: []byte(`{"foo": "bar"}`),
+			Timeout: time.Second,
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.Error(t, res.Error)
+		assert.False(t, res.Success())
+	})
+
+	t.Run("should fail with invalid url", func(t *testing.T) {
+		deliverer := NewKafkaDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		res := deliverer.Deliver(context.Background(), &Request{URL: "kafka://" + brokers})
+		assert.EqualError(t, res.Error, "invalid kafka url")
+	})
+
+	t.Run("should be denied by acl", func(t *testing.T) {
+		deliverer := NewKafkaDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+		assert.NoError(t, deliverer.SetupACL(AclOptions{Rules: []string{"@loopback"}}))
+
+		req := &Request{
+			URL:     "kafka://" + brokers + "/events",
+			Payload: []byte(`{"foo": "bar"}`),
+			Timeout: time.Second,
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.ErrorIs(t, res.Error, ErrDenied)
+		assert.True(t, res.ACL.Denied)
+	})
+}
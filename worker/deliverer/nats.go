@@ -0,0 +1,162 @@
+package deliverer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NATSDeliverer delivers via NATS.
+// The request URL is nats://host:4222[,host2:4222]/subject, the credentials of the request are used for authentication.
+// Messages are published to JetStream and are delivered once they are acknowledged by the stream when the query jetstream=true is present.
+type NATSDeliverer struct {
+	log            *zap.SugaredLogger
+	requestTimeout time.Duration
+	dial           dialFunc
+	connections    *clientCache[*nats.Conn]
+}
+
+func NewNATSDeliverer(opts Options) *NATSDeliverer {
+	return &NATSDeliverer{
+		log:            opts.Logger,
+		requestTimeout: opts.RequestTimeout,
+		dial:           defaultDial,
+		connections:    newClientCache(func(c *nats.Conn) { c.Close() }),
+	}
+}
+
+func (d *NATSDeliverer) SetupACL(opts AclOptions) error {
+	d.dial = restrictedDialFunc(NewACL(opts))
+	return nil
+}
+
+// natsDialer dials the NATS servers, including the reconnections
+type natsDialer struct {
+	dial dialFunc
+}
+
+func (d natsDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dial(context.Background(), network, address)
+}
+
+type natsTarget struct {
+	servers   string
+	subject   string
+	jetstream bool
+}
+
+func parseNATSURL(s string, credentials *Credentials) (*natsTarget, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	subject := strings.Trim(u.Path, "/")
+	if u.Host == "" || subject == "" {
+		return nil, errors.New("invalid nats url")
+	}
+	if credentials != nil {
+		u.User = credentials.userinfo()
+	}
+	servers := strings.Split(u.Host, ",")
+	for i, server := range servers {
+		servers[i] = (&url.URL{Scheme: u.Scheme, User: u.User, Host: server}).String()
+	}
+	return &natsTarget{
+		servers:   strings.Join(servers, ","),
+		subject:   subject,
+		jetstream: u.Query().Get("jetstream") == "true",
+	}, nil
+}
+
+func (d *NATSDeliverer) getConnection(target *natsTarget, opts *TLSOptions, timeout time.Duration) (*nats.Conn, error) {
+	key := clientKey(target.servers, opts)
+	connect := func() (*nats.Conn, error) {
+		nopts := []nats.Option{
+			nats.Name("webhookx"),
+			nats.Timeout(timeout),
+			nats.SetCustomDialer(natsDialer{dial: d.dial}),
+		}
+		if opts != nil {
+			tlsConfig, err := opts.Build(DefaultTLSConfig)
+			if err != nil {
+				return nil, err
+			}
+			nopts = append(nopts, nats.Secure(tlsConfig))
+		}
+		return nats.Connect(target.servers, nopts...)
+	}
+
+	conn, err := d.connections.Get(key, connect)
+	if err == nil && conn.IsClosed() {
+		// the connection was closed after exhausting reconnections
+		d.connections.Remove(key)
+		conn, err = d.connections.Get(key, connect)
+	}
+	return conn, err
+}
+
+func (d *NATSDeliverer) publish(ctx context.Context, req *Request, target *natsTarget, timeout time.Duration) error {
+	conn, err := d.getConnection(target, req.TLS, timeout)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(target.subject)
+	msg.Data = req.Payload
+	for name, value := range messageHeaders(ctx, req) {
+		msg.Header.Set(name, value)
+	}
+
+	if target.jetstream {
+		js, err := jetstream.New(conn)
+		if err != nil {
+			return err
+		}
+		_, err = js.PublishMsg(ctx, msg)
+		return err
+	}
+
+	if err := conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// the message is delivered once the server has processed it
+	return conn.FlushWithContext(ctx)
+}
+
+func (d *NATSDeliverer) Deliver(ctx context.Context, req *Request) (res *Response) {
+	timeout := requestTimeout(req, d.requestTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res = &Response{
+		Request: req,
+	}
+
+	target, err := parseNATSURL(req.URL, req.Credentials)
+	if err != nil {
+		res.setError(err)
+		return
+	}
+
+	res.Latancy = timing(func() {
+		res.setError(d.publish(ctx, req, target, timeout))
+	})
+
+	return
+}
+
+// Close closes the cached connections
+func (d *NATSDeliverer) Close() error {
+	d.connections.Purge()
+	return nil
+}
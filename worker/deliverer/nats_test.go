@@ -0,0 +1,117 @@
+package deliverer
+
+import (
+	"context"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func runNATSServer(t *testing.T) *server.Server {
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	assert.NoError(t, err)
+	go s.Start()
+	if !s.ReadyForConnections(time.Second * 5) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func TestNATSDeliverer(t *testing.T) {
+	s := runNATSServer(t)
+
+	conn, err := nats.Connect(s.ClientURL())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	t.Run("sanity", func(t *testing.T) {
+		deliverer := NewNATSDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		sub, err := conn.SubscribeSync("webhookx.events")
+		assert.NoError(t, err)
+		defer sub.Unsubscribe()
+		assert.NoError(t, conn.Flush())
+
+		req := &Request{
+			URL:     s.ClientURL() + "/webhookx.events",
+			Payload: []byte(`{"foo": "bar"}`),
+			Headers: map[string]string{
+				"X-Key": "value",
+			},
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Success())
+
+		msg, err := sub.NextMsg(time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"foo": "bar"}`, string(msg.Data))
+		assert.Equal(t, "value", msg.Header.Get("X-Key"))
+	})
+
+	t.Run("jetstream", func(t *testing.T) {
+		deliverer := NewNATSDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		js, err := jetstream.New(conn)
+		assert.NoError(t, err)
+		stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+			Name:     "webhookx",
+			Subjects: []string{"stream.>"},
+		})
+		assert.NoError(t, err)
+
+		req := &Request{
+			URL:     s.ClientURL() + "/stream.events?jetstream=true",
+			Payload: []byte(`{"foo": "bar"}`),
+		}
+		res := deliverer.Deliver(context.Background(), req)
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Success())
+
+		msg, err := stream.GetLastMsgForSubject(context.Background(), "stream.events")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"foo": "bar"}`, string(msg.Data))
+
+		t.Run("should fail when no stream is bound to the subject", func(t *testing.T) {
+			req := &Request{
+				URL:     s.ClientURL() + "/unknown.events?jetstream=true",
+				Payload: []byte(`{"foo": "bar"}`),
+			}
+			res := deliverer.Deliver(context.Background(), req)
+			assert.Error(t, res.Error)
+			assert.False(t, res.Success())
+		})
+	})
+
+	t.Run("should fail when server is unavailable", func(t *testing.T) {
+		deliverer := NewNATSDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+
+		res := deliverer.Deliver(context.Background(), &Request{URL: "nats://127.0.0.1:1/webhookx.events"})
+		assert.Error(t, res.Error)
+		assert.False(t, res.Success())
+	})
+
+	t.Run("should be denied by acl", func(t *testing.T) {
+		deliverer := NewNATSDeliverer(Options{RequestTimeout: time.Second * 10})
+		defer deliverer.Close()
+		assert.NoError(t, deliverer.SetupACL(AclOptions{Rules: []string{"@loopback"}}))
+
+		res := deliverer.Deliver(context.Background(), &Request{URL: s.ClientURL() + "/webhookx.events"})
+		assert.ErrorIs(t, res.Error, ErrDenied)
+		assert.True(t, res.ACL.Denied)
+	})
+}
@@ -0,0 +1,61 @@
+package deliverer
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Hash returns the hash of options, used as the cache key of transports
+func (opts *TLSOptions) Hash() string {
+	b, _ := json.Marshal(opts)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Build builds a tls.Config based on the base config
+func (opts *TLSOptions) Build(base *tls.Config) (*tls.Config, error) {
+	var config *tls.Config
+	if base != nil {
+		config = base.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(opts.ClientCert), []byte(opts.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if opts.CACert != "" {
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM([]byte(opts.CACert)) {
+			return nil, fmt.Errorf("failed to append ca certificate to pool")
+		}
+		config.RootCAs = cp
+	}
+	if opts.ServerName != "" {
+		config.ServerName = opts.ServerName
+	}
+	if opts.MinVersion != "" {
+		version, ok := tlsVersions[opts.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls version '%s'", opts.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	return config, nil
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/webhookx-io/webhookx/constants"
+	"github.com/webhookx-io/webhookx/db/dao"
+	"github.com/webhookx-io/webhookx/db/entities"
+	"github.com/webhookx-io/webhookx/pkg/tracing"
+	"github.com/webhookx-io/webhookx/pkg/types"
+	"github.com/webhookx-io/webhookx/utils"
+	"time"
+)
+
+// EndpointHealthEventData is the data of the endpoint health system events
+type EndpointHealthEventData struct {
+	Endpoint EndpointSummary          `json:"endpoint"`
+	Health   *entities.EndpointHealth `json:"health"`
+}
+
+type EndpointSummary struct {
+	ID   string  `json:"id"`
+	Name *string `json:"name"`
+	URL  string  `json:"url"`
+}
+
+// nextHealthStatus returns the status the endpoint transitions to, or an empty string if it stays unchanged.
+func nextHealthStatus(health *entities.EndpointHealth, threshold int64) entities.EndpointHealthStatus {
+	switch health.Status {
+	case entities.EndpointHealthStatusHealthy:
+		if health.ConsecutiveFailures >= threshold {
+			return entities.EndpointHealthStatusFailing
+		}
+	case entities.EndpointHealthStatusFailing:
+		if health.ConsecutiveFailures == 0 {
+			return entities.EndpointHealthStatusHealthy
+		}
+	}
+	return ""
+}
+
+// trackHealth records the attempt result into the endpoint health, and dispatches a system event
+// into the endpoint's workspace when the endpoint starts failing or recovers.
+func (w *Worker) trackHealth(ctx context.Context, endpoint *entities.Endpoint, result *dao.AttemptResult) {
+	success := result.Status == entities.AttemptStatusSuccess
+	health, err := w.db.EndpointHealth.Record(ctx, endpoint, success, result.AttemptedAt.Time)
+	if err != nil {
+		w.log.Warnf("failed to record endpoint health: %v", err)
+		return
+	}
+
+	status := nextHealthStatus(health, int64(w.opts.HealthFailureThreshold))
+	if status == "" {
+		return
+	}
+	ok, err := w.db.EndpointHealth.UpdateStatus(ctx, endpoint.ID, health.Status, status)
+	if err != nil {
+		w.log.Warnf("failed to update endpoint health status: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	health.Status = status
+
+	eventType := constants.EventTypeEndpointFailing
+	if status == entities.EndpointHealthStatusHealthy {
+		eventType = constants.EventTypeEndpointRecovered
+	}
+	w.log.Infof("endpoint %s health changed to %s", endpoint.ID, status)
+
+	if err := w.dispatchHealthEvent(ctx, endpoint, eventType, health); err != nil {
+		w.log.Errorf("failed to dispatch %s event: %v", eventType, err)
+	}
+}
+
+func (w *Worker) dispatchHealthEvent(ctx context.Context, endpoint *entities.Endpoint, eventType string, health *entities.EndpointHealth) error {
+	data, err := json.Marshal(EndpointHealthEventData{
+		Endpoint: EndpointSummary{
+			ID:   endpoint.ID,
+			Name: endpoint.Name,
+			URL:  endpoint.Request.URL,
+		},
+		Health: health,
+	})
+	if err != nil {
+		return err
+	}
+
+	event := &entities.Event{
+		ID:          utils.KSUID(),
+		EventType:   eventType,
+		Data:        data,
+		IngestedAt:  types.NewTime(time.Now()),
+		TraceParent: tracing.TraceParent(ctx),
+	}
+	event.WorkspaceId = endpoint.WorkspaceId
+
+	// the endpoint is not notified of its own health, or failed deliveries of the event would count as its failures
+	attempts, err := w.dispatcher.DispatchExcluding(ctx, []*entities.Event{event}, endpoint.ID)
+	if err != nil {
+		return err
+	}
+	w.srv.ScheduleAttempts(ctx, attempts)
+	return nil
+}
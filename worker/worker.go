@@ -13,24 +13,32 @@ import (
 	"github.com/webhookx-io/webhookx/db/dao"
 	"github.com/webhookx-io/webhookx/db/entities"
 	"github.com/webhookx-io/webhookx/db/query"
+	"github.com/webhookx-io/webhookx/dispatcher"
 	"github.com/webhookx-io/webhookx/eventbus"
 	"github.com/webhookx-io/webhookx/mcache"
+	"github.com/webhookx-io/webhookx/pkg/livetail"
 	"github.com/webhookx-io/webhookx/pkg/metrics"
 	"github.com/webhookx-io/webhookx/pkg/plugin"
 	"github.com/webhookx-io/webhookx/pkg/pool"
 	"github.com/webhookx-io/webhookx/pkg/ratelimiter"
 	"github.com/webhookx-io/webhookx/pkg/schedule"
+	"github.com/webhookx-io/webhookx/pkg/semaphore"
 	"github.com/webhookx-io/webhookx/pkg/stats"
 	"github.com/webhookx-io/webhookx/pkg/taskqueue"
 	"github.com/webhookx-io/webhookx/pkg/tracing"
 	"github.com/webhookx-io/webhookx/pkg/types"
 	"github.com/webhookx-io/webhookx/service"
 	"github.com/webhookx-io/webhookx/utils"
+	"github.com/webhookx-io/webhookx/worker/auth"
 	"github.com/webhookx-io/webhookx/worker/deliverer"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"io"
 	"maps"
+	"net/http"
 	"runtime"
+	"slices"
+	"strconv"
 	"sync/atomic"
 	"time"
 )
@@ -41,7 +49,10 @@ var (
 	processing atomic.Int64
 )
 
-var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+var (
+	ErrRateLimitExceeded        = errors.New("rate limit exceeded")
+	ErrConcurrencyLimitExceeded = errors.New("concurrency limit exceeded")
+)
 
 type Worker struct {
 	ctx    context.Context
@@ -51,13 +62,17 @@ type Worker struct {
 
 	log *zap.SugaredLogger
 
-	deliverer   deliverer.Deliverer
-	db          *db.DB
-	tracer      *tracing.Tracer
-	pool        *pool.Pool
-	metrics     *metrics.Metrics
-	srv         *service.Service
-	rateLimiter ratelimiter.RateLimiter
+	deliverers    map[entities.EndpointType]deliverer.Deliverer
+	authenticator *auth.Authenticator
+	db            *db.DB
+	tracer        *tracing.Tracer
+	pool          *pool.Pool
+	metrics       *metrics.Metrics
+	srv           *service.Service
+	dispatcher    *dispatcher.Dispatcher
+	tail          *livetail.Tail
+	rateLimiter   ratelimiter.RateLimiter
+	semaphore     semaphore.Semaphore
 }
 
 type Options struct {
@@ -66,13 +81,28 @@ type Options struct {
 	PoolSize           int
 	PoolConcurrency    int
 
-	DB          *db.DB
-	Deliverer   deliverer.Deliverer
-	Metrics     *metrics.Metrics
-	Tracer      *tracing.Tracer
-	EventBus    eventbus.Bus
-	Srv         *service.Service
-	RedisClient *redis.Client
+	// DeliveryTimeout is the timeout of deliveries to endpoints without a timeout
+	DeliveryTimeout time.Duration
+	// PluginTimeout is the maximum execution time of a plugin phase
+	PluginTimeout time.Duration
+
+	// HealthFailureThreshold is the number of consecutive failures after which an endpoint is failing,
+	// endpoint health tracking is disabled if it is zero.
+	HealthFailureThreshold int
+
+	DB            *db.DB
+	Deliverer     deliverer.Deliverer
+	Authenticator *auth.Authenticator
+	Metrics       *metrics.Metrics
+	Tracer        *tracing.Tracer
+	EventBus      eventbus.Bus
+	Srv           *service.Service
+	Dispatcher    *dispatcher.Dispatcher
+	Tail          *livetail.Tail
+	RedisClient   *redis.Client
+
+	// Deliverers are the deliverers of endpoint types other than http, Deliverer is used for http endpoints
+	Deliverers map[entities.EndpointType]deliverer.Deliverer
 }
 
 func init() {
@@ -90,22 +120,32 @@ func NewWorker(opts Options) *Worker {
 	opts.RequeueJobInterval = utils.DefaultIfZero(opts.RequeueJobInterval, constants.RequeueInterval)
 	opts.PoolSize = utils.DefaultIfZero(opts.PoolSize, 10000)
 	opts.PoolConcurrency = utils.DefaultIfZero(opts.PoolConcurrency, runtime.NumCPU()*100)
+	if opts.Authenticator == nil {
+		opts.Authenticator = auth.NewAuthenticator(auth.Options{})
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	worker := &Worker{
-		ctx:         ctx,
-		cancel:      cancel,
-		opts:        opts,
-		log:         zap.S().Named("worker"),
-		deliverer:   opts.Deliverer,
-		db:          opts.DB,
-		pool:        pool.NewPool(opts.PoolSize, opts.PoolConcurrency),
-		metrics:     opts.Metrics,
-		tracer:      opts.Tracer,
-		srv:         opts.Srv,
-		rateLimiter: ratelimiter.NewRedisLimiter(opts.RedisClient),
+		ctx:           ctx,
+		cancel:        cancel,
+		opts:          opts,
+		log:           zap.S().Named("worker"),
+		deliverers:    make(map[entities.EndpointType]deliverer.Deliverer),
+		authenticator: opts.Authenticator,
+		db:            opts.DB,
+		pool:          pool.NewPool(opts.PoolSize, opts.PoolConcurrency),
+		metrics:       opts.Metrics,
+		tracer:        opts.Tracer,
+		srv:           opts.Srv,
+		dispatcher:    opts.Dispatcher,
+		tail:          opts.Tail,
+		rateLimiter:   ratelimiter.NewRedisLimiter(opts.RedisClient),
+		semaphore:     semaphore.NewRedisSemaphore(opts.RedisClient, constants.ConcurrencyKeyPrefix),
 	}
 
+	maps.Copy(worker.deliverers, opts.Deliverers)
+	worker.deliverers[entities.EndpointTypeHTTP] = opts.Deliverer
+
 	worker.registerEventHandler(opts.EventBus)
 
 	return worker
@@ -114,14 +154,23 @@ func NewWorker(opts Options) *Worker {
 func (w *Worker) registerEventHandler(bus eventbus.Bus) {
 	rs := redsync.New(goredis.NewPool(w.opts.RedisClient))
 	bus.Subscribe("plugin.crud", func(data interface{}) {
-		plugin := entities.Plugin{}
-		if err := json.Unmarshal(data.(*eventbus.CrudData).Data, &plugin); err != nil {
-			w.log.Errorf("failed to unmarshal event data: %s", err)
-			return
+		crud := data.(*eventbus.CrudData)
+		var keys []string
+		for _, raw := range []json.RawMessage{crud.Data, crud.Previous} {
+			if len(raw) == 0 {
+				continue
+			}
+			plugin := entities.Plugin{}
+			if err := json.Unmarshal(raw, &plugin); err != nil {
+				w.log.Errorf("failed to unmarshal event data: %s", err)
+				return
+			}
+			// the previous scope is invalidated as well when a plugin is moved
+			if key := pluginsCacheKey(&plugin, crud.WID); key != "" && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
 		}
-
-		if plugin.EndpointId != nil {
-			cacheKey := constants.EndpointPluginsKey.Build(*plugin.EndpointId)
+		for _, cacheKey := range keys {
 			err := mcache.Invalidate(context.TODO(), cacheKey)
 			if err != nil {
 				w.log.Errorf("failed to invalidate cache: key=%s %v", cacheKey, err)
@@ -206,24 +255,27 @@ func (w *Worker) run() {
 						processing.Add(1)
 						defer processing.Add(-1)
 
-						// TODO: start trace with task Context
 						ctx := context.TODO()
-						if w.tracer != nil {
-							tracingCtx, span := w.tracer.Start(ctx, "worker.submit", trace.WithSpanKind(trace.SpanKindServer))
-							defer span.End()
-							ctx = tracingCtx
-						}
-						task.Data = &taskqueue.MessageData{}
-						err = task.UnmarshalData(task.Data)
+						data := &taskqueue.MessageData{}
+						task.Data = data
+						err := task.UnmarshalData(data)
 						if err != nil {
 							w.log.Errorf("failed to unmarshal task: %v", err)
 							_ = w.srv.DeleteTask(ctx, task)
 							return
 						}
 
+						if w.tracer != nil {
+							// continue the trace started at ingestion
+							ctx = tracing.ContextWithTraceParent(ctx, data.TraceParent)
+							tracingCtx, span := w.tracer.Start(ctx, "worker.submit", trace.WithSpanKind(trace.SpanKindServer))
+							defer span.End()
+							ctx = tracingCtx
+						}
+
 						err = w.handleTask(ctx, task)
 						if err != nil {
-							if errors.Is(ErrRateLimitExceeded, err) {
+							if errors.Is(err, ErrRateLimitExceeded) || errors.Is(err, ErrConcurrencyLimitExceeded) {
 								return
 							}
 							// TODO: delete task when causes error too many times (maxReceiveCount)
@@ -259,6 +311,7 @@ func (w *Worker) Start() error {
 	go w.run()
 
 	schedule.ScheduleWithoutDelay(w.ctx, w.ProcessRequeue, w.opts.RequeueJobInterval)
+	schedule.ScheduleWithoutDelay(w.ctx, w.ProcessRollup, constants.AnalyticsRollupInterval)
 	return nil
 }
 
@@ -268,11 +321,28 @@ func (w *Worker) Stop() error {
 	w.log.Named("pool").Infow("closing pool", "handling", w.pool.GetHandling())
 	w.pool.Shutdown()
 	w.log.Named("pool").Info("closed pool")
+	for _, d := range w.deliverers {
+		if closer, ok := d.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
 	w.log.Info("worker stopped")
 
 	return nil
 }
 
+// ProcessRollup re-aggregates recently attempted attempts into attempt stats
+func (w *Worker) ProcessRollup() {
+	since := time.Now().Add(-constants.AnalyticsRollupWindow)
+	err := w.db.TX(context.TODO(), func(ctx context.Context) error {
+		_, err := w.db.AttemptStats.Rollup(ctx, since)
+		return err
+	})
+	if err != nil {
+		w.log.Errorf("failed to rollup attempt stats: %v", err)
+	}
+}
+
 func (w *Worker) ProcessRequeue() {
 	batchSize := w.opts.RequeueJobBatch
 
@@ -332,23 +402,12 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 		return w.db.Attempts.UpdateErrorCode(ctx, task.ID, entities.AttemptStatusCanceled, entities.AttemptErrorCodeEndpointNotFound)
 	}
 	if !endpoint.Enabled {
-		return w.db.Attempts.UpdateErrorCode(ctx, task.ID, entities.AttemptStatusCanceled, entities.AttemptErrorCodeEndpointDisabled)
+		return w.cancelAttempt(ctx, task, endpoint, entities.AttemptErrorCodeEndpointDisabled)
 	}
-	if endpoint.RateLimit != nil {
-		d := time.Duration(endpoint.RateLimit.Period) * time.Second
-		res, err := w.rateLimiter.Allow(ctx, endpoint.ID, endpoint.RateLimit.Quota, d)
-		if err != nil {
-			return err
-		}
-		if !res.Allowed {
-			task.ScheduledAt = time.Now().Add(d)
-			w.log.Debugw("rate limit exceeded", "endpoint", endpoint.ID, "task", task.ID, "next", task.ScheduledAt)
-			err := w.srv.ScheduleTask(ctx, task)
-			if err != nil {
-				return err
-			}
-			return ErrRateLimitExceeded
-		}
+	d := w.deliverers[utils.DefaultIfZero(endpoint.Type, entities.EndpointTypeHTTP)]
+	if d == nil {
+		w.log.Warnf("no deliverer for endpoint type '%s'", endpoint.Type)
+		return w.cancelAttempt(ctx, task, endpoint, entities.AttemptErrorCodeUnknown)
 	}
 
 	if data.Event == "" { // backward compatibility
@@ -360,16 +419,56 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 			return err
 		}
 		if event == nil {
-			return w.db.Attempts.UpdateErrorCode(ctx, task.ID, entities.AttemptStatusCanceled, entities.AttemptErrorCodeUnknown)
+			return w.cancelAttempt(ctx, task, endpoint, entities.AttemptErrorCodeUnknown)
 		}
 		data.Event = string(event.Data)
+		data.EventType = event.EventType
 	}
 
-	plugins, err := listEndpointPlugins(ctx, w.db, endpoint.ID)
+	plugins, err := listEndpointPlugins(ctx, w.db, endpoint)
 	if err != nil {
 		return err
 	}
 
+	// the concurrency permit is acquired first, so that the tasks requeued for concurrency do not consume rate limit quota
+	if endpoint.MaxConcurrency != nil {
+		acquired, err := w.semaphore.Acquire(ctx, endpoint.ID, task.ID, *endpoint.MaxConcurrency, w.permitTTL(endpoint, len(plugins)))
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			task.ScheduledAt = time.Now().Add(constants.ConcurrencyLimitDelay)
+			w.log.Debugw("concurrency limit exceeded", "endpoint", endpoint.ID, "task", task.ID, "next", task.ScheduledAt)
+			err := w.srv.ScheduleTask(ctx, task)
+			if err != nil {
+				return err
+			}
+			return ErrConcurrencyLimitExceeded
+		}
+		defer func() {
+			if err := w.semaphore.Release(context.WithoutCancel(ctx), endpoint.ID, task.ID); err != nil {
+				w.log.Warnf("failed to release concurrency permit: %v", err)
+			}
+		}()
+	}
+
+	if endpoint.RateLimit != nil {
+		d := time.Duration(endpoint.RateLimit.Period) * time.Second
+		res, err := w.rateLimiter.Allow(ctx, endpoint.ID, endpoint.RateLimit.Quota, d)
+		if err != nil {
+			return err
+		}
+		if !res.Allowed {
+			task.ScheduledAt = time.Now().Add(d)
+			w.log.Debugw("rate limit exceeded", "endpoint", endpoint.ID, "task", task.ID, "next", task.ScheduledAt)
+			err := w.srv.ScheduleTask(ctx, task)
+			if err != nil {
+				return err
+			}
+			return ErrRateLimitExceeded
+		}
+	}
+
 	cacheKey = constants.WorkspaceCacheKey.Build(endpoint.WorkspaceId)
 	//workspace, err := mcache.Load(ctx, cacheKey, nil, w.DB.Workspaces.Get, endpoint.WorkspaceId)
 	//if err != nil {
@@ -385,17 +484,60 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 	maps.Copy(outbound.Headers, endpoint.Request.Headers)
 	pluginCtx := &plugin.Context{
 		//Workspace: workspace,
+		Event: &plugin.Event{
+			ID:        data.EventID,
+			EventType: data.EventType,
+		},
+		Endpoint: &plugin.Endpoint{
+			ID:       endpoint.ID,
+			Name:     utils.PointerValue(endpoint.Name),
+			Metadata: endpoint.Metadata,
+		},
+		Attempt: &plugin.Attempt{
+			ID:     task.ID,
+			Number: data.Attempt,
+		},
 	}
+	var responsePlugins []*responsePlugin
 	for _, p := range plugins {
-		executor, err := p.Plugin()
+		matched, err := p.MatchOutbound(&outbound, pluginCtx)
+		if err != nil {
+			if e, ok := plugin.AsLimitError(err); ok {
+				w.log.Warnf("condition of plugin %s exceeded the resource limit: %v", p.Name, err)
+				if w.metrics.Enabled {
+					w.metrics.PluginLimitExceededCounter.With("plugin", p.Name, "limit", e.Limit).Add(1)
+				}
+				return w.cancelAttempt(ctx, task, endpoint, entities.AttemptErrorCodePluginLimit)
+			}
+			return fmt.Errorf("failed to match %s plugin: %v", p.Name, err)
+		}
+		if !matched {
+			continue
+		}
+
+		executor, err := p.OutboundExecutor(ctx)
 		if err != nil {
 			return err
 		}
 
 		err = executor.ExecuteOutbound(&outbound, pluginCtx)
 		if err != nil {
+			if e, ok := plugin.AsLimitError(err); ok {
+				w.log.Warnf("plugin %s exceeded the resource limit: %v", p.Name, err)
+				if w.metrics.Enabled {
+					w.metrics.PluginLimitExceededCounter.With("plugin", p.Name, "limit", e.Limit).Add(1)
+				}
+				return w.cancelAttempt(ctx, task, endpoint, entities.AttemptErrorCodePluginLimit)
+			}
 			return fmt.Errorf("failed to execute %s plugin: %v", p.Name, err)
 		}
+		if outbound.Dropped {
+			w.log.Debugf("delivery %s is dropped by %s plugin", task.ID, p.Name)
+			return w.cancelAttempt(ctx, task, endpoint, entities.AttemptErrorCodePluginDropped)
+		}
+		if rp, ok := executor.(plugin.ResponsePlugin); ok {
+			responsePlugins = append(responsePlugins, &responsePlugin{name: p.Name, executor: rp})
+		}
 	}
 
 	outbound.Headers["Webhookx-Event-Id"] = data.EventID
@@ -409,12 +551,28 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 		Headers: outbound.Headers,
 		Timeout: time.Duration(endpoint.Request.Timeout) * time.Millisecond,
 	}
+	if cfg := endpoint.Request.TLS; cfg != nil {
+		request.TLS = &deliverer.TLSOptions{
+			ClientCert: cfg.ClientCert,
+			ClientKey:  cfg.ClientKey,
+			CACert:     cfg.CACert,
+			ServerName: cfg.ServerName,
+			MinVersion: cfg.MinVersion,
+		}
+	}
+
+	authConfig := endpoint.Request.Auth
+	if endpoint.Type.Broker() && authConfig != nil && authConfig.Basic != nil {
+		request.Credentials = &deliverer.Credentials{
+			Username: authConfig.Basic.Username,
+			Password: authConfig.Basic.Password,
+		}
+		authConfig = nil
+	}
 
 	// deliver the request
 	startAt := time.Now()
-	ctx, span := tracing.Start(ctx, "worker.deliver", trace.WithSpanKind(trace.SpanKindClient))
-	response := w.deliverer.Deliver(ctx, request)
-	span.End()
+	response := w.deliver(ctx, d, authConfig, request)
 	finishAt := time.Now()
 
 	if response.Error != nil {
@@ -423,9 +581,11 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 	w.log.Debugf("delivery response: %v", response)
 
 	result := buildAttemptResult(request, response)
+	if len(responsePlugins) > 0 {
+		w.executeResponsePlugins(responsePlugins, response, result, pluginCtx)
+	}
 	result.AttemptedAt = types.NewTime(startAt)
-	result.Exhausted = data.Attempt >= len(endpoint.Retry.Config.Attempts)
-	if response.ACL.Denied {
+	if data.Attempt >= len(endpoint.Retry.Config.Attempts) {
 		result.Exhausted = true
 	}
 
@@ -435,23 +595,42 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 	}
 
 	if w.metrics.Enabled {
-		w.metrics.AttemptTotalCounter.Add(1)
+		labels := []string{
+			"workspace", endpoint.WorkspaceId,
+			"endpoint", endpoint.ID,
+			"status_class", statusClass(response.StatusCode),
+			"error_code", "none",
+		}
+		if result.ErrorCode != nil {
+			labels[7] = string(*result.ErrorCode)
+		}
+		w.metrics.AttemptTotalCounter.With(labels...).Add(1)
 		if result.Status == entities.AttemptStatusFailure {
-			w.metrics.AttemptFailedCounter.Add(1)
+			w.metrics.AttemptFailedCounter.With(labels...).Add(1)
 		}
-		w.metrics.AttemptResponseDurationHistogram.Observe(response.Latancy.Seconds())
+		w.metrics.AttemptResponseDurationHistogram.With(labels[:6]...).Observe(response.Latancy.Seconds())
 	}
 
 	err = w.db.Attempts.UpdateDelivery(ctx, task.ID, result)
 	if err != nil {
 		return err
 	}
+	w.publishAttempt(task, endpoint, result.Status, result.ErrorCode, &result.AttemptedAt)
+
+	if w.opts.HealthFailureThreshold > 0 {
+		w.trackHealth(ctx, endpoint, result)
+	}
 
 	go func() {
 		attemptDetail := &entities.AttemptDetail{
-			ID:             task.ID,
-			RequestHeaders: utils.HeaderMap(request.Request.Header),
-			RequestBody:    utils.Pointer(string(request.Payload)),
+			ID:          task.ID,
+			RequestBody: utils.Pointer(string(request.Payload)),
+		}
+		// the request headers carry the endpoint credentials, which must not be stored in plaintext
+		if request.Request != nil {
+			attemptDetail.RequestHeaders = entities.RedactHeaders(utils.HeaderMap(request.Request.Header))
+		} else if endpoint.Type != entities.EndpointTypeHTTP {
+			attemptDetail.RequestHeaders = entities.RedactHeaders(request.Headers)
 		}
 		if len(response.Header) > 0 {
 			attemptDetail.ResponseHeaders = utils.Pointer(entities.Headers(utils.HeaderMap(response.Header)))
@@ -484,7 +663,7 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 		AttemptNumber: data.Attempt + 1,
 		ScheduledAt:   types.NewTime(finishAt.Add(time.Second * time.Duration(delay))),
 		TriggerMode:   entities.AttemptTriggerModeAutomatic,
-		Event:         &entities.Event{ID: data.EventID, Data: json.RawMessage(data.Event)},
+		Event:         &entities.Event{ID: data.EventID, EventType: data.EventType, Data: json.RawMessage(data.Event), TraceParent: data.TraceParent},
 	}
 	nextAttempt.WorkspaceId = endpoint.WorkspaceId
 
@@ -492,15 +671,142 @@ func (w *Worker) handleTask(ctx context.Context, task *taskqueue.TaskMessage) er
 	if err != nil {
 		return err
 	}
+	w.tail.Publish(livetail.NewAttemptActivity(nextAttempt))
 
 	w.srv.ScheduleAttempts(ctx, []*entities.Attempt{nextAttempt})
 	return nil
 }
 
+// cancelAttempt cancels the attempt with the error code
+func (w *Worker) cancelAttempt(ctx context.Context, task *taskqueue.TaskMessage, endpoint *entities.Endpoint, code entities.AttemptErrorCode) error {
+	err := w.db.Attempts.UpdateErrorCode(ctx, task.ID, entities.AttemptStatusCanceled, code)
+	if err != nil {
+		return err
+	}
+	w.publishAttempt(task, endpoint, entities.AttemptStatusCanceled, &code, nil)
+	return nil
+}
+
+// publishAttempt publishes the attempt status change to the live tail
+func (w *Worker) publishAttempt(task *taskqueue.TaskMessage, endpoint *entities.Endpoint, status entities.AttemptStatus,
+	code *entities.AttemptErrorCode, attemptedAt *types.Time) {
+	if w.tail == nil {
+		return
+	}
+	data := task.Data.(*taskqueue.MessageData)
+	attempt := &entities.Attempt{
+		ID:            task.ID,
+		EventId:       data.EventID,
+		EndpointId:    endpoint.ID,
+		Status:        status,
+		AttemptNumber: data.Attempt,
+		ErrorCode:     code,
+		AttemptedAt:   attemptedAt,
+		Event:         &entities.Event{EventType: data.EventType},
+	}
+	attempt.WorkspaceId = endpoint.WorkspaceId
+	w.tail.Publish(livetail.NewAttemptActivity(attempt))
+}
+
+// deliver delivers the request by d with the endpoint's outbound authentication.
+// When an OAuth 2.0 token is rejected with 401, the token is refreshed and the request is delivered once more.
+func (w *Worker) deliver(ctx context.Context, d deliverer.Deliverer, cfg *entities.AuthConfig, request *deliverer.Request) *deliverer.Response {
+	if cfg != nil {
+		if err := w.authenticator.Authorize(ctx, cfg, request.Headers); err != nil {
+			return &deliverer.Response{Request: request, Error: err}
+		}
+	}
+
+	ctx, span := tracing.Start(ctx, "worker.deliver", trace.WithSpanKind(trace.SpanKindClient))
+	response := d.Deliver(ctx, request)
+	span.End()
+
+	if response.StatusCode == http.StatusUnauthorized && auth.Refreshable(cfg) {
+		w.log.Debugf("refreshing access token: %s", request.URL)
+		if err := w.authenticator.Invalidate(ctx, cfg); err != nil {
+			w.log.Errorf("failed to invalidate access token: %v", err)
+			return response
+		}
+		if err := w.authenticator.Authorize(ctx, cfg, request.Headers); err != nil {
+			return &deliverer.Response{Request: request, Error: err}
+		}
+		ctx, span := tracing.Start(ctx, "worker.deliver", trace.WithSpanKind(trace.SpanKindClient))
+		response = d.Deliver(ctx, request)
+		span.End()
+	}
+
+	return response
+}
+
+// permitTTL returns the duration for which a concurrency permit is held at most, which covers
+// the whole delivery, including the token fetch and the second delivery after a 401 response
+// when the endpoint authenticates with OAuth 2.0, and the executions of the plugins.
+func (w *Worker) permitTTL(endpoint *entities.Endpoint, plugins int) time.Duration {
+	timeout := utils.DefaultIfZero(time.Duration(endpoint.Request.Timeout)*time.Millisecond, w.opts.DeliveryTimeout)
+	if auth.Refreshable(endpoint.Request.Auth) {
+		timeout = 2 * (timeout + auth.TokenTimeout)
+	}
+	// a plugin evaluates its condition, then executes the outbound and the response phase
+	timeout += time.Duration(3*plugins) * w.opts.PluginTimeout
+	return timeout + time.Second*5
+}
+
+// statusClass returns the class of the status code, e.g. "2xx", or "none" when there is no response
+func statusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "none"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+type responsePlugin struct {
+	name     string
+	executor plugin.ResponsePlugin
+}
+
+// executeResponsePlugins executes the response phase of plugins in execution order, the plugins can override
+// the status and the error code of the attempt. As the request has been delivered, execution errors are logged
+// rather than failing the task. Successful attempts have no error code, and invalid error codes are dropped.
+func (w *Worker) executeResponsePlugins(plugins []*responsePlugin, response *deliverer.Response, result *dao.AttemptResult, pluginCtx *plugin.Context) {
+	pluginResponse := &plugin.Response{
+		StatusCode: response.StatusCode,
+		Headers:    utils.HeaderMap(response.Header),
+		Body:       string(response.ResponseBody),
+		Success:    result.Status == entities.AttemptStatusSuccess,
+		ErrorCode:  utils.PointerValue(result.ErrorCode),
+	}
+
+	for _, p := range plugins {
+		if err := p.executor.ExecuteResponse(pluginResponse, pluginCtx); err != nil {
+			if e, ok := plugin.AsLimitError(err); ok && w.metrics.Enabled {
+				w.metrics.PluginLimitExceededCounter.With("plugin", p.name, "limit", e.Limit).Add(1)
+			}
+			w.log.Warnf("failed to execute response phase of %s plugin: %v", p.name, err)
+		}
+	}
+
+	result.ErrorCode = nil
+	if pluginResponse.Success {
+		result.Status = entities.AttemptStatusSuccess
+		result.Exhausted = false
+		return
+	}
+
+	result.Status = entities.AttemptStatusFailure
+	result.Exhausted = response.ACL.Denied || response.Permanent
+	if code := pluginResponse.ErrorCode; code != "" {
+		if entities.IsValidAttemptErrorCode(code) {
+			result.ErrorCode = utils.Pointer(code)
+		} else {
+			w.log.Warnf("invalid error code set by response plugins is dropped: %q", code)
+		}
+	}
+}
+
 func buildAttemptResult(request *deliverer.Request, response *deliverer.Response) *dao.AttemptResult {
 	result := &dao.AttemptResult{
 		Request: &entities.AttemptRequest{
-			URL:    request.URL,
+			URL:    utils.StripUserinfo(request.URL),
 			Method: request.Method,
 		},
 		Status: entities.AttemptStatusSuccess,
@@ -511,37 +817,76 @@ func buildAttemptResult(request *deliverer.Request, response *deliverer.Response
 			result.ErrorCode = utils.Pointer(entities.AttemptErrorCodeTimeout)
 		} else if response.ACL.Denied {
 			result.ErrorCode = utils.Pointer(entities.AttemptErrorCodeDenied)
+		} else if errors.Is(response.Error, auth.ErrAuthentication) {
+			result.ErrorCode = utils.Pointer(entities.AttemptErrorCodeAuthFailed)
 		} else {
 			result.ErrorCode = utils.Pointer(entities.AttemptErrorCodeUnknown)
 		}
 	}
 
-	if !response.Is2xx() {
+	if !response.Success() {
 		result.Status = entities.AttemptStatusFailure
+		// failures that will never succeed are not retried
+		result.Exhausted = response.ACL.Denied || response.Permanent
 	}
 
-	if response.StatusCode != 0 {
+	if response.StatusCode != 0 || response.Error == nil {
 		result.Response = &entities.AttemptResponse{
-			Status:  response.StatusCode,
-			Latency: response.Latancy.Milliseconds(),
+			Status:        response.StatusCode,
+			Latency:       response.Latancy.Milliseconds(),
+			GRPCStatus:    response.GRPCStatus,
+			BodyTruncated: response.Truncated,
 		}
 	}
 
 	return result
 }
 
-func listEndpointPlugins(ctx context.Context, db *db.DB, endpointId string) ([]*entities.Plugin, error) {
-	// refactor me
-	cacheKey := constants.EndpointPluginsKey.Build(endpointId)
+// listEndpointPlugins returns the plugins to execute for the endpoint, which are resolved from the global,
+// workspace and endpoint plugins.
+func listEndpointPlugins(ctx context.Context, db *db.DB, endpoint *entities.Endpoint) ([]*entities.Plugin, error) {
+	global, err := loadPlugins(ctx, constants.GlobalPluginsKey.Build(""), func(ctx context.Context, _ string) ([]*entities.Plugin, error) {
+		return db.Plugins.ListGlobalPlugin(ctx)
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := loadPlugins(ctx, constants.WorkspacePluginsKey.Build(endpoint.WorkspaceId), db.Plugins.ListWorkspacePlugin, endpoint.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	plugins, err := loadPlugins(ctx, constants.EndpointPluginsKey.Build(endpoint.ID), db.Plugins.ListEndpointPlugin, endpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ResolvePlugins(plugin.TypeOutbound, global, workspace, plugins), nil
+}
+
+func loadPlugins(ctx context.Context, cacheKey string, fn func(ctx context.Context, id string) ([]*entities.Plugin, error), id string) ([]*entities.Plugin, error) {
 	plugins, err := mcache.Load(ctx, cacheKey, nil, func(ctx context.Context, id string) (*[]*entities.Plugin, error) {
-		plugins, err := db.Plugins.ListEndpointPlugin(ctx, id)
+		plugins, err := fn(ctx, id)
 		if err != nil {
 			return nil, err
 		}
 		return &plugins, nil
-	}, endpointId)
+	}, id)
 	if err != nil {
 		return nil, err
 	}
-	return *plugins, err
+	return *plugins, nil
+}
+
+// pluginsCacheKey returns the cache key of the plugin list that contains the plugin,
+// it returns empty for source plugins which are not executed by workers
+func pluginsCacheKey(plugin *entities.Plugin, wid string) string {
+	switch {
+	case plugin.EndpointId != nil:
+		return constants.EndpointPluginsKey.Build(*plugin.EndpointId)
+	case plugin.SourceId != nil:
+		return ""
+	case plugin.Global:
+		return constants.GlobalPluginsKey.Build("")
+	default:
+		return constants.WorkspacePluginsKey.Build(wid)
+	}
 }